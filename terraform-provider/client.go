@@ -2,14 +2,33 @@ package main
 
 import (
 	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	mathrand "math/rand"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"context"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // HTTPError represents an error from the Nixernetes API
@@ -17,51 +36,643 @@ type HTTPError struct {
 	StatusCode int
 	Body       string
 	Message    string
+
+	// Code is the machine-readable error code from the response body, if any.
+	Code string
+
+	// FieldErrors are the per-field errors of a rejected request, typically
+	// a 422 with a body like {"errors": [{"field": "replicas", "message": "..."}]}.
+	FieldErrors []ValidationError
+
+	// Maintenance is set when the API answered 503 because it is in
+	// maintenance mode. MaintenanceEnd is the estimated time it will be
+	// back, or zero when the server gave no estimate.
+	Maintenance    bool
+	MaintenanceEnd time.Time
+
+	// RequestID is the server's identifier for the failed request, taken
+	// from the X-Request-Id response header, for matching the failure
+	// with the server's logs.
+	RequestID string
 }
 
 func (e *HTTPError) Error() string {
-	return fmt.Sprintf("API error (HTTP %d): %s", e.StatusCode, e.Message)
+	if e.Maintenance {
+		return maintenanceMessage(e.MaintenanceEnd) + e.requestIDSuffix()
+	}
+	return fmt.Sprintf("API error (HTTP %d): %s%s", e.StatusCode, e.Message, e.requestIDSuffix())
+}
+
+// requestIDSuffix returns the request ID as a suffix for error messages, or
+// an empty string when the server sent none.
+func (e *HTTPError) requestIDSuffix() string {
+	if e.RequestID == "" {
+		return ""
+	}
+	return " (request ID: " + e.RequestID + ")"
+}
+
+// parseFieldErrors returns the field errors listed in the "errors" array of
+// an error response body. Entries without a message are skipped.
+func parseFieldErrors(body map[string]interface{}) []ValidationError {
+	entries, _ := body["errors"].([]interface{})
+	var fieldErrors []ValidationError
+	for _, entry := range entries {
+		e, _ := entry.(map[string]interface{})
+		message, _ := e["message"].(string)
+		if message == "" {
+			continue
+		}
+		field, _ := e["field"].(string)
+		fieldErrors = append(fieldErrors, ValidationError{Field: field, Message: message})
+	}
+	return fieldErrors
+}
+
+// fieldErrorsMessage summarizes field errors for an error response that has
+// no message of its own.
+func fieldErrorsMessage(fieldErrors []ValidationError) string {
+	messages := make([]string, len(fieldErrors))
+	for i, e := range fieldErrors {
+		messages[i] = e.Message
+		if e.Field != "" {
+			messages[i] = e.Field + ": " + e.Message
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// injectTraceContext adds the trace context of ctx, or the client's
+// TraceParent when ctx carries none, to the request headers. It does nothing
+// unless the client has a Propagator.
+func (c *NixernetesClient) injectTraceContext(ctx context.Context, header http.Header) {
+	if c.Propagator == nil {
+		return
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() && c.TraceParent.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, c.TraceParent)
+	}
+	c.Propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// traceParentFromEnv returns the trace context passed to the provider in the
+// TRACEPARENT and TRACESTATE environment variables, or an invalid span
+// context when there is none.
+func traceParentFromEnv(propagator propagation.TextMapPropagator) trace.SpanContext {
+	carrier := propagation.MapCarrier{
+		"traceparent": os.Getenv("TRACEPARENT"),
+		"tracestate":  os.Getenv("TRACESTATE"),
+	}
+	return trace.SpanContextFromContext(propagator.Extract(context.Background(), carrier))
+}
+
+// maintenanceMessage describes a maintenance window ending at end.
+func maintenanceMessage(end time.Time) string {
+	if end.IsZero() {
+		return "Nixernetes API in maintenance, no estimated end given"
+	}
+	return "Nixernetes API in maintenance, estimated back at " + end.UTC().Format(time.RFC3339)
+}
+
+// parseMaintenance reports whether a 503 response body is the API's
+// maintenance notice, e.g. {"maintenance": true, "estimated_duration": "15m"},
+// and when the maintenance is estimated to end. The duration may also be given
+// in seconds.
+func parseMaintenance(body map[string]interface{}, now time.Time) (bool, time.Time) {
+	if maintenance, _ := body["maintenance"].(bool); !maintenance {
+		return false, time.Time{}
+	}
+
+	switch d := body["estimated_duration"].(type) {
+	case string:
+		if duration, err := time.ParseDuration(d); err == nil && duration > 0 {
+			return true, now.Add(duration)
+		}
+	case float64:
+		if d > 0 {
+			return true, now.Add(time.Duration(d * float64(time.Second)))
+		}
+	}
+	return true, time.Time{}
+}
+
+// maintenanceWait returns how long to wait before retrying a request that
+// failed because the API is in maintenance, so retries back off until the
+// estimated end rather than following the default schedule. It reports false
+// for other errors or when no estimate is known.
+func maintenanceWait(err error, now time.Time) (time.Duration, bool) {
+	httpErr, ok := err.(*HTTPError)
+	if !ok || !httpErr.Maintenance || httpErr.MaintenanceEnd.IsZero() {
+		return 0, false
+	}
+	return max(httpErr.MaintenanceEnd.Sub(now), 0), true
+}
+
+// RetryPolicy controls how a request is retried after a retryable failure.
+// The zero value never retries.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+
+	// Backoff is the pause before the first retry, doubled after each
+	// retry up to MaxBackoff. Each pause is shortened by a random amount of
+	// up to half, so clients that failed together do not retry together. A
+	// maintenance response with an estimated end waits until then instead.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Retryable decides which errors are retried; nil retries the errors
+	// ValidateHTTPError reports as retryable.
+	Retryable func(error) bool
+}
+
+// Backoff settings of the provider-configured retry policy.
+const (
+	defaultRetryBackoff = time.Second
+	maxRetryBackoff     = time.Minute
+)
+
+// retryable reports whether the policy retries err.
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	_, retryable := ValidateHTTPError(err)
+	return retryable
+}
+
+// jitter shortens backoff by a random amount of up to half of it.
+func jitter(backoff time.Duration) time.Duration {
+	if backoff <= 1 {
+		return backoff
+	}
+	return backoff - time.Duration(mathrand.Int63n(int64(backoff/2)+1))
+}
+
+// nextBackoff returns the pause that follows backoff.
+func (p RetryPolicy) nextBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
 }
 
-// Post sends a POST request to the Nixernetes API
+// Post sends a POST request to the Nixernetes API, retried according to the
+// client's RetryPolicy.
 func (c *NixernetesClient) Post(ctx context.Context, endpoint string, body map[string]interface{}) (map[string]interface{}, error) {
-	return c.doRequest(ctx, "POST", endpoint, body)
+	return c.PostWithRetry(ctx, endpoint, body, c.RetryPolicy)
 }
 
-// Get sends a GET request to the Nixernetes API
+// Get sends a GET request to the Nixernetes API, retried according to the
+// client's RetryPolicy.
 func (c *NixernetesClient) Get(ctx context.Context, endpoint string) (map[string]interface{}, error) {
-	return c.doRequest(ctx, "GET", endpoint, nil)
+	return c.GetWithRetry(ctx, endpoint, c.RetryPolicy)
 }
 
-// Put sends a PUT request to the Nixernetes API
+// Put sends a PUT request to the Nixernetes API, retried according to the
+// client's RetryPolicy.
 func (c *NixernetesClient) Put(ctx context.Context, endpoint string, body map[string]interface{}) (map[string]interface{}, error) {
-	return c.doRequest(ctx, "PUT", endpoint, body)
+	return c.PutWithRetry(ctx, endpoint, body, c.RetryPolicy)
+}
+
+// Patch sends a JSON merge patch (RFC 7396) to the Nixernetes API, retried
+// according to the client's RetryPolicy. Only the fields in body change; a
+// null field is reset to the server's default.
+func (c *NixernetesClient) Patch(ctx context.Context, endpoint string, body map[string]interface{}) (map[string]interface{}, error) {
+	return c.doRequestWithRetry(ctx, c.RetryPolicy, "PATCH", endpoint, body)
+}
+
+// Head reports whether the resource at endpoint exists, sending a HEAD
+// request retried according to the client's RetryPolicy. A 404 means it
+// does not; any other error is returned.
+func (c *NixernetesClient) Head(ctx context.Context, endpoint string) (bool, error) {
+	_, err := c.doRequestRawWithRetry(ctx, c.RetryPolicy, "HEAD", endpoint, nil)
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// BatchResult is the outcome of one entry of a batch request: the entry's
+// response, or an *HTTPError describing why the server rejected it.
+type BatchResult struct {
+	Response map[string]interface{}
+	Err      error
+}
+
+// PostBatch sends bodies in a single POST request to a batch endpoint such
+// as /modules/batch, retried according to the client's RetryPolicy. The
+// server processes each entry on its own, so some may succeed while others
+// fail; the returned results are in the order of bodies. An error is only
+// returned when the request as a whole fails.
+func (c *NixernetesClient) PostBatch(ctx context.Context, endpoint string, bodies []map[string]interface{}) ([]BatchResult, error) {
+	items := make([]interface{}, len(bodies))
+	for i, body := range bodies {
+		items[i] = body
+	}
+	response, err := c.Post(ctx, endpoint, map[string]interface{}{"items": items})
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := listFromResponse(response, "results")
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != len(bodies) {
+		return nil, fmt.Errorf("batch response has %d results for %d requests", len(entries), len(bodies))
+	}
+
+	results := make([]BatchResult, len(entries))
+	for i, entry := range entries {
+		status, _ := entry["status"].(float64)
+		if status >= 200 && status < 300 {
+			result, _ := entry["response"].(map[string]interface{})
+			if result == nil {
+				result = map[string]interface{}{}
+			}
+			results[i].Response = result
+			continue
+		}
+
+		httpErr := &HTTPError{StatusCode: int(status), Message: "batch entry failed"}
+		if errResp, ok := entry["error"].(map[string]interface{}); ok {
+			httpErr.Code, _ = errResp["code"].(string)
+			if msg, ok := errResp["message"].(string); ok {
+				httpErr.Message = msg
+			}
+		}
+		results[i].Err = httpErr
+	}
+	return results, nil
+}
+
+// PostWithRetry sends a POST request, retried according to policy. A POST
+// is not idempotent, so it is only re-sent when it carries an idempotency key.
+func (c *NixernetesClient) PostWithRetry(ctx context.Context, endpoint string, body map[string]interface{}, policy RetryPolicy) (map[string]interface{}, error) {
+	return c.doRequestWithRetry(ctx, policy, "POST", endpoint, body)
+}
+
+// GetWithRetry sends a GET request, retried according to policy.
+func (c *NixernetesClient) GetWithRetry(ctx context.Context, endpoint string, policy RetryPolicy) (map[string]interface{}, error) {
+	return c.doRequestWithRetry(ctx, policy, "GET", endpoint, nil)
+}
+
+// PutWithRetry sends a PUT request, retried according to policy.
+func (c *NixernetesClient) PutWithRetry(ctx context.Context, endpoint string, body map[string]interface{}, policy RetryPolicy) (map[string]interface{}, error) {
+	return c.doRequestWithRetry(ctx, policy, "PUT", endpoint, body)
+}
+
+// doRequestInto sends a request, retried according to the client's
+// RetryPolicy, and decodes the JSON response into a T. Unlike the map-based
+// methods, a field of an unexpected type is reported as an error rather than
+// left to a type assertion. An empty response gives the zero T.
+func doRequestInto[T any](ctx context.Context, c *NixernetesClient, method string, endpoint string, body map[string]interface{}) (T, error) {
+	var result T
+	respBody, err := c.doRequestRawWithRetry(ctx, c.RetryPolicy, method, endpoint, body)
+	if err != nil {
+		return result, err
+	}
+	err = c.decodeResponse(ctx, endpoint, respBody, &result)
+	return result, err
+}
+
+// doRequestWithRetry performs a request, repeating it after retryable
+// failures until policy gives up or ctx is done.
+func (c *NixernetesClient) doRequestWithRetry(ctx context.Context, policy RetryPolicy, method string, endpoint string, body map[string]interface{}) (map[string]interface{}, error) {
+	respBody, err := c.doRequestRawWithRetry(ctx, policy, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeMapResponse(ctx, endpoint, respBody)
+}
+
+// doRequestRawWithRetry is doRequestWithRetry returning the undecoded
+// response body. Requests that are not idempotent are sent once, unless they
+// carry an idempotency key for the server to recognise the retry.
+func (c *NixernetesClient) doRequestRawWithRetry(ctx context.Context, policy RetryPolicy, method string, endpoint string, body map[string]interface{}) ([]byte, error) {
+	_, hasKey := ctx.Value(idempotencyKeyContextKey{}).(string)
+	retrySafe := isIdempotentMethod(method) || hasKey
+	backoff := policy.Backoff
+	staleRetried := false
+	for attempt := 0; ; attempt++ {
+		result, err := c.doRequestRaw(ctx, method, endpoint, body)
+		if err != nil && !staleRetried && ctx.Err() == nil && isIdempotentMethod(method) && isStaleConnError(err) {
+			// A connection dropped while idle, e.g. by a load balancer, fails
+			// the first request sent over it. Retrying once on a fresh
+			// connection does not count against the policy.
+			tflog.Debug(ctx, "Retrying API request on a new connection", map[string]any{
+				"method": method,
+				"url":    c.redact(endpoint),
+				"error":  err.Error(),
+			})
+			staleRetried = true
+			attempt--
+			continue
+		}
+		if err == nil || !retrySafe || attempt >= policy.MaxRetries || ctx.Err() != nil || !policy.retryable(err) {
+			return result, err
+		}
+
+		wait := jitter(backoff)
+		if w, ok := maintenanceWait(err, time.Now()); ok {
+			wait = w
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// The retry could not be sent before the deadline; report the
+			// failure now instead of sleeping until the context expires.
+			return result, err
+		}
+		tflog.Warn(ctx, "Retrying API request", map[string]any{
+			"method":  method,
+			"url":     c.redact(endpoint),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+			"error":   c.redact(err.Error()),
+		})
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+		backoff = policy.nextBackoff(backoff)
+	}
+}
+
+// isStaleConnError reports whether err is the connection failure seen when
+// the server or a proxy closed a connection the client was about to reuse.
+func isStaleConnError(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// isIdempotentMethod reports whether repeating a request with method has
+// the same effect as sending it once. POST is excluded as a retried create
+// could create a second object; a PATCH is a merge patch, which is idempotent.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case "GET", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+// Default API base paths of each resource type.
+const (
+	defaultConfigsPath  = "/configs"
+	defaultModulesPath  = "/modules"
+	defaultProjectsPath = "/projects"
+)
+
+// configsPath returns the API base path for configurations.
+func (c *NixernetesClient) configsPath() string {
+	if c.ConfigsPath != "" {
+		return c.ConfigsPath
+	}
+	return defaultConfigsPath
+}
+
+// modulesPath returns the API base path for modules.
+func (c *NixernetesClient) modulesPath() string {
+	if c.ModulesPath != "" {
+		return c.ModulesPath
+	}
+	return defaultModulesPath
+}
+
+// projectsPath returns the API base path for projects.
+func (c *NixernetesClient) projectsPath() string {
+	if c.ProjectsPath != "" {
+		return c.ProjectsPath
+	}
+	return defaultProjectsPath
+}
+
+// requestURL joins the client endpoint, APIPrefix and endpoint, a path
+// rooted at "/", so that a prefix of "/api/v1", "/api/v1/" or "api/v1"
+// turns "/configs" into "<Endpoint>/api/v1/configs".
+func (c *NixernetesClient) requestURL(endpoint string) string {
+	base := strings.TrimSuffix(c.Endpoint, "/")
+	if prefix := strings.Trim(c.APIPrefix, "/"); prefix != "" {
+		base += "/" + prefix
+	}
+	return base + endpoint
+}
+
+// mergePatchContentType is the media type of PATCH request bodies, which are
+// JSON merge patches.
+const mergePatchContentType = "application/merge-patch+json"
+
+// updateMethods are the HTTP methods accepted for the provider update_method setting.
+var updateMethods = []string{"PUT", "POST", "PATCH"}
+
+// isValidUpdateMethod reports whether method is one of updateMethods.
+func isValidUpdateMethod(method string) bool {
+	for _, m := range updateMethods {
+		if method == m {
+			return true
+		}
+	}
+	return false
 }
 
-// Delete sends a DELETE request to the Nixernetes API
+// Update sends a request modifying an existing object using the configured
+// update method, defaulting to PUT.
+func (c *NixernetesClient) Update(ctx context.Context, endpoint string, body map[string]interface{}) (map[string]interface{}, error) {
+	method := c.UpdateMethod
+	if method == "" {
+		method = "PUT"
+	}
+	return c.doRequestWithRetry(ctx, c.RetryPolicy, method, endpoint, body)
+}
+
+// UpdateChanges updates the object at endpoint from the prior request body
+// to the planned one. By default only the changes are sent, as a merge patch,
+// so fields the server manages are left alone; a configured update_method
+// sends the planned body in full instead.
+func (c *NixernetesClient) UpdateChanges(ctx context.Context, endpoint string, prior, planned map[string]interface{}) (map[string]interface{}, error) {
+	if c.UpdateMethod != "" {
+		return c.Update(ctx, endpoint, planned)
+	}
+	return c.Patch(ctx, endpoint, mergePatch(prior, planned))
+}
+
+// mergePatch returns the JSON merge patch that turns prior into planned:
+// the fields that changed, nested objects patched field by field, and null
+// for the fields planned leaves out.
+func mergePatch(prior, planned map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+	for key, value := range planned {
+		old, ok := prior[key]
+		if ok && reflect.DeepEqual(old, value) {
+			continue
+		}
+		oldObject, oldIsObject := old.(map[string]interface{})
+		object, isObject := value.(map[string]interface{})
+		if oldIsObject && isObject {
+			patch[key] = mergePatch(oldObject, object)
+			continue
+		}
+		patch[key] = value
+	}
+	for key := range prior {
+		if _, ok := planned[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
+
+// conflictDependentsDeleting is the error code of a 409 returned while the
+// object's dependents are still being deleted. Any other 409 is permanent.
+const conflictDependentsDeleting = "dependents_deleting"
+
+// deleteConflictRetries bounds how often a delete is retried after a
+// transient dependency conflict, and deleteConflictBackoff is the first pause,
+// doubled after each attempt.
+var (
+	deleteConflictRetries = 5
+	deleteConflictBackoff = 2 * time.Second
+)
+
+// Delete sends a DELETE request to the Nixernetes API, retried according to
+// the client's RetryPolicy. A 404 is treated as success, since it means an
+// earlier attempt already deleted the resource. A 409 caused by dependents
+// that are still being deleted, as when Terraform deletes a config and its
+// modules in the same apply, is retried with backoff.
 func (c *NixernetesClient) Delete(ctx context.Context, endpoint string) error {
-	_, err := c.doRequest(ctx, "DELETE", endpoint, nil)
-	return err
+	return c.DeleteWithRetry(ctx, endpoint, c.RetryPolicy)
+}
+
+// DeleteWithRetry is Delete with failed requests retried according to policy.
+func (c *NixernetesClient) DeleteWithRetry(ctx context.Context, endpoint string, policy RetryPolicy) error {
+	// Every attempt carries the same key, so the server can tell a retry from
+	// a new delete.
+	ctx = context.WithValue(ctx, idempotencyKeyContextKey{}, newIdempotencyKey())
+	backoff := deleteConflictBackoff
+	for attempt := 0; ; attempt++ {
+		_, err := c.doRequestWithRetry(ctx, policy, "DELETE", endpoint, nil)
+		httpErr, ok := err.(*HTTPError)
+		if ok && httpErr.StatusCode == 404 {
+			tflog.Debug(ctx, "Resource already deleted", map[string]any{"endpoint": endpoint})
+			return nil
+		}
+		if !ok || !isTransientConflict(httpErr) || attempt >= deleteConflictRetries {
+			return err
+		}
+
+		tflog.Info(ctx, "Waiting for dependents to be deleted", map[string]any{
+			"endpoint": endpoint,
+			"attempt":  attempt + 1,
+			"backoff":  backoff.String(),
+		})
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientConflict reports whether err is a 409 that resolves by itself
+// once the object's dependents finish deleting.
+func isTransientConflict(err *HTTPError) bool {
+	return err.StatusCode == 409 && err.Code == conflictDependentsDeleting
+}
+
+// requestIDHeader carries the server's identifier for a request. Header
+// lookups are case-insensitive, so X-Request-ID matches as well.
+const requestIDHeader = "X-Request-Id"
+
+// decodeMapResponse decodes a response body into a map; an empty body gives
+// an empty map.
+func (c *NixernetesClient) decodeMapResponse(ctx context.Context, endpoint string, respBody []byte) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := c.decodeResponse(ctx, endpoint, respBody, &result); err != nil {
+		return nil, err
+	}
+	if len(respBody) == 0 {
+		result = make(map[string]interface{})
+	}
+	return result, nil
+}
+
+// decodeResponse decodes the JSON response body of a request to endpoint
+// into v, ignoring any trailing data. An empty body leaves v unchanged.
+func (c *NixernetesClient) decodeResponse(ctx context.Context, endpoint string, respBody []byte, v interface{}) error {
+	if len(respBody) == 0 {
+		return nil
+	}
+	trailing, err := decodeFirstJSON(respBody, v)
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if trailing > 0 {
+		tflog.Debug(ctx, "Ignoring trailing data after JSON response", map[string]any{
+			"url":            c.redact(endpoint),
+			"trailing_bytes": trailing,
+		})
+	}
+	return nil
 }
 
-// doRequest performs the actual HTTP request
-func (c *NixernetesClient) doRequest(ctx context.Context, method string, endpoint string, body map[string]interface{}) (map[string]interface{}, error) {
+// doRequestRaw sends a single request and returns the body of a successful
+// response, which may be empty.
+func (c *NixernetesClient) doRequestRaw(ctx context.Context, method string, endpoint string, body map[string]interface{}) (_ []byte, err error) {
+	// Wait before starting the clock, so the request log reports the
+	// request's own duration.
+	if c.Limiter != nil {
+		if err := c.Limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("waiting for request rate limit: %w", err)
+		}
+	}
+	// Held until the response body is read, but not across retry delays.
+	if c.Concurrency != nil {
+		if err := c.Concurrency.Acquire(ctx, 1); err != nil {
+			return nil, fmt.Errorf("waiting for a free request slot: %w", err)
+		}
+		defer c.Concurrency.Release(1)
+	}
+
 	// Build the URL
-	url := fmt.Sprintf("%s%s", strings.TrimSuffix(c.Endpoint, "/"), endpoint)
+	url := c.requestURL(endpoint)
+
+	start := time.Now()
+	var status int
+	var requestID string
+	defer func() {
+		if logErr := c.logRequest(method, endpoint, start, status, requestID, err); logErr != nil {
+			tflog.Warn(ctx, "Failed to write request log", map[string]any{
+				"error": logErr.Error(),
+			})
+		}
+	}()
 
 	tflog.Debug(ctx, "Making API request", map[string]any{
 		"method": method,
-		"url":    url,
+		"url":    c.redact(url),
 	})
 
 	// Create request
 	var reqBody io.Reader
+	var jsonBody []byte
+	var requestBytes int
+	var bodyMD5 string
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		reqBody = bytes.NewBuffer(jsonBody)
+		requestBytes = len(jsonBody)
+		if c.SendContentMD5 {
+			bodyMD5 = contentMD5(jsonBody)
+		}
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
@@ -70,38 +681,99 @@ func (c *NixernetesClient) doRequest(ctx context.Context, method string, endpoin
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
+	if method == "PATCH" {
+		req.Header.Set("Content-Type", mergePatchContentType)
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "terraform-provider-nixernetes/1.0")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	} else {
+		req.Header.Set("User-Agent", userAgentProduct)
+	}
+	if bodyMD5 != "" {
+		req.Header.Set("Content-MD5", bodyMD5)
+	}
+
+	// Deletes carry an idempotency key so the server can recognise a retry
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	c.injectTraceContext(ctx, req.Header)
 
 	// Set authentication
 	if c.Username != "" && c.Password != "" {
 		req.SetBasicAuth(c.Username, c.Password)
 	}
 
+	if c.DebugHTTP {
+		tflog.Trace(ctx, "API request", map[string]any{
+			"method":  method,
+			"url":     c.redact(url),
+			"headers": redactHeaders(req.Header),
+			"body":    c.debugPayload(jsonBody),
+		})
+	}
+
 	// Send request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.getHTTPClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
+	status = resp.StatusCode
+	requestID = resp.Header.Get(requestIDHeader)
 
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	c.metrics.record(requestBytes, len(respBody))
+
+	if c.DebugHTTP {
+		tflog.Trace(ctx, "API response", map[string]any{
+			"status_code": resp.StatusCode,
+			"headers":     redactHeaders(resp.Header),
+			"body":        c.debugPayload(respBody),
+		})
+	}
+
+	// A body altered in transit must not be mistaken for the API's answer
+	if want := resp.Header.Get("Content-MD5"); c.SendContentMD5 && want != "" {
+		if got := contentMD5(respBody); got != want {
+			tflog.Error(ctx, "API response failed integrity check", map[string]any{
+				"url":            c.redact(url),
+				"content_md5":    want,
+				"computed_md5":   got,
+				"response_bytes": len(respBody),
+			})
+			return nil, fmt.Errorf("response body does not match its Content-MD5 header (got %s, computed %s); it was probably corrupted in transit", want, got)
+		}
+	}
 
 	// Check for error responses
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		var errMsg string
 		var errResp map[string]interface{}
-		if err := json.Unmarshal(respBody, &errResp); err == nil {
+		var code string
+		var fieldErrors []ValidationError
+		var maintenance bool
+		var maintenanceEnd time.Time
+		if _, err := decodeFirstJSON(respBody, &errResp); err == nil {
+			if resp.StatusCode == http.StatusServiceUnavailable {
+				maintenance, maintenanceEnd = parseMaintenance(errResp, time.Now())
+			}
+			code, _ = errResp["code"].(string)
+			fieldErrors = parseFieldErrors(errResp)
 			if msg, ok := errResp["message"]; ok {
 				errMsg = fmt.Sprintf("%v", msg)
 			} else if msg, ok := errResp["error"]; ok {
 				errMsg = fmt.Sprintf("%v", msg)
+			} else if len(fieldErrors) > 0 {
+				errMsg = fieldErrorsMessage(fieldErrors)
 			}
 		}
 		if errMsg == "" {
@@ -109,32 +781,754 @@ func (c *NixernetesClient) doRequest(ctx context.Context, method string, endpoin
 		}
 
 		tflog.Error(ctx, "API request failed", map[string]any{
-			"status_code": resp.StatusCode,
-			"error":       errMsg,
+			"status_code":    resp.StatusCode,
+			"request_id":     requestID,
+			"error":          c.redact(errMsg),
+			"request_bytes":  requestBytes,
+			"response_bytes": len(respBody),
 		})
 
+		if maintenance {
+			tflog.Warn(ctx, "API is in maintenance", map[string]any{
+				"estimated_end": maintenanceEnd.UTC().Format(time.RFC3339),
+			})
+		}
+
 		return nil, &HTTPError{
-			StatusCode: resp.StatusCode,
-			Body:       string(respBody),
-			Message:    errMsg,
+			StatusCode:     resp.StatusCode,
+			Body:           string(respBody),
+			Message:        errMsg,
+			Code:           code,
+			FieldErrors:    fieldErrors,
+			Maintenance:    maintenance,
+			MaintenanceEnd: maintenanceEnd,
+			RequestID:      requestID,
 		}
 	}
 
-	// Parse response
-	var result map[string]interface{}
-	if len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, &result); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
-		}
-	} else {
-		result = make(map[string]interface{})
+	// An auth proxy answering for the API serves its login page with a 200
+	if isHTMLResponse(resp.Header.Get("Content-Type"), respBody) {
+		tflog.Error(ctx, "API returned an HTML page instead of JSON", map[string]any{
+			"status_code": resp.StatusCode,
+			"url":         c.redact(url),
+		})
+		return nil, fmt.Errorf("received an HTML page instead of a JSON API response (HTTP %d). "+
+			"The session or credentials are probably invalid, or the endpoint is behind an authentication proxy (such as SSO) "+
+			"that served its login page; check the provider endpoint and credentials", resp.StatusCode)
 	}
 
 	tflog.Debug(ctx, "API request successful", map[string]any{
-		"status_code": resp.StatusCode,
-		"method":      method,
-		"url":         url,
+		"status_code":    resp.StatusCode,
+		"request_id":     requestID,
+		"method":         method,
+		"url":            c.redact(url),
+		"request_bytes":  requestBytes,
+		"response_bytes": len(respBody),
 	})
 
-	return result, nil
+	return respBody, nil
+}
+
+// contentMD5 returns the Content-MD5 header value for a body: the base64
+// encoding of its MD5 digest, as defined in RFC 1864.
+func contentMD5(body []byte) string {
+	sum := md5.Sum(body)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// ClientMetrics is a snapshot of the cumulative traffic sent through a client.
+type ClientMetrics struct {
+	Requests      int64
+	RequestBytes  int64
+	ResponseBytes int64
+}
+
+// clientMetrics holds the running counters behind ClientMetrics. The counters
+// are atomic so concurrent resource operations can share one client.
+type clientMetrics struct {
+	requests      atomic.Int64
+	requestBytes  atomic.Int64
+	responseBytes atomic.Int64
+}
+
+func (m *clientMetrics) record(requestBytes, responseBytes int) {
+	m.requests.Add(1)
+	m.requestBytes.Add(int64(requestBytes))
+	m.responseBytes.Add(int64(responseBytes))
+}
+
+// Metrics returns the number of requests that received a response and the
+// total request and response body bytes exchanged so far.
+func (c *NixernetesClient) Metrics() ClientMetrics {
+	return ClientMetrics{
+		Requests:      c.metrics.requests.Load(),
+		RequestBytes:  c.metrics.requestBytes.Load(),
+		ResponseBytes: c.metrics.responseBytes.Load(),
+	}
+}
+
+// getHTTPClient returns the HTTP client shared by all requests, building it
+// on first use so the transport's connections are reused.
+func (c *NixernetesClient) getHTTPClient() *http.Client {
+	c.httpClientOnce.Do(func() {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.ResponseHeaderTimeout = c.ResponseHeaderTimeout
+		if c.KeepAlive > 0 || c.DialTimeout > 0 {
+			dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+			if c.KeepAlive > 0 {
+				dialer.KeepAlive = c.KeepAlive
+			}
+			if c.DialTimeout > 0 {
+				dialer.Timeout = c.DialTimeout
+			}
+			transport.DialContext = dialer.DialContext
+		}
+		// All requests go to one host, so the per-host limit is the one
+		// that matters; the default of 2 is easily exceeded by parallel
+		// refreshes.
+		if c.MaxIdleConns > 0 {
+			transport.MaxIdleConns = max(transport.MaxIdleConns, c.MaxIdleConns)
+			transport.MaxIdleConnsPerHost = c.MaxIdleConns
+		}
+		if c.IdleConnTimeout > 0 {
+			transport.IdleConnTimeout = c.IdleConnTimeout
+		}
+		if c.MaxConnLifetime > 0 && c.MaxConnLifetime < transport.IdleConnTimeout {
+			transport.IdleConnTimeout = c.MaxConnLifetime
+		}
+		if c.RootCAs != nil || c.InsecureSkipVerify || c.ClientCertificate != nil {
+			transport.TLSClientConfig = &tls.Config{RootCAs: c.RootCAs, InsecureSkipVerify: c.InsecureSkipVerify}
+			if c.ClientCertificate != nil {
+				transport.TLSClientConfig.Certificates = []tls.Certificate{*c.ClientCertificate}
+			}
+		}
+		if len(c.TLSInsecureHosts) > 0 {
+			transport.DialTLSContext = dialTLSSkippingHosts(transport.DialContext, transport.TLSClientConfig, c.TLSInsecureHosts)
+		}
+		c.httpClient = &http.Client{Transport: transport, Timeout: c.Timeout}
+	})
+	c.sweepConnections()
+	return c.httpClient
+}
+
+// dialTLSSkippingHosts returns a DialTLSContext function that verifies
+// server certificates according to base, which may be nil, except for
+// connections to the given hosts.
+func dialTLSSkippingHosts(dial func(ctx context.Context, network, addr string) (net.Conn, error), base *tls.Config, hosts []string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	insecure := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		insecure[strings.ToLower(host)] = true
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		config := &tls.Config{}
+		if base != nil {
+			config = base.Clone()
+		}
+		config.ServerName = host
+		config.InsecureSkipVerify = config.InsecureSkipVerify || insecure[strings.ToLower(host)]
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
+
+// sweepConnections closes the client's idle connections once every
+// MaxConnLifetime, so that connections a load balancer silently dropped are
+// not reused indefinitely. Connections in use are closed by a later sweep.
+func (c *NixernetesClient) sweepConnections() {
+	if c.MaxConnLifetime <= 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	last := c.lastConnSweep.Load()
+	if now-last < int64(c.MaxConnLifetime) || !c.lastConnSweep.CompareAndSwap(last, now) {
+		return
+	}
+	c.httpClient.CloseIdleConnections()
+}
+
+// getString returns the string field key of m; ok is false when the field is
+// missing, null or not a string.
+func getString(m map[string]interface{}, key string) (string, bool) {
+	s, ok := m[key].(string)
+	return s, ok
+}
+
+// requireStrings returns the string fields keys of m by key, or an error
+// naming every one that is missing, null or not a string. It lets a
+// malformed response fail with a diagnostic instead of a panic.
+func requireStrings(m map[string]interface{}, keys ...string) (map[string]string, error) {
+	fields := make(map[string]string, len(keys))
+	var missing []string
+	for _, key := range keys {
+		s, ok := getString(m, key)
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		fields[key] = s
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("response is missing the string field(s) %s", strings.Join(missing, ", "))
+	}
+	return fields, nil
+}
+
+// decodeFirstJSON decodes the first JSON document in body into v and returns
+// the number of bytes that follow it, ignoring whitespace. Some endpoints
+// occasionally send a second document after the first; only the first is used.
+func decodeFirstJSON(body []byte, v interface{}) (int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err := decoder.Decode(v); err != nil {
+		return 0, err
+	}
+	rest := bytes.TrimSpace(body[decoder.InputOffset():])
+	return len(rest), nil
+}
+
+// idempotencyKeyContextKey is the context key of the Idempotency-Key sent
+// with each attempt of a logical request.
+type idempotencyKeyContextKey struct{}
+
+// newIdempotencyKey returns a random key identifying one logical request.
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// isHTMLResponse reports whether a response is an HTML document, judging by
+// its content type or, when that is missing or generic, by its first bytes.
+func isHTMLResponse(contentType string, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "text/html", "application/xhtml+xml":
+			return true
+		case "application/json":
+			return false
+		}
+	}
+
+	start := strings.ToLower(strings.TrimSpace(string(body[:min(len(body), 512)])))
+	return strings.HasPrefix(start, "<!doctype html") || strings.HasPrefix(start, "<html")
+}
+
+// ModuleEvent is a Kubernetes event recorded for a module.
+type ModuleEvent struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Timestamp string `json:"timestamp"`
+	Count     int64  `json:"count"`
+}
+
+// GetModuleEvents fetches the events recorded for a module, newest first.
+// When since is non-empty only events at or after that RFC 3339 timestamp are returned.
+func (c *NixernetesClient) GetModuleEvents(ctx context.Context, moduleID string, since string) ([]ModuleEvent, error) {
+	var sinceTime time.Time
+	endpoint := c.modulesPath() + "/" + url.PathEscape(moduleID) + "/events"
+	if since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since timestamp %q: %w", since, err)
+		}
+		sinceTime = parsed
+		endpoint += "?since=" + url.QueryEscape(since)
+	}
+
+	response, err := c.Get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-encode the generic response so the events decode into typed values
+	raw, err := json.Marshal(response["events"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse events: %w", err)
+	}
+	var events []ModuleEvent
+	if err := json.Unmarshal(raw, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse events: %w", err)
+	}
+
+	// Older servers ignore the since parameter, so filter here as well
+	if since != "" {
+		filtered := events[:0]
+		for _, event := range events {
+			ts, err := time.Parse(time.RFC3339, event.Timestamp)
+			if err != nil || !ts.Before(sinceTime) {
+				filtered = append(filtered, event)
+			}
+		}
+		events = filtered
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		ti, erri := time.Parse(time.RFC3339, events[i].Timestamp)
+		tj, errj := time.Parse(time.RFC3339, events[j].Timestamp)
+		if erri != nil || errj != nil {
+			return events[i].Timestamp > events[j].Timestamp
+		}
+		return ti.After(tj)
+	})
+
+	return events, nil
+}
+
+// moduleResponse holds the top-level fields of a GET /modules/{id} response.
+// Raw is the whole response, for the nested blocks and the generation. The
+// required fields are pointers so a response that leaves one out is caught.
+type moduleResponse struct {
+	Name           *string `json:"name"`
+	Image          *string `json:"image"`
+	Namespace      *string `json:"namespace"`
+	Replicas       *int64  `json:"replicas"`
+	PausedReplicas *int64  `json:"paused_replicas"`
+	ProjectID      *string `json:"project_id"`
+	CreatedAt      *string `json:"created_at"`
+
+	Raw map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the typed fields and keeps the whole response in Raw.
+func (m *moduleResponse) UnmarshalJSON(data []byte) error {
+	type fields moduleResponse
+	if err := json.Unmarshal(data, (*fields)(m)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &m.Raw)
+}
+
+// missing returns the names of the required fields the response left out.
+func (m *moduleResponse) missing() []string {
+	var missing []string
+	if m.Name == nil {
+		missing = append(missing, "name")
+	}
+	if m.Image == nil {
+		missing = append(missing, "image")
+	}
+	if m.Namespace == nil {
+		missing = append(missing, "namespace")
+	}
+	if m.Replicas == nil {
+		missing = append(missing, "replicas")
+	}
+	return missing
+}
+
+// ModuleStatus is the runtime status of a module.
+type ModuleStatus struct {
+	Phase              string `json:"phase"`
+	ReadyReplicas      int64  `json:"ready_replicas"`
+	AvailableReplicas  int64  `json:"available_replicas"`
+	LastTransitionTime string `json:"last_transition_time"`
+	Message            string `json:"message"`
+	RestartCount       int64  `json:"restart_count"`
+	LastRestartReason  string `json:"last_restart_reason"`
+}
+
+// Ready reports whether the module has reached the Ready phase.
+func (s *ModuleStatus) Ready() bool {
+	return strings.EqualFold(s.Phase, "ready")
+}
+
+// GetModuleStatus fetches the runtime status of a module.
+func (c *NixernetesClient) GetModuleStatus(ctx context.Context, moduleID string) (*ModuleStatus, error) {
+	response, err := c.Get(ctx, c.modulesPath()+"/"+url.PathEscape(moduleID)+"/status")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse status: %w", err)
+	}
+	var status ModuleStatus
+	if err := json.Unmarshal(raw, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// ModuleImpact describes what depends on a running module, as reported by
+// the API's impact endpoint.
+type ModuleImpact struct {
+	ReadyReplicas     int64    `json:"ready_replicas"`
+	ActiveConnections int64    `json:"active_connections"`
+	RequestsPerMinute int64    `json:"requests_per_minute"`
+	Dependents        []string `json:"dependents"`
+}
+
+// GetModuleImpact fetches what would be affected if a module were removed.
+// The endpoint is read-only and optional; servers without it return 404.
+func (c *NixernetesClient) GetModuleImpact(ctx context.Context, moduleID string) (*ModuleImpact, error) {
+	response, err := c.Get(ctx, c.modulesPath()+"/"+url.PathEscape(moduleID)+"/impact")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse impact: %w", err)
+	}
+	var impact ModuleImpact
+	if err := json.Unmarshal(raw, &impact); err != nil {
+		return nil, fmt.Errorf("failed to parse impact: %w", err)
+	}
+
+	return &impact, nil
+}
+
+// Capabilities describes limits and features the API server advertises.
+// Zero values mean the server does not advertise the setting.
+type Capabilities struct {
+	MaxConfigSize int64 `json:"max_config_size"`
+}
+
+// FetchCapabilities reads the capabilities the API server advertises. It
+// returns nil capabilities and no error when the server does not publish any.
+func (c *NixernetesClient) FetchCapabilities(ctx context.Context) (*Capabilities, error) {
+	response, err := c.Get(ctx, "/capabilities")
+	if httpErr, ok := err.(*HTTPError); ok {
+		switch httpErr.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			tflog.Debug(ctx, "API server does not advertise capabilities", map[string]any{"status_code": httpErr.StatusCode})
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse capabilities: %w", err)
+	}
+	var capabilities Capabilities
+	if err := json.Unmarshal(raw, &capabilities); err != nil {
+		return nil, fmt.Errorf("failed to parse capabilities: %w", err)
+	}
+
+	return &capabilities, nil
+}
+
+// FetchConfigSchema downloads the JSON schema the API server validates
+// configurations against. It returns a nil schema and no error when the
+// server does not publish one.
+func (c *NixernetesClient) FetchConfigSchema(ctx context.Context) (*jsonschema.Schema, error) {
+	response, err := c.Get(ctx, c.configsPath()+"/schema")
+	if httpErr, ok := err.(*HTTPError); ok {
+		switch httpErr.StatusCode {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			tflog.Debug(ctx, "API server does not publish a configuration schema", map[string]any{"status_code": httpErr.StatusCode})
+			return nil, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return compileConfigSchema(response)
+}
+
+// moduleReadyPollInterval is the pause between status checks while waiting for a module.
+var moduleReadyPollInterval = 5 * time.Second
+
+// ReadyTimeoutError is returned by WaitForModuleReady when the module does not
+// become ready in time.
+type ReadyTimeoutError struct {
+	Timeout time.Duration
+	Status  *ModuleStatus
+}
+
+func (e *ReadyTimeoutError) Error() string {
+	phase := "unknown"
+	if e.Status != nil {
+		phase = e.Status.Phase
+	}
+	return fmt.Sprintf("module not ready after %s (phase %s)", e.Timeout, phase)
+}
+
+// WaitForModuleReady polls the module's status until it is ready, fails, or
+// timeout elapses, in which case a *ReadyTimeoutError is returned.
+func (c *NixernetesClient) WaitForModuleReady(ctx context.Context, moduleID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := c.GetModuleStatus(ctx, moduleID)
+		if err != nil {
+			return err
+		}
+		if status.Ready() {
+			return nil
+		}
+		if strings.EqualFold(status.Phase, "failed") {
+			return fmt.Errorf("module failed: %s", status.Message)
+		}
+
+		tflog.Debug(ctx, "Waiting for module to become ready", map[string]any{
+			"id":             moduleID,
+			"phase":          status.Phase,
+			"ready_replicas": status.ReadyReplicas,
+		})
+
+		if time.Now().Add(moduleReadyPollInterval).After(deadline) {
+			return &ReadyTimeoutError{Timeout: timeout, Status: status}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(moduleReadyPollInterval):
+		}
+	}
+}
+
+// BuildOperation is a configuration build started through the API.
+type BuildOperation struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Log    string `json:"log"`
+}
+
+// Done reports whether the build has finished, successfully or not.
+func (o *BuildOperation) Done() bool {
+	return o.Succeeded() || strings.EqualFold(o.Status, "failed")
+}
+
+// Succeeded reports whether the build finished successfully.
+func (o *BuildOperation) Succeeded() bool {
+	return strings.EqualFold(o.Status, "succeeded")
+}
+
+// buildLogTailLines is how many trailing lines of a failed build's log are reported.
+const buildLogTailLines = 20
+
+// BuildFailedError is returned by WaitForDryBuild when the build fails.
+type BuildFailedError struct {
+	Operation *BuildOperation
+}
+
+func (e *BuildFailedError) Error() string {
+	return fmt.Sprintf("build %s failed", e.Operation.ID)
+}
+
+// LogTail returns the last lines of the build log.
+func (e *BuildFailedError) LogTail() string {
+	lines := strings.Split(strings.TrimRight(e.Operation.Log, "\n"), "\n")
+	if len(lines) > buildLogTailLines {
+		lines = lines[len(lines)-buildLogTailLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildPollInterval is the pause between status checks while waiting for a build.
+var buildPollInterval = 5 * time.Second
+
+// StartDryBuild asks the API server to build a configuration without
+// deploying it.
+func (c *NixernetesClient) StartDryBuild(ctx context.Context, configID string) (*BuildOperation, error) {
+	response, err := c.Post(ctx, c.configsPath()+"/"+url.PathEscape(configID)+"/build?dry_run=true", map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	return buildOperationFromResponse(response)
+}
+
+// GetBuild fetches the current state of a build operation.
+func (c *NixernetesClient) GetBuild(ctx context.Context, configID, operationID string) (*BuildOperation, error) {
+	response, err := c.Get(ctx, c.configsPath()+"/"+url.PathEscape(configID)+"/builds/"+url.PathEscape(operationID))
+	if err != nil {
+		return nil, err
+	}
+	return buildOperationFromResponse(response)
+}
+
+func buildOperationFromResponse(response map[string]interface{}) (*BuildOperation, error) {
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse build: %w", err)
+	}
+	var op BuildOperation
+	if err := json.Unmarshal(raw, &op); err != nil {
+		return nil, fmt.Errorf("failed to parse build: %w", err)
+	}
+	if op.ID == "" {
+		return nil, fmt.Errorf("build response did not include an id")
+	}
+	return &op, nil
+}
+
+// WaitForDryBuild starts a dry build of a configuration and polls it until it
+// finishes or timeout elapses. A failed build is returned as a
+// *BuildFailedError.
+func (c *NixernetesClient) WaitForDryBuild(ctx context.Context, configID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	op, err := c.StartDryBuild(ctx, configID)
+	for {
+		if err != nil {
+			return err
+		}
+		if op.Succeeded() {
+			return nil
+		}
+		if op.Done() {
+			return &BuildFailedError{Operation: op}
+		}
+
+		tflog.Debug(ctx, "Waiting for configuration build", map[string]any{
+			"id":        configID,
+			"operation": op.ID,
+			"status":    op.Status,
+		})
+
+		if time.Now().Add(buildPollInterval).After(deadline) {
+			return fmt.Errorf("build %s did not finish within %s (status %s)", op.ID, timeout, op.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(buildPollInterval):
+		}
+
+		op, err = c.GetBuild(ctx, configID, op.ID)
+	}
+}
+
+// deletionPollInterval is the pause between existence checks while waiting for a deletion.
+var deletionPollInterval = 5 * time.Second
+
+// WaitForDeletion polls endpoint until the API reports it as not found, ctx is
+// cancelled, or timeout elapses.
+func (c *NixernetesClient) WaitForDeletion(ctx context.Context, endpoint string, timeout time.Duration) error {
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	for {
+		response, err := c.Get(ctx, endpoint)
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		fields := map[string]any{
+			"endpoint": endpoint,
+			"elapsed":  time.Since(start).Round(time.Second).String(),
+		}
+		if remaining, ok := response["module_count"].(float64); ok {
+			fields["remaining_modules"] = int64(remaining)
+		}
+		tflog.Info(ctx, "Waiting for deletion to complete", fields)
+
+		if time.Now().Add(deletionPollInterval).After(deadline) {
+			return fmt.Errorf("%s still exists after %s", endpoint, timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(deletionPollInterval):
+		}
+	}
+}
+
+// ProjectChild is a module or configuration that belongs to a project.
+type ProjectChild struct {
+	ID   string
+	Name string
+}
+
+// ProjectChildren lists what belongs to a project.
+type ProjectChildren struct {
+	Modules []ProjectChild
+	Configs []ProjectChild
+}
+
+// GetProjectChildren lists the modules and configurations of a project.
+func (c *NixernetesClient) GetProjectChildren(ctx context.Context, projectID string) (*ProjectChildren, error) {
+	modules, err := c.Get(ctx, c.modulesPath()+"?project_id="+url.QueryEscape(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list modules: %w", err)
+	}
+	configs, err := c.Get(ctx, c.configsPath()+"?project_id="+url.QueryEscape(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configurations: %w", err)
+	}
+
+	return &ProjectChildren{
+		// Older servers ignore the project_id parameter. Modules report their
+		// project, so filtering them here is enough.
+		Modules: projectChildrenFromList(filterByProject(modules["modules"], projectID), projectID, false),
+		// Configurations that do not report a project cannot be told apart
+		// from those of other projects, so only matching ones are kept.
+		Configs: projectChildrenFromList(configs["configs"], projectID, true),
+	}, nil
+}
+
+// projectChildrenFromList converts listed items into project children. When
+// strict is set, items must report projectID as their project_id.
+func projectChildrenFromList(items interface{}, projectID string, strict bool) []ProjectChild {
+	list, _ := items.([]interface{})
+	children := []ProjectChild{}
+	for _, item := range list {
+		m, _ := item.(map[string]interface{})
+		id, _ := m["id"].(string)
+		if id == "" {
+			continue
+		}
+		if owner, _ := m["project_id"].(string); strict && owner != projectID {
+			continue
+		}
+		name, _ := m["name"].(string)
+		children = append(children, ProjectChild{ID: id, Name: name})
+	}
+	return children
+}
+
+// ModuleCost is the estimated running cost of a module.
+type ModuleCost struct {
+	MonthlyCost float64 `json:"monthly_cost"`
+	CPUCost     float64 `json:"cpu_cost"`
+	MemoryCost  float64 `json:"memory_cost"`
+	Currency    string  `json:"currency"`
+}
+
+// GetModuleCost fetches the estimated cost of a module. The endpoint is
+// optional; servers without cost estimates return 404.
+func (c *NixernetesClient) GetModuleCost(ctx context.Context, moduleID string) (*ModuleCost, error) {
+	response, err := c.Get(ctx, c.modulesPath()+"/"+url.PathEscape(moduleID)+"/cost")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cost: %w", err)
+	}
+	var cost ModuleCost
+	if err := json.Unmarshal(raw, &cost); err != nil {
+		return nil, fmt.Errorf("failed to parse cost: %w", err)
+	}
+
+	return &cost, nil
 }
@@ -2,10 +2,31 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
 )
 
 func TestPostRequest(t *testing.T) {
@@ -90,6 +111,42 @@ func TestGetRequest(t *testing.T) {
 	}
 }
 
+func TestUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		providerVersion, terraformVersion string
+		want                              string
+	}{
+		{"1.2.3", "1.7.0", "terraform-provider-nixernetes/1.2.3 (+terraform 1.7.0)"},
+		{"dev", "", "terraform-provider-nixernetes/dev"},
+		{"", "1.7.0", "terraform-provider-nixernetes (+terraform 1.7.0)"},
+	}
+	for _, tt := range tests {
+		client := &NixernetesClient{Endpoint: server.URL, UserAgent: userAgent(tt.providerVersion, tt.terraformVersion)}
+		if _, err := client.Get(context.Background(), "/configs"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Expected User-Agent %q, got %q", tt.want, got)
+		}
+	}
+
+	client := &NixernetesClient{Endpoint: server.URL}
+	if _, err := client.Get(context.Background(), "/configs"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != userAgentProduct {
+		t.Errorf("Expected default User-Agent %q, got %q", userAgentProduct, got)
+	}
+}
+
 func TestPutRequest(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "PUT" {
@@ -123,6 +180,141 @@ func TestPutRequest(t *testing.T) {
 	}
 }
 
+func TestPatchRequest(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" {
+			t.Errorf("Expected PATCH method, got %s", r.Method)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/merge-patch+json" {
+			t.Errorf("Expected merge patch content type, got %q", got)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+	if _, err := client.Patch(context.Background(), "/modules/mod-1", map[string]interface{}{"replicas": 3}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 1 || sent["replicas"] != float64(3) {
+		t.Errorf("Expected body {replicas: 3}, got %v", sent)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	prior := map[string]interface{}{
+		"name":     "api",
+		"replicas": int64(2),
+		"labels":   map[string]interface{}{"team": "payments", "tier": "web"},
+		"ports":    []interface{}{int64(80)},
+		"platform": "linux/amd64",
+	}
+	planned := map[string]interface{}{
+		"name":     "api",
+		"replicas": int64(3),
+		"labels":   map[string]interface{}{"team": "payments", "tier": "api"},
+		"ports":    []interface{}{int64(80), int64(443)},
+	}
+
+	want := map[string]interface{}{
+		"replicas": int64(3),
+		"labels":   map[string]interface{}{"tier": "api"},
+		"ports":    []interface{}{int64(80), int64(443)},
+		"platform": nil,
+	}
+	if got := mergePatch(prior, planned); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected patch %v, got %v", want, got)
+	}
+	if got := mergePatch(prior, prior); len(got) != 0 {
+		t.Errorf("Expected an empty patch for an unchanged body, got %v", got)
+	}
+}
+
+func TestUpdateMethod(t *testing.T) {
+	tests := []struct {
+		updateMethod string
+		expected     string
+	}{
+		{"", "PUT"},
+		{"POST", "POST"},
+		{"PATCH", "PATCH"},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != tt.expected {
+				t.Errorf("UpdateMethod %q: expected %s method, got %s", tt.updateMethod, tt.expected, r.Method)
+			}
+			w.Write([]byte(`{}`))
+		}))
+
+		client := &NixernetesClient{Endpoint: server.URL, UpdateMethod: tt.updateMethod}
+		if _, err := client.Update(context.Background(), "/configs/config-123", map[string]interface{}{"name": "updated-config"}); err != nil {
+			t.Errorf("UpdateMethod %q: unexpected error: %v", tt.updateMethod, err)
+		}
+		server.Close()
+	}
+}
+
+func TestAPIPaths(t *testing.T) {
+	client := &NixernetesClient{}
+	if got := client.configsPath() + client.modulesPath() + client.projectsPath(); got != "/configs/modules/projects" {
+		t.Errorf("Unexpected default paths: %s", got)
+	}
+
+	client = &NixernetesClient{ConfigsPath: "/v2/configurations", ModulesPath: "/v2/modules", ProjectsPath: "/v2/projects"}
+	if got := client.configsPath() + client.modulesPath() + client.projectsPath(); got != "/v2/configurations/v2/modules/v2/projects" {
+		t.Errorf("Unexpected configured paths: %s", got)
+	}
+}
+
+func TestAPIPrefix(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		endpoint, prefix string
+		want             string
+	}{
+		{server.URL, "", "/configs"},
+		{server.URL, "/api/v1", "/api/v1/configs"},
+		{server.URL, "/api/v1/", "/api/v1/configs"},
+		{server.URL, "api/v1", "/api/v1/configs"},
+		{server.URL + "/", "/api/v1/", "/api/v1/configs"},
+		{server.URL, "/", "/configs"},
+	}
+	for _, tt := range tests {
+		client := &NixernetesClient{Endpoint: tt.endpoint, APIPrefix: tt.prefix}
+		if _, err := client.Get(context.Background(), client.configsPath()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Prefix %q: expected request to %s, got %s", tt.prefix, tt.want, got)
+		}
+	}
+}
+
+func TestIsValidUpdateMethod(t *testing.T) {
+	for _, method := range []string{"PUT", "POST", "PATCH"} {
+		if !isValidUpdateMethod(method) {
+			t.Errorf("isValidUpdateMethod(%q) = false, want true", method)
+		}
+	}
+	for _, method := range []string{"", "GET", "DELETE", "put"} {
+		if isValidUpdateMethod(method) {
+			t.Errorf("isValidUpdateMethod(%q) = true, want false", method)
+		}
+	}
+}
+
 func TestDeleteRequest(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "DELETE" {
@@ -144,6 +336,244 @@ func TestDeleteRequest(t *testing.T) {
 	}
 }
 
+func TestHeadRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" {
+			t.Errorf("Expected HEAD method, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/configs/config-123":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+		case "/configs/config-404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	exists, err := client.Head(context.Background(), "/configs/config-123")
+	if err != nil || !exists {
+		t.Errorf("Expected config-123 to exist, got %v, %v", exists, err)
+	}
+
+	exists, err = client.Head(context.Background(), "/configs/config-404")
+	if err != nil || exists {
+		t.Errorf("Expected config-404 not to exist, got %v, %v", exists, err)
+	}
+
+	if _, err := client.Head(context.Background(), "/configs/broken"); err == nil {
+		t.Error("Expected an error for a 500 response")
+	}
+}
+
+func TestHTTPErrorRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-7f3a")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"message": "invalid name"})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	_, err := client.Get(context.Background(), "/configs/config-123")
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected an HTTPError, got %v", err)
+	}
+	if httpErr.RequestID != "req-7f3a" {
+		t.Errorf("Expected request ID req-7f3a, got %q", httpErr.RequestID)
+	}
+	if want := "API error (HTTP 400): invalid name (request ID: req-7f3a)"; httpErr.Error() != want {
+		t.Errorf("Expected error %q, got %q", want, httpErr.Error())
+	}
+
+	if got := (&HTTPError{StatusCode: 400, Message: "invalid name"}).Error(); strings.Contains(got, "request ID") {
+		t.Errorf("Expected no request ID without the header, got %q", got)
+	}
+}
+
+func TestHTTPErrorFieldErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []map[string]string{
+				{"field": "replicas", "message": "must not exceed 50"},
+				{"field": "image", "message": "repository not found"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL, RetryPolicy: RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond}}
+
+	_, err := client.Post(context.Background(), "/modules", map[string]interface{}{"name": "api"})
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected an HTTPError, got %v", err)
+	}
+	want := []ValidationError{
+		{Field: "replicas", Message: "must not exceed 50"},
+		{Field: "image", Message: "repository not found"},
+	}
+	if !reflect.DeepEqual(httpErr.FieldErrors, want) {
+		t.Errorf("Expected field errors %v, got %v", want, httpErr.FieldErrors)
+	}
+	if want := "replicas: must not exceed 50; image: repository not found"; httpErr.Message != want {
+		t.Errorf("Expected message %q, got %q", want, httpErr.Message)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected a 422 not to be retried, got %d requests", got)
+	}
+}
+
+func TestPostBatch(t *testing.T) {
+	var results []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/modules/batch" {
+			t.Errorf("Expected POST /modules/batch, got %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Items []map[string]interface{} `json:"items"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Items) != 2 || body.Items[1]["name"] != "worker" {
+			t.Errorf("Expected the two bodies as items, got %v", body.Items)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+	bodies := []map[string]interface{}{{"name": "api"}, {"name": "worker"}}
+
+	t.Run("all succeed", func(t *testing.T) {
+		results = []map[string]interface{}{
+			{"status": 201, "response": map[string]interface{}{"id": "module-1"}},
+			{"status": 201, "response": map[string]interface{}{"id": "module-2"}},
+		}
+		got, err := client.PostBatch(context.Background(), "/modules/batch", bodies)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(got) != 2 || got[0].Err != nil || got[1].Err != nil || got[1].Response["id"] != "module-2" {
+			t.Errorf("Expected two created modules, got %+v", got)
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		results = []map[string]interface{}{
+			{"status": 201, "response": map[string]interface{}{"id": "module-1"}},
+			{"status": 409, "error": map[string]interface{}{"code": "name_taken", "message": "module worker already exists"}},
+		}
+		got, err := client.PostBatch(context.Background(), "/modules/batch", bodies)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got[0].Err != nil || got[0].Response["id"] != "module-1" {
+			t.Errorf("Expected the first module created, got %+v", got[0])
+		}
+		httpErr, ok := got[1].Err.(*HTTPError)
+		if !ok || httpErr.StatusCode != 409 || httpErr.Code != "name_taken" || httpErr.Message != "module worker already exists" {
+			t.Errorf("Expected a 409 name_taken error for the second module, got %v", got[1].Err)
+		}
+	})
+
+	t.Run("result count mismatch", func(t *testing.T) {
+		results = []map[string]interface{}{
+			{"status": 201, "response": map[string]interface{}{"id": "module-1"}},
+		}
+		if _, err := client.PostBatch(context.Background(), "/modules/batch", bodies); err == nil {
+			t.Error("Expected an error for a missing result")
+		}
+	})
+}
+
+func TestDeleteRetryAfterTimeout(t *testing.T) {
+	deleted := make(chan struct{})
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		mu.Unlock()
+		select {
+		case <-deleted:
+			// Already removed by the first attempt
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": "config not found"})
+		default:
+			// The server completes the delete, but too slowly for the client
+			close(deleted)
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := client.Delete(ctx, "/configs/config-123"); err == nil {
+		t.Fatal("Expected first delete to time out")
+	}
+
+	if err := client.Delete(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Expected retried delete to succeed on 404, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(keys) != 2 || keys[0] == "" || keys[1] == "" {
+		t.Errorf("Expected an idempotency key on every delete, got %q", keys)
+	}
+}
+
+func TestDeleteRetriesReuseIdempotencyKey(t *testing.T) {
+	defer func(backoff time.Duration) { deleteConflictBackoff = backoff }(deleteConflictBackoff)
+	deleteConflictBackoff = time.Millisecond
+
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		switch len(keys) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": conflictDependentsDeleting})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL, RetryPolicy: RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}}
+	if err := client.Delete(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(keys) != 3 || keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("Expected every attempt of one delete to send the same idempotency key, got %q", keys)
+	}
+
+	if err := client.Delete(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if keys[3] == keys[0] {
+		t.Errorf("Expected a new idempotency key for a new delete, got %q twice", keys[0])
+	}
+}
+
 func TestErrorHandling(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -174,45 +604,1415 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
-func TestAuthenticationFailure(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": "Unauthorized",
-		})
+func TestTLSInsecureHosts(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123"}`))
 	}))
 	defer server.Close()
 
-	client := &NixernetesClient{
-		Endpoint: server.URL,
-		Username: "wronguser",
-		Password: "wrongpass",
+	// The test server's self-signed certificate is rejected for other hosts
+	strict := &NixernetesClient{Endpoint: server.URL, TLSInsecureHosts: []string{"selfsigned.example.com"}}
+	if _, err := strict.Get(context.Background(), "/configs/config-123"); err == nil {
+		t.Fatal("Expected certificate verification error")
 	}
 
-	_, err := client.Get(context.Background(), "/configs")
+	// and accepted for its own host
+	insecure := &NixernetesClient{Endpoint: server.URL, TLSInsecureHosts: []string{"127.0.0.1"}}
+	if _, err := insecure.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestTLSCACertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	defer server.Close()
+
+	// The test server's certificate is not trusted by default
+	untrusted := &NixernetesClient{Endpoint: server.URL}
+	if _, err := untrusted.Get(context.Background(), "/configs/config-123"); err == nil {
+		t.Fatal("Expected certificate verification error")
+	}
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	rootCAs, err := loadCACertificate(string(caPEM))
+	if err != nil {
+		t.Fatalf("Unexpected error loading CA: %v", err)
+	}
+	trusted := &NixernetesClient{Endpoint: server.URL, RootCAs: rootCAs}
+	if _, err := trusted.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error with CA certificate: %v", err)
+	}
+
+	// The CA also applies to connections made for tls_insecure_hosts
+	mixed := &NixernetesClient{Endpoint: server.URL, RootCAs: rootCAs, TLSInsecureHosts: []string{"selfsigned.example.com"}}
+	if _, err := mixed.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error with CA certificate and insecure hosts: %v", err)
+	}
+
+	insecure := &NixernetesClient{Endpoint: server.URL, InsecureSkipVerify: true}
+	if _, err := insecure.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error with insecure_skip_verify: %v", err)
+	}
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	certPEM, keyPEM := testClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(certPEM)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	// The gateway rejects connections without a client certificate
+	anonymous := &NixernetesClient{Endpoint: server.URL, InsecureSkipVerify: true}
+	if _, err := anonymous.Get(context.Background(), "/configs/config-123"); err == nil {
+		t.Fatal("Expected the server to require a client certificate")
+	}
+
+	cert, err := loadClientCertificate(string(certPEM), string(keyPEM))
+	if err != nil {
+		t.Fatalf("Unexpected error loading client certificate: %v", err)
+	}
+	authenticated := &NixernetesClient{Endpoint: server.URL, InsecureSkipVerify: true, ClientCertificate: &cert}
+	if _, err := authenticated.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error with client certificate: %v", err)
+	}
+
+	// The certificate is also presented on connections made for tls_insecure_hosts
+	mixed := &NixernetesClient{Endpoint: server.URL, TLSInsecureHosts: []string{"127.0.0.1"}, ClientCertificate: &cert}
+	if _, err := mixed.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error with client certificate and insecure hosts: %v", err)
+	}
+}
+
+// testClientCertificate returns a self-signed client certificate and its
+// private key, PEM-encoded.
+func testClientCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestResponseHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := &NixernetesClient{Endpoint: server.URL, ResponseHeaderTimeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	_, err := client.Get(context.Background(), "/configs/slow")
 	if err == nil {
-		t.Error("Expected authentication error")
+		t.Fatal("Expected error when the server never starts responding")
+	}
+	if !strings.Contains(err.Error(), "timeout awaiting response headers") {
+		t.Errorf("Expected response header timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected request to fail fast, took %v", elapsed)
 	}
 }
 
-func TestContextCancellation(t *testing.T) {
+func TestRequestTimeout(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Simulate slow response
-		select {}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`{"id":"config-123"}`))
 	}))
 	defer server.Close()
 
-	client := &NixernetesClient{
-		Endpoint: server.URL,
-		Username: "testuser",
-		Password: "testpass",
+	// Unlike response_header_timeout, timeout also covers a slow body
+	client := &NixernetesClient{Endpoint: server.URL, Timeout: 50 * time.Millisecond}
+	if _, err := client.Get(context.Background(), "/configs/config-123"); err == nil {
+		t.Fatal("Expected timeout error")
 	}
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel() // Cancel immediately
+func TestResponseHeaderTimeoutSlowBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
 
-	_, err := client.Get(ctx, "/configs")
-	if err == nil {
-		t.Error("Expected context cancellation error")
+		// The body takes longer than the header timeout to arrive
+		time.Sleep(150 * time.Millisecond)
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL, ResponseHeaderTimeout: 50 * time.Millisecond}
+
+	result, err := client.Get(context.Background(), "/configs/config-123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result["id"] != "config-123" {
+		t.Errorf("Expected id config-123, got %v", result["id"])
+	}
+}
+
+func TestMaintenanceResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"maintenance":        true,
+			"estimated_duration": "30m",
+			"message":            "Scheduled database upgrade",
+		})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL, Username: "testuser", Password: "testpass"}
+
+	before := time.Now()
+	_, err := client.Get(context.Background(), "/configs/config-123")
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		t.Fatalf("Expected HTTPError, got %T: %v", err, err)
+	}
+	if !httpErr.Maintenance {
+		t.Fatal("Expected maintenance to be detected")
+	}
+	if end := httpErr.MaintenanceEnd; end.Before(before.Add(30*time.Minute)) || end.After(time.Now().Add(30*time.Minute)) {
+		t.Errorf("Expected maintenance to end in 30 minutes, got %v", end)
+	}
+	if !strings.HasPrefix(err.Error(), "Nixernetes API in maintenance, estimated back at ") {
+		t.Errorf("Unexpected error message: %q", err.Error())
+	}
+
+	wait, ok := maintenanceWait(err, before)
+	if !ok || wait < 30*time.Minute {
+		t.Errorf("Expected to wait at least 30 minutes, got %v (%v)", wait, ok)
+	}
+}
+
+func TestParseMaintenance(t *testing.T) {
+	now := time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		body            map[string]interface{}
+		wantMaintenance bool
+		wantEnd         time.Time
+	}{
+		{"duration string", map[string]interface{}{"maintenance": true, "estimated_duration": "15m"}, true, now.Add(15 * time.Minute)},
+		{"duration seconds", map[string]interface{}{"maintenance": true, "estimated_duration": float64(90)}, true, now.Add(90 * time.Second)},
+		{"no estimate", map[string]interface{}{"maintenance": true}, true, time.Time{}},
+		{"invalid estimate", map[string]interface{}{"maintenance": true, "estimated_duration": "soon"}, true, time.Time{}},
+		{"not maintenance", map[string]interface{}{"maintenance": false, "estimated_duration": "15m"}, false, time.Time{}},
+		{"plain 503", map[string]interface{}{"error": "overloaded"}, false, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maintenance, end := parseMaintenance(tt.body, now)
+			if maintenance != tt.wantMaintenance || !end.Equal(tt.wantEnd) {
+				t.Errorf("parseMaintenance() = %v, %v, want %v, %v", maintenance, end, tt.wantMaintenance, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWait(t *testing.T) {
+	now := time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)
+
+	if wait, ok := maintenanceWait(&HTTPError{StatusCode: 503, Maintenance: true, MaintenanceEnd: now.Add(time.Minute)}, now); !ok || wait != time.Minute {
+		t.Errorf("Expected to wait 1m, got %v (%v)", wait, ok)
+	}
+	if wait, ok := maintenanceWait(&HTTPError{StatusCode: 503, Maintenance: true, MaintenanceEnd: now.Add(-time.Minute)}, now); !ok || wait != 0 {
+		t.Errorf("Expected no wait once the estimate has passed, got %v (%v)", wait, ok)
+	}
+	if _, ok := maintenanceWait(&HTTPError{StatusCode: 503, Maintenance: true}, now); ok {
+		t.Error("Expected no maintenance wait without an estimate")
+	}
+	if _, ok := maintenanceWait(&HTTPError{StatusCode: 503}, now); ok {
+		t.Error("Expected no maintenance wait for a plain 503")
+	}
+}
+
+func TestConcatenatedJSONResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123","name":"web"}{"id":"config-456","name":"api"}`))
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	result, err := client.Get(context.Background(), "/configs/config-123")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result["id"] != "config-123" || result["name"] != "web" {
+		t.Errorf("Expected the first document, got %v", result)
+	}
+}
+
+func TestDecodeFirstJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantTrailing int
+		wantErr      bool
+	}{
+		{"single document", `{"id":"a"}`, 0, false},
+		{"trailing whitespace", "{\"id\":\"a\"}\n", 0, false},
+		{"two documents", `{"id":"a"} {"id":"b"}`, 10, false},
+		{"trailing garbage", `{"id":"a"}xyz`, 3, false},
+		{"invalid", `{"id":`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v map[string]interface{}
+			trailing, err := decodeFirstJSON([]byte(tt.body), &v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeFirstJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if trailing != tt.wantTrailing {
+				t.Errorf("decodeFirstJSON() trailing = %d, want %d", trailing, tt.wantTrailing)
+			}
+			if !tt.wantErr && v["id"] != "a" {
+				t.Errorf("Expected first document, got %v", v)
+			}
+		})
+	}
+}
+
+func TestAuthenticationFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Unauthorized",
+		})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{
+		Endpoint: server.URL,
+		Username: "wronguser",
+		Password: "wrongpass",
+	}
+
+	_, err := client.Get(context.Background(), "/configs")
+	if err == nil {
+		t.Error("Expected authentication error")
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Simulate slow response
+		select {}
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{
+		Endpoint: server.URL,
+		Username: "testuser",
+		Password: "testpass",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // Cancel immediately
+
+	_, err := client.Get(ctx, "/configs")
+	if err == nil {
+		t.Error("Expected context cancellation error")
+	}
+}
+
+func TestGetModuleEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/modules/mod-123/events" {
+			t.Errorf("Expected request to /modules/mod-123/events, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("since") != "2024-02-04T00:00:00Z" {
+			t.Errorf("Expected since query parameter, got %q", r.URL.RawQuery)
+		}
+
+		// Deliberately unordered and including an event older than since
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"events": []map[string]interface{}{
+				{"type": "Normal", "reason": "Pulled", "message": "Pulled image", "timestamp": "2024-02-04T00:01:00Z", "count": 1},
+				{"type": "Normal", "reason": "Scheduled", "message": "Assigned to node", "timestamp": "2024-02-03T23:59:00Z", "count": 1},
+				{"type": "Warning", "reason": "BackOff", "message": "Back-off restarting failed container", "timestamp": "2024-02-04T00:05:00Z", "count": 4},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{
+		Endpoint: server.URL,
+		Username: "testuser",
+		Password: "testpass",
+	}
+
+	events, err := client.GetModuleEvents(context.Background(), "mod-123", "2024-02-04T00:00:00Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events after since filter, got %d", len(events))
+	}
+	if events[0].Reason != "BackOff" || events[1].Reason != "Pulled" {
+		t.Errorf("Expected events newest first, got %q then %q", events[0].Reason, events[1].Reason)
+	}
+	if events[0].Count != 4 {
+		t.Errorf("Expected count 4, got %d", events[0].Count)
+	}
+}
+
+func TestGetModuleEventsInvalidSince(t *testing.T) {
+	client := &NixernetesClient{Endpoint: "http://127.0.0.1:0"}
+
+	_, err := client.GetModuleEvents(context.Background(), "mod-123", "yesterday")
+	if err == nil {
+		t.Error("Expected error for invalid since timestamp")
+	}
+}
+
+func TestWaitForModuleReady(t *testing.T) {
+	moduleReadyPollInterval = time.Millisecond
+	defer func() { moduleReadyPollInterval = 5 * time.Second }()
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/modules/mod-123/status" {
+			t.Errorf("Expected path /modules/mod-123/status, got %s", r.URL.Path)
+		}
+		polls++
+		phase := "Pending"
+		if polls >= 3 {
+			phase = "Ready"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"phase": phase, "ready_replicas": polls - 1})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	if err := client.WaitForModuleReady(context.Background(), "mod-123", time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if polls != 3 {
+		t.Errorf("Expected 3 status polls, got %d", polls)
+	}
+}
+
+func TestWaitForModuleReadyTimeout(t *testing.T) {
+	moduleReadyPollInterval = time.Millisecond
+	defer func() { moduleReadyPollInterval = 5 * time.Second }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"phase": "Pending"})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	err := client.WaitForModuleReady(context.Background(), "mod-123", 20*time.Millisecond)
+	if _, ok := err.(*ReadyTimeoutError); !ok {
+		t.Fatalf("Expected *ReadyTimeoutError, got %v", err)
+	}
+}
+
+func TestWaitForModuleReadyFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"phase": "Failed", "message": "ImagePullBackOff"})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	err := client.WaitForModuleReady(context.Background(), "mod-123", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "ImagePullBackOff") {
+		t.Errorf("Expected failure mentioning ImagePullBackOff, got %v", err)
+	}
+}
+
+func TestWaitForDryBuild(t *testing.T) {
+	buildPollInterval = time.Millisecond
+	defer func() { buildPollInterval = 5 * time.Second }()
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/configs/config-1/build":
+			if r.URL.Query().Get("dry_run") != "true" {
+				t.Errorf("Expected dry_run=true, got %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "op-1", "status": "pending"})
+		case r.Method == http.MethodGet && r.URL.Path == "/configs/config-1/builds/op-1":
+			polls++
+			status := "running"
+			if polls >= 2 {
+				status = "succeeded"
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "op-1", "status": status})
+		default:
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	if err := client.WaitForDryBuild(context.Background(), "config-1", time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if polls != 2 {
+		t.Errorf("Expected 2 build polls, got %d", polls)
+	}
+}
+
+func TestWaitForDryBuildFailed(t *testing.T) {
+	var log strings.Builder
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&log, "line %d\n", i)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "op-1", "status": "failed", "log": log.String()})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	err := client.WaitForDryBuild(context.Background(), "config-1", time.Minute)
+	buildErr, ok := err.(*BuildFailedError)
+	if !ok {
+		t.Fatalf("Expected *BuildFailedError, got %v", err)
+	}
+	tail := buildErr.LogTail()
+	if !strings.HasPrefix(tail, "line 11\n") || !strings.HasSuffix(tail, "line 30") {
+		t.Errorf("Expected the last 20 log lines, got %q", tail)
+	}
+}
+
+func TestWaitForDryBuildTimeout(t *testing.T) {
+	buildPollInterval = time.Millisecond
+	defer func() { buildPollInterval = 5 * time.Second }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "op-1", "status": "running"})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	err := client.WaitForDryBuild(context.Background(), "config-1", 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "did not finish") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestWaitForDeletionTimeout(t *testing.T) {
+	deletionPollInterval = time.Millisecond
+	defer func() { deletionPollInterval = 5 * time.Second }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "proj-1"})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	if err := client.WaitForDeletion(context.Background(), "/projects/proj-1", 20*time.Millisecond); err == nil {
+		t.Error("Expected timeout error while the project still exists")
+	}
+}
+
+func TestClientMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL, Username: "testuser", Password: "testpass"}
+
+	if _, err := client.Post(context.Background(), "/configs", map[string]interface{}{"name": "web"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Get(context.Background(), "/configs/missing"); err == nil {
+		t.Fatal("Expected error for missing config")
+	}
+
+	metrics := client.Metrics()
+	if metrics.Requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", metrics.Requests)
+	}
+	if want := int64(len(`{"name":"web"}`)); metrics.RequestBytes != want {
+		t.Errorf("Expected %d request bytes, got %d", want, metrics.RequestBytes)
+	}
+	if want := int64(len(`{"id":"config-123"}`) + len(`{"error":"not found"}`)); metrics.ResponseBytes != want {
+		t.Errorf("Expected %d response bytes, got %d", want, metrics.ResponseBytes)
+	}
+}
+
+func TestHTMLLoginPageResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("<!DOCTYPE html><html><body><form action=\"/sso/login\"></form></body></html>"))
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL, Username: "testuser", Password: "testpass"}
+
+	_, err := client.Get(context.Background(), "/modules")
+	if err == nil {
+		t.Fatal("Expected error for HTML response")
+	}
+	if !strings.Contains(err.Error(), "authentication proxy") {
+		t.Errorf("Expected error to mention an authentication proxy, got %q", err)
+	}
+}
+
+func TestIsHTMLResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        bool
+	}{
+		{"html content type", "text/html; charset=utf-8", "<p>hi</p>", true},
+		{"xhtml content type", "application/xhtml+xml", "", true},
+		{"json content type", "application/json", `{"id": "1"}`, false},
+		{"sniffed html", "", "\n  <!doctype html><html></html>", true},
+		{"plain text json", "text/plain; charset=utf-8", `{"id": "1"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHTMLResponse(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("isHTMLResponse(%q, %q) = %v, want %v", tt.contentType, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteTransientConflict(t *testing.T) {
+	defer func(backoff time.Duration) { deleteConflictBackoff = backoff }(deleteConflictBackoff)
+	deleteConflictBackoff = time.Millisecond
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": "config still has modules being deleted",
+				"code":  "dependents_deleting",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+	if err := client.Delete(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Expected delete to succeed once dependents are gone, got %v", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestDeletePermanentConflict(t *testing.T) {
+	defer func(backoff time.Duration) { deleteConflictBackoff = backoff }(deleteConflictBackoff)
+	deleteConflictBackoff = time.Millisecond
+
+	tests := []struct {
+		name         string
+		code         string
+		wantAttempts int32
+	}{
+		{"dependents exist", "dependents_exist", 1},
+		{"no code", "", 1},
+		{"dependents never finish", "dependents_deleting", int32(deleteConflictRetries) + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": "config is referenced by modules",
+					"code":  tt.code,
+				})
+			}))
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL}
+			err := client.Delete(context.Background(), "/configs/config-123")
+			httpErr, ok := err.(*HTTPError)
+			if !ok || httpErr.StatusCode != http.StatusConflict {
+				t.Fatalf("Expected 409 HTTPError, got %v", err)
+			}
+			if httpErr.Code != tt.code {
+				t.Errorf("Expected code %q, got %q", tt.code, httpErr.Code)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.wantAttempts, got)
+			}
+		})
+	}
+}
+
+func TestContentMD5(t *testing.T) {
+	// Well-known MD5 digest of the empty body
+	if got, want := contentMD5(nil), "1B2M2Y8AsgTpgAmY7PhCfg=="; got != want {
+		t.Errorf("contentMD5(empty) = %q, want %q", got, want)
+	}
+	if got, want := contentMD5([]byte(`{"name":"web"}`)), contentMD5([]byte(`{"name":"web"}`)); got != want {
+		t.Errorf("Expected contentMD5 to be deterministic, got %q and %q", got, want)
+	}
+}
+
+func TestRequestContentMD5(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"enabled", true},
+		{"disabled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header string
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Get("Content-MD5")
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-123"})
+			}))
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL, SendContentMD5: tt.enabled}
+			if _, err := client.Post(context.Background(), "/configs", map[string]interface{}{"name": "web"}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			switch {
+			case tt.enabled && header != contentMD5(body):
+				t.Errorf("Expected Content-MD5 %q for body %s, got %q", contentMD5(body), body, header)
+			case !tt.enabled && header != "":
+				t.Errorf("Expected no Content-MD5 header, got %q", header)
+			}
+		})
+	}
+}
+
+func TestResponseContentMD5(t *testing.T) {
+	const body = `{"id":"config-123"}`
+	tests := []struct {
+		name    string
+		header  string
+		enabled bool
+		wantErr bool
+	}{
+		{"matching", contentMD5([]byte(body)), true, false},
+		{"mismatch", contentMD5([]byte(`{"id":"config-456"}`)), true, true},
+		{"absent", "", true, false},
+		{"mismatch not checked when disabled", contentMD5([]byte("other")), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("Content-MD5", tt.header)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL, SendContentMD5: tt.enabled}
+			result, err := client.Get(context.Background(), "/configs/config-123")
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "Content-MD5") {
+					t.Errorf("Expected Content-MD5 mismatch error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result["id"] != "config-123" {
+				t.Errorf("Expected id config-123, got %v", result["id"])
+			}
+		})
+	}
+}
+
+func TestFetchCapabilities(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *Capabilities
+		wantErr bool
+	}{
+		{"advertised", http.StatusOK, `{"max_config_size": 262144}`, &Capabilities{MaxConfigSize: 262144}, false},
+		{"nothing advertised", http.StatusOK, `{}`, &Capabilities{}, false},
+		{"not published", http.StatusNotFound, `{"error": "not found"}`, nil, false},
+		{"server error", http.StatusInternalServerError, `{"error": "boom"}`, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/capabilities" {
+					t.Errorf("Expected request to /capabilities, got %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL}
+			capabilities, err := client.FetchCapabilities(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(capabilities, tt.want) {
+				t.Errorf("Expected capabilities %+v, got %+v", tt.want, capabilities)
+			}
+		})
+	}
+}
+
+func TestFetchConfigSchema(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantSchema bool
+		wantErr    bool
+	}{
+		{"published", http.StatusOK, `{"type": "object"}`, true, false},
+		{"not published", http.StatusNotFound, `{"error": "not found"}`, false, false},
+		{"not implemented", http.StatusNotImplemented, `{"error": "not implemented"}`, false, false},
+		{"server error", http.StatusInternalServerError, `{"error": "boom"}`, false, true},
+		{"invalid schema", http.StatusOK, `{"type": 42}`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/configurations/schema" {
+					t.Errorf("Expected request to /v2/configurations/schema, got %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL, ConfigsPath: "/v2/configurations"}
+			schema, err := client.FetchConfigSchema(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if (schema != nil) != tt.wantSchema {
+				t.Errorf("Expected schema %v, got %v", tt.wantSchema, schema)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       RetryPolicy
+		statuses     []int
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{"no retries by default", RetryPolicy{}, []int{503, 200}, 1, true},
+		{"retries until success", RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}, []int{503, 502, 200}, 3, false},
+		{"gives up after max retries", RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond}, []int{500, 500, 200}, 2, true},
+		{"client errors are not retried", RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond}, []int{400, 200}, 1, true},
+		{
+			"custom retryable",
+			RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond, Retryable: func(err error) bool {
+				httpErr, ok := err.(*HTTPError)
+				return ok && httpErr.StatusCode == 409
+			}},
+			[]int{409, 200},
+			2,
+			false,
+		},
+		{
+			"custom retryable rejects",
+			RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond, Retryable: func(err error) bool { return false }},
+			[]int{503, 200},
+			1,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&attempts, 1)
+				status := tt.statuses[len(tt.statuses)-1]
+				if int(n) <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				w.WriteHeader(status)
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-123"})
+			}))
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL}
+			_, err := client.GetWithRetry(context.Background(), "/configs/config-123", tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.wantAttempts, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDeadline(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+	start := time.Now()
+	_, err := client.GetWithRetry(ctx, "/configs/config-123", RetryPolicy{MaxRetries: 3, Backoff: time.Minute})
+	if httpErr, ok := err.(*HTTPError); !ok || httpErr.StatusCode != 503 {
+		t.Errorf("Expected the 503 error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to give up without waiting for the deadline, took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if got := jitter(time.Second); got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("Expected jittered backoff between 500ms and 1s, got %v", got)
+		}
+	}
+	if got := jitter(0); got != 0 {
+		t.Errorf("Expected no backoff to stay zero, got %v", got)
+	}
+}
+
+func TestClientRetryPolicy(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": "slow down"})
+			return
+		}
+		switch r.Method {
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-123"})
+		}
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{
+		Endpoint:    server.URL,
+		RetryPolicy: RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond},
+	}
+
+	calls := map[string]func() error{
+		"Get": func() error {
+			_, err := client.Get(context.Background(), "/configs/config-123")
+			return err
+		},
+		"Put": func() error {
+			_, err := client.Put(context.Background(), "/configs/config-123", map[string]interface{}{"name": "web"})
+			return err
+		},
+		"Update": func() error {
+			_, err := client.Update(context.Background(), "/configs/config-123", map[string]interface{}{"name": "web"})
+			return err
+		},
+		"Delete": func() error {
+			return client.Delete(context.Background(), "/configs/config-123")
+		},
+	}
+	for name, call := range calls {
+		atomic.StoreInt32(&attempts, 0)
+		if err := call(); err != nil {
+			t.Errorf("%s: expected the client's retry policy to recover from a 429, got %v", name, err)
+		}
+		if got := atomic.LoadInt32(&attempts); got != 2 {
+			t.Errorf("%s: expected 2 attempts, got %d", name, got)
+		}
+	}
+}
+
+func TestRetryPolicyPostSentOnce(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{
+		Endpoint:    server.URL,
+		RetryPolicy: RetryPolicy{MaxRetries: 3, Backoff: time.Millisecond},
+	}
+	// The server may have created the config before failing, so a retry
+	// could create a second one.
+	if _, err := client.Post(context.Background(), "/configs", map[string]interface{}{"name": "web"}); err == nil {
+		t.Error("Expected the 503 error")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("Expected the POST to be sent once, got %d attempts", got)
+	}
+}
+
+func TestRetryPolicyMaintenanceWait(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]interface{}{"maintenance": true, "estimated_duration": "10ms"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-123"})
+	}))
+	defer server.Close()
+
+	// The maintenance estimate replaces the hour-long backoff.
+	client := &NixernetesClient{Endpoint: server.URL}
+	policy := RetryPolicy{MaxRetries: 1, Backoff: time.Hour}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetWithRetry(context.Background(), "/configs/config-123", policy)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the retry to wait for the maintenance estimate, not the backoff")
+	}
+}
+
+func TestRetryPolicyNextBackoff(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	if got := policy.nextBackoff(time.Second); got != 2*time.Second {
+		t.Errorf("Expected 2s, got %v", got)
+	}
+	if got := policy.nextBackoff(2 * time.Second); got != 3*time.Second {
+		t.Errorf("Expected backoff capped at 3s, got %v", got)
+	}
+	if got := (RetryPolicy{}).nextBackoff(time.Minute); got != 2*time.Minute {
+		t.Errorf("Expected uncapped 2m, got %v", got)
+	}
+}
+
+func TestMaxConnLifetime(t *testing.T) {
+	tests := []struct {
+		name      string
+		lifetime  time.Duration
+		wantConns int32
+	}{
+		{"unlimited", 0, 1},
+		{"expired", 10 * time.Millisecond, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conns int32
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-123"})
+			}))
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt32(&conns, 1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL, MaxConnLifetime: tt.lifetime}
+			for i := 0; i < 2; i++ {
+				if _, err := client.Get(context.Background(), "/configs/config-123"); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				time.Sleep(30 * time.Millisecond)
+			}
+
+			if got := atomic.LoadInt32(&conns); got != tt.wantConns {
+				t.Errorf("Expected %d connections, got %d", tt.wantConns, got)
+			}
+		})
+	}
+}
+
+func TestKeepAliveTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-123"})
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL, KeepAlive: 5 * time.Second}
+	if _, err := client.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error with custom keep-alive: %v", err)
+	}
+}
+
+func TestStaleConnectionRetry(t *testing.T) {
+	tests := []struct {
+		method       string
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{"GET", 2, false},
+		{"PUT", 2, false},
+		{"POST", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					// Drop the connection without answering, like a proxy
+					// closing a connection it considers stale
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err == nil {
+						conn.Close()
+					}
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-123"})
+			}))
+			defer server.Close()
+
+			client := &NixernetesClient{Endpoint: server.URL}
+			var body map[string]interface{}
+			if tt.method != "GET" {
+				body = map[string]interface{}{"name": "web"}
+			}
+			_, err := client.doRequestWithRetry(context.Background(), RetryPolicy{}, tt.method, "/configs/config-123", body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.wantAttempts, got)
+			}
+		})
+	}
+}
+
+func TestConnectionPoolSettings(t *testing.T) {
+	client := &NixernetesClient{MaxIdleConns: 16, IdleConnTimeout: 30 * time.Second}
+	transport := client.getHTTPClient().Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != 16 || transport.MaxIdleConns < 16 {
+		t.Errorf("Expected 16 idle connections per host, got %d (total %d)", transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+	}
+	if transport.IdleConnTimeout != 30*time.Second {
+		t.Errorf("Expected idle timeout 30s, got %v", transport.IdleConnTimeout)
+	}
+	if client.getHTTPClient() != client.getHTTPClient() {
+		t.Error("Expected the HTTP client to be shared between requests")
+	}
+
+	// A shorter connection lifetime bounds the idle timeout.
+	client = &NixernetesClient{IdleConnTimeout: time.Minute, MaxConnLifetime: 10 * time.Second}
+	if got := client.getHTTPClient().Transport.(*http.Transport).IdleConnTimeout; got != 10*time.Second {
+		t.Errorf("Expected idle timeout 10s, got %v", got)
+	}
+}
+
+// BenchmarkRepeatedRequests compares requests through one shared client with
+// a new client, and so a new connection pool, per request.
+func BenchmarkRepeatedRequests(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	defer server.Close()
+
+	b.Run("shared", func(b *testing.B) {
+		client := &NixernetesClient{Endpoint: server.URL}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.Get(context.Background(), "/configs/config-123"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("per request", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			client := &NixernetesClient{Endpoint: server.URL}
+			if _, err := client.Get(context.Background(), "/configs/config-123"); err != nil {
+				b.Fatal(err)
+			}
+			client.getHTTPClient().CloseIdleConnections()
+		}
+	})
+}
+
+func TestRequestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	defer server.Close()
+
+	// At 20 requests per second with a burst of one, five requests need at
+	// least four intervals of 50ms.
+	client := &NixernetesClient{Endpoint: server.URL, Limiter: rate.NewLimiter(20, 1)}
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.Get(context.Background(), "/configs/config-123"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected 5 requests to take at least 200ms, took %v", elapsed)
+	}
+
+	// A request that cannot get its turn before the deadline fails without
+	// being sent.
+	slow := &NixernetesClient{Endpoint: server.URL, Limiter: rate.NewLimiter(0.1, 1)}
+	if _, err := slow.Get(context.Background(), "/configs/config-123"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := slow.Get(ctx, "/configs/config-123"); err == nil {
+		t.Error("Expected rate limit wait to fail before the deadline")
+	}
+}
+
+func TestRequestConcurrencyLimit(t *testing.T) {
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	defer server.Close()
+
+	const limit = 3
+	client := &NixernetesClient{Endpoint: server.URL, Concurrency: semaphore.NewWeighted(limit)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Get(context.Background(), "/configs/config-123"); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&peak); got > limit {
+		t.Errorf("Expected at most %d concurrent requests, got %d", limit, got)
+	} else if got < limit {
+		t.Errorf("Expected requests to use all %d slots, peak was %d", limit, got)
+	}
+}
+
+func TestDoRequestInto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/modules/mod-1":
+			w.Write([]byte(`{"id":"mod-1","name":"api","replicas":3,"paused_replicas":2,"project_id":"proj-1"}`))
+		case "/modules/mod-2":
+			w.Write([]byte(`{"id":"mod-2","replicas":"3"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	module, err := doRequestInto[moduleResponse](context.Background(), client, "GET", "/modules/mod-1", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if module.Name == nil || *module.Name != "api" || module.Replicas == nil || *module.Replicas != 3 {
+		t.Errorf("Expected api with 3 replicas, got %v with %v", module.Name, module.Replicas)
+	}
+	if missing := module.missing(); strings.Join(missing, ",") != "image,namespace" {
+		t.Errorf("Expected image and namespace to be missing, got %v", missing)
+	}
+	if module.PausedReplicas == nil || *module.PausedReplicas != 2 {
+		t.Errorf("Expected 2 paused replicas, got %v", module.PausedReplicas)
+	}
+	if module.ProjectID == nil || *module.ProjectID != "proj-1" || module.CreatedAt != nil {
+		t.Errorf("Expected project proj-1 and no created_at, got %v and %v", module.ProjectID, module.CreatedAt)
+	}
+	if module.Raw["id"] != "mod-1" {
+		t.Errorf("Expected the raw response to be kept, got %v", module.Raw)
+	}
+
+	// The map-based methods decode the same response as before
+	response, err := client.Get(context.Background(), "/modules/mod-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response["replicas"] != float64(3) {
+		t.Errorf("Expected replicas 3, got %v", response["replicas"])
+	}
+
+	if _, err := doRequestInto[moduleResponse](context.Background(), client, "GET", "/modules/mod-2", nil); err == nil {
+		t.Error("Expected error for a string replica count")
+	}
+
+	empty, err := doRequestInto[ModuleStatus](context.Background(), client, "DELETE", "/modules/mod-3", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if empty != (ModuleStatus{}) {
+		t.Errorf("Expected the zero value for an empty response, got %v", empty)
+	}
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	state, _ := trace.ParseTraceState("vendor=value")
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		TraceState: state,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), span)
+
+	enabled := &NixernetesClient{Endpoint: server.URL, Propagator: propagation.TraceContext{}}
+	if _, err := enabled.Get(ctx, "/configs"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"; headers[0].Get("traceparent") != want {
+		t.Errorf("Expected traceparent %q, got %q", want, headers[0].Get("traceparent"))
+	}
+	if headers[0].Get("tracestate") != "vendor=value" {
+		t.Errorf("Expected tracestate vendor=value, got %q", headers[0].Get("tracestate"))
+	}
+
+	// Without a trace in the context, the run's trace is used.
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	t.Setenv("TRACESTATE", "")
+	enabled.TraceParent = traceParentFromEnv(enabled.Propagator)
+	if _, err := enabled.Get(context.Background(), "/configs"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"; headers[1].Get("traceparent") != want {
+		t.Errorf("Expected traceparent %q from the environment, got %q", want, headers[1].Get("traceparent"))
+	}
+
+	disabled := &NixernetesClient{Endpoint: server.URL}
+	if _, err := disabled.Get(ctx, "/configs"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := headers[2].Get("traceparent"); got != "" {
+		t.Errorf("Expected no traceparent when propagation is disabled, got %q", got)
 	}
 }
@@ -2,10 +2,19 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -14,6 +23,24 @@ var (
 	_ datasource.DataSourceWithConfigure = &NixernetesModulesDataSource{}
 	_ datasource.DataSource              = &NixernetesProjectsDataSource{}
 	_ datasource.DataSourceWithConfigure = &NixernetesProjectsDataSource{}
+	_ datasource.DataSource              = &NixernetesConfigDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesConfigDataSource{}
+	_ datasource.DataSource              = &NixernetesProjectDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesProjectDataSource{}
+	_ datasource.DataSource              = &NixernetesModuleEventsDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesModuleEventsDataSource{}
+	_ datasource.DataSource              = &NixernetesModuleStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesModuleStatusDataSource{}
+	_ datasource.DataSource              = &NixernetesInventoryDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesInventoryDataSource{}
+	_ datasource.DataSource              = &NixernetesClusterDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesClusterDataSource{}
+	_ datasource.DataSource              = &NixernetesModuleCostDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesModuleCostDataSource{}
+	_ datasource.DataSource              = &NixernetesDeploymentsDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesDeploymentsDataSource{}
+	_ datasource.DataSource              = &NixernetesConfigVersionDataSource{}
+	_ datasource.DataSourceWithConfigure = &NixernetesConfigVersionDataSource{}
 )
 
 // NewNixernetesModulesDataSource is a helper function to simplify the provider implementation.
@@ -27,7 +54,12 @@ type NixernetesModulesDataSource struct {
 }
 
 type NixernetesModulesDataSourceModel struct {
-	Modules []NixernetesModuleData `tfsdk:"modules"`
+	ProjectID             types.String           `tfsdk:"project_id"`
+	ConsistentRead        types.Bool             `tfsdk:"consistent_read"`
+	ExpectMinCount        types.Int64            `tfsdk:"expect_min_count"`
+	ConsistentReadTimeout types.String           `tfsdk:"consistent_read_timeout"`
+	PageSize              types.Int64            `tfsdk:"page_size"`
+	Modules               []NixernetesModuleData `tfsdk:"modules"`
 }
 
 type NixernetesModuleData struct {
@@ -45,6 +77,26 @@ func (d *NixernetesModulesDataSource) Schema(ctx context.Context, _ datasource.S
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Fetches the list of available Nixernetes modules.",
 		Attributes: map[string]schema.Attribute{
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "Only list modules belonging to this project",
+				Optional:            true,
+			},
+			"consistent_read": schema.BoolAttribute{
+				MarkdownDescription: "Retry the list until it contains at least `expect_min_count` modules, to ride out eventual consistency right after creation",
+				Optional:            true,
+			},
+			"expect_min_count": schema.Int64Attribute{
+				MarkdownDescription: "Minimum number of modules to wait for when `consistent_read` is set (default: 1)",
+				Optional:            true,
+			},
+			"consistent_read_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to keep retrying when `consistent_read` is set, as a duration such as `30s` or `2m` (default: `60s`)",
+				Optional:            true,
+			},
+			"page_size": schema.Int64Attribute{
+				MarkdownDescription: "Number of modules to request per page. All pages are read regardless; defaults to the server's page size",
+				Optional:            true,
+			},
 			"modules": schema.ListNestedAttribute{
 				MarkdownDescription: "List of modules",
 				Computed:            true,
@@ -86,11 +138,66 @@ func (d *NixernetesModulesDataSource) Configure(_ context.Context, req datasourc
 	d.client = client
 }
 
-func (d *NixernetesModulesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *NixernetesModulesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state NixernetesModulesDataSourceModel
 
-	// API call to list modules
-	response, err := d.client.Get(ctx, "/modules")
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := state.ProjectID.ValueString()
+	if !state.ProjectID.IsNull() && !isValidID(projectID) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("project_id"),
+			"Invalid project ID",
+			"Project ID must be 1-64 characters of letters, digits, hyphens and underscores, starting with a letter or digit, got: "+projectID,
+		)
+		return
+	}
+
+	if !state.PageSize.IsNull() && state.PageSize.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("page_size"),
+			"Invalid page size",
+			fmt.Sprintf("Page size must be at least 1, got: %d", state.PageSize.ValueInt64()),
+		)
+		return
+	}
+
+	read := func() (int, error) {
+		// API call to list modules, following the cursor through all pages
+		query := url.Values{}
+		if projectID != "" {
+			query.Set("project_id", projectID)
+		}
+		if !state.PageSize.IsNull() {
+			query.Set("page_size", strconv.FormatInt(state.PageSize.ValueInt64(), 10))
+		}
+		modules, err := listAllPages(ctx, d.client, d.client.modulesPath(), query, "modules")
+		if err != nil {
+			return 0, err
+		}
+		response := map[string]interface{}{"modules": modules}
+
+		if projectID == "" {
+			state.Modules, err = moduleListFromResponse(response)
+			return len(state.Modules), err
+		}
+
+		// Older servers ignore the project_id parameter, so filter here as well
+		response["modules"] = filterByProject(response["modules"], projectID)
+		state.Modules, err = moduleListFromResponse(response)
+		if err != nil {
+			return 0, err
+		}
+		if state.Modules == nil {
+			state.Modules = []NixernetesModuleData{}
+		}
+		return len(state.Modules), nil
+	}
+
+	err := readList(ctx, state.ConsistentRead, state.ExpectMinCount, state.ConsistentReadTimeout, read)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading modules",
@@ -99,18 +206,110 @@ func (d *NixernetesModulesDataSource) Read(ctx context.Context, _ datasource.Rea
 		return
 	}
 
-	modules := response["modules"].([]interface{})
-	for _, m := range modules {
-		module := m.(map[string]interface{})
-		state.Modules = append(state.Modules, NixernetesModuleData{
-			ID:          types.StringValue(module["id"].(string)),
-			Name:        types.StringValue(module["name"].(string)),
-			Description: types.StringValue(module["description"].(string)),
-			Version:     types.StringValue(module["version"].(string)),
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// moduleListFromResponse converts a GET /modules response into module data.
+func moduleListFromResponse(response map[string]interface{}) ([]NixernetesModuleData, error) {
+	modules, err := listFromResponse(response, "modules")
+	if err != nil {
+		return nil, err
+	}
+	var result []NixernetesModuleData
+	for i, module := range modules {
+		fields, err := requireStrings(module, "id", "name")
+		if err != nil {
+			return nil, fmt.Errorf("modules[%d]: %w", i, err)
+		}
+		result = append(result, NixernetesModuleData{
+			ID:          types.StringValue(fields["id"]),
+			Name:        types.StringValue(fields["name"]),
+			Description: stringFromResponse(module, "description"),
+			Version:     stringFromResponse(module, "version"),
 		})
 	}
+	return result, nil
+}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+// listFromResponse returns the objects listed under key in a list response;
+// a missing or null list is empty.
+func listFromResponse(response map[string]interface{}, key string) ([]map[string]interface{}, error) {
+	if response[key] == nil {
+		return nil, nil
+	}
+	list, ok := response[key].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("response field %s is not a list", key)
+	}
+	items := make([]map[string]interface{}, 0, len(list))
+	for i, item := range list {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("response field %s[%d] is not an object", key, i)
+		}
+		items = append(items, object)
+	}
+	return items, nil
+}
+
+// listAllPages reads every page of the list at basePath with query, following
+// the cursor each page reports, and returns the items listed under key. The
+// query's cursor parameter is overwritten.
+func listAllPages(ctx context.Context, client *NixernetesClient, basePath string, query url.Values, key string) ([]interface{}, error) {
+	items := []interface{}{}
+	seen := map[string]bool{}
+	for {
+		endpoint := basePath
+		if len(query) > 0 {
+			endpoint += "?" + query.Encode()
+		}
+		page, err := client.Get(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if page[key] != nil {
+			list, ok := page[key].([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("response field %s is not a list", key)
+			}
+			items = append(items, list...)
+		}
+
+		cursor := nextPageCursor(page)
+		if cursor == "" {
+			return items, nil
+		}
+		if seen[cursor] {
+			return nil, fmt.Errorf("the server returned the page cursor %q more than once", cursor)
+		}
+		seen[cursor] = true
+		query.Set("cursor", cursor)
+	}
+}
+
+// nextPageCursor returns the cursor of the page following a list response,
+// reported as next_page or next, or "" on the last page.
+func nextPageCursor(response map[string]interface{}) string {
+	if cursor, ok := response["next_page"].(string); ok && cursor != "" {
+		return cursor
+	}
+	cursor, _ := response["next"].(string)
+	return cursor
+}
+
+// filterByProject keeps the listed items that belong to projectID. Items that
+// do not report a project are kept, since the server has already filtered them.
+func filterByProject(items interface{}, projectID string) []interface{} {
+	list, _ := items.([]interface{})
+	filtered := []interface{}{}
+	for _, item := range list {
+		m, _ := item.(map[string]interface{})
+		if id, ok := m["project_id"].(string); ok && id != projectID {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered
 }
 
 // ========== Projects Data Source ==========
@@ -124,9 +323,19 @@ type NixernetesProjectsDataSource struct {
 }
 
 type NixernetesProjectsDataSourceModel struct {
-	Projects []NixernetesProjectData `tfsdk:"projects"`
+	Status                types.String            `tfsdk:"status"`
+	NamePrefix            types.String            `tfsdk:"name_prefix"`
+	ConsistentRead        types.Bool              `tfsdk:"consistent_read"`
+	ExpectMinCount        types.Int64             `tfsdk:"expect_min_count"`
+	ConsistentReadTimeout types.String            `tfsdk:"consistent_read_timeout"`
+	PageSize              types.Int64             `tfsdk:"page_size"`
+	Projects              []NixernetesProjectData `tfsdk:"projects"`
 }
 
+// projectStatuses are the project statuses the projects data source can
+// filter on.
+var projectStatuses = []string{"active", "paused", "archived", "production"}
+
 type NixernetesProjectData struct {
 	ID          types.String `tfsdk:"id"`
 	Name        types.String `tfsdk:"name"`
@@ -142,6 +351,30 @@ func (d *NixernetesProjectsDataSource) Schema(ctx context.Context, _ datasource.
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "Fetches the list of Nixernetes projects.",
 		Attributes: map[string]schema.Attribute{
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only list projects with this status: " + strings.Join(projectStatuses, ", "),
+				Optional:            true,
+			},
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "Only list projects whose name starts with this prefix",
+				Optional:            true,
+			},
+			"consistent_read": schema.BoolAttribute{
+				MarkdownDescription: "Retry the list until it contains at least `expect_min_count` projects, to ride out eventual consistency right after creation",
+				Optional:            true,
+			},
+			"expect_min_count": schema.Int64Attribute{
+				MarkdownDescription: "Minimum number of projects to wait for when `consistent_read` is set (default: 1)",
+				Optional:            true,
+			},
+			"consistent_read_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to keep retrying when `consistent_read` is set, as a duration such as `30s` or `2m` (default: `60s`)",
+				Optional:            true,
+			},
+			"page_size": schema.Int64Attribute{
+				MarkdownDescription: "Number of projects to request per page. All pages are read regardless; defaults to the server's page size",
+				Optional:            true,
+			},
 			"projects": schema.ListNestedAttribute{
 				MarkdownDescription: "List of projects",
 				Computed:            true,
@@ -183,11 +416,72 @@ func (d *NixernetesProjectsDataSource) Configure(_ context.Context, req datasour
 	d.client = client
 }
 
-func (d *NixernetesProjectsDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+func (d *NixernetesProjectsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state NixernetesProjectsDataSourceModel
 
-	// API call to list projects
-	response, err := d.client.Get(ctx, "/projects")
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	status := state.Status.ValueString()
+	if !state.Status.IsNull() && !slices.Contains(projectStatuses, status) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("status"),
+			"Invalid status",
+			fmt.Sprintf("Status must be one of %s, got: %s", strings.Join(projectStatuses, ", "), status),
+		)
+		return
+	}
+
+	if !state.PageSize.IsNull() && state.PageSize.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("page_size"),
+			"Invalid page size",
+			fmt.Sprintf("Page size must be at least 1, got: %d", state.PageSize.ValueInt64()),
+		)
+		return
+	}
+
+	namePrefix := state.NamePrefix.ValueString()
+
+	read := func() (int, error) {
+		// API call to list projects, following the cursor through all pages
+		query := url.Values{}
+		if status != "" {
+			query.Set("status", status)
+		}
+		if namePrefix != "" {
+			query.Set("name_prefix", namePrefix)
+		}
+		if !state.PageSize.IsNull() {
+			query.Set("page_size", strconv.FormatInt(state.PageSize.ValueInt64(), 10))
+		}
+		projects, err := listAllPages(ctx, d.client, d.client.projectsPath(), query, "projects")
+		if err != nil {
+			return 0, err
+		}
+
+		// Older servers ignore the filters, so apply them here as well
+		state.Projects, err = projectListFromResponse(map[string]interface{}{"projects": projects})
+		if err != nil {
+			return 0, err
+		}
+		filtered := []NixernetesProjectData{}
+		for _, project := range state.Projects {
+			if status != "" && project.Status.ValueString() != status {
+				continue
+			}
+			if !strings.HasPrefix(project.Name.ValueString(), namePrefix) {
+				continue
+			}
+			filtered = append(filtered, project)
+		}
+		state.Projects = filtered
+		return len(state.Projects), nil
+	}
+
+	err := readList(ctx, state.ConsistentRead, state.ExpectMinCount, state.ConsistentReadTimeout, read)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading projects",
@@ -196,16 +490,1375 @@ func (d *NixernetesProjectsDataSource) Read(ctx context.Context, _ datasource.Re
 		return
 	}
 
-	projects := response["projects"].([]interface{})
-	for _, p := range projects {
-		project := p.(map[string]interface{})
-		state.Projects = append(state.Projects, NixernetesProjectData{
-			ID:          types.StringValue(project["id"].(string)),
-			Name:        types.StringValue(project["name"].(string)),
-			Description: types.StringValue(project["description"].(string)),
-			Status:      types.StringValue(project["status"].(string)),
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// projectListFromResponse converts a GET /projects response into project data.
+func projectListFromResponse(response map[string]interface{}) ([]NixernetesProjectData, error) {
+	projects, err := listFromResponse(response, "projects")
+	if err != nil {
+		return nil, err
+	}
+	var result []NixernetesProjectData
+	for i, project := range projects {
+		fields, err := requireStrings(project, "id", "name")
+		if err != nil {
+			return nil, fmt.Errorf("projects[%d]: %w", i, err)
+		}
+		result = append(result, NixernetesProjectData{
+			ID:          types.StringValue(fields["id"]),
+			Name:        types.StringValue(fields["name"]),
+			Description: stringFromResponse(project, "description"),
+			Status:      stringFromResponse(project, "status"),
+		})
+	}
+	return result, nil
+}
+
+// ========== Consistent List Reads ==========
+
+// defaultConsistentReadTimeout bounds how long consistent_read keeps retrying.
+const defaultConsistentReadTimeout = 60 * time.Second
+
+// consistentReadInterval is the pause between list attempts during a consistent read.
+var consistentReadInterval = 2 * time.Second
+
+// readList performs a list read. When consistentRead is set it repeats the
+// read until it returns at least expectMinCount items (default 1), the
+// timeout elapses, or ctx is cancelled.
+func readList(ctx context.Context, consistentRead types.Bool, expectMinCount types.Int64, timeout types.String, read func() (int, error)) error {
+	count, err := read()
+	if err != nil || !consistentRead.ValueBool() {
+		return err
+	}
+
+	minCount := int64(1)
+	if !expectMinCount.IsNull() {
+		minCount = expectMinCount.ValueInt64()
+	}
+
+	wait := defaultConsistentReadTimeout
+	if !timeout.IsNull() {
+		wait, err = time.ParseDuration(timeout.ValueString())
+		if err != nil {
+			return fmt.Errorf("invalid consistent_read_timeout %q: %w", timeout.ValueString(), err)
+		}
+	}
+	deadline := time.Now().Add(wait)
+
+	for int64(count) < minCount {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("expected at least %d items but found %d after waiting %s", minCount, count, wait)
+		}
+
+		tflog.Debug(ctx, "Waiting for list to become consistent", map[string]any{
+			"found":    count,
+			"expected": minCount,
 		})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(consistentReadInterval):
+		}
+
+		count, err = read()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ========== Config Data Source ==========
+
+// NewNixernetesConfigDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesConfigDataSource() datasource.DataSource {
+	return &NixernetesConfigDataSource{}
+}
+
+// NixernetesConfigDataSource looks up an existing configuration by name.
+type NixernetesConfigDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesConfigDataSourceModel struct {
+	Name          types.String `tfsdk:"name"`
+	ID            types.String `tfsdk:"id"`
+	Configuration types.String `tfsdk:"configuration"`
+	Environment   types.String `tfsdk:"environment"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	UpdatedAt     types.String `tfsdk:"updated_at"`
+}
+
+func (d *NixernetesConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config"
+}
+
+func (d *NixernetesConfigDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Looks up an existing Nixernetes configuration by name.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name of the configuration. Exactly one configuration must have it.",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Configuration ID",
+				Computed:            true,
+			},
+			"configuration": schema.StringAttribute{
+				MarkdownDescription: "Nix configuration content",
+				Computed:            true,
+			},
+			"environment": schema.StringAttribute{
+				MarkdownDescription: "Deployment environment",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Creation timestamp",
+				Computed:            true,
+			},
+			"updated_at": schema.StringAttribute{
+				MarkdownDescription: "Last update timestamp",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NixernetesConfigDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesConfigDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := state.Name.ValueString()
+	response, err := d.client.Get(ctx, d.client.configsPath()+"?name="+url.QueryEscape(name))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading configuration", "Could not list configurations: "+err.Error())
+		return
+	}
+
+	// Older servers ignore the name parameter, so match the name here as well.
+	var ids []string
+	list, _ := response["configs"].([]interface{})
+	for _, item := range list {
+		c, _ := item.(map[string]interface{})
+		if n, _ := c["name"].(string); n != name {
+			continue
+		}
+		if id, ok := c["id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Configuration not found",
+			fmt.Sprintf("No configuration is named %q.", name),
+		)
+		return
+	case 1:
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Multiple configurations found",
+			fmt.Sprintf("%d configurations are named %q (IDs %s); the name must identify exactly one.", len(ids), name, strings.Join(ids, ", ")),
+		)
+		return
+	}
+
+	// The list does not include the content, so read the configuration itself.
+	config, err := d.client.Get(ctx, d.client.configsPath()+"/"+url.PathEscape(ids[0]))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading configuration", "Could not read configuration "+ids[0]+": "+err.Error())
+		return
+	}
+
+	state.ID = types.StringValue(ids[0])
+	state.Configuration = stringFromResponse(config, "configuration")
+	state.Environment = environmentFromResponse(config, types.StringNull())
+	state.CreatedAt = stringFromResponse(config, "created_at")
+	state.UpdatedAt = stringFromResponse(config, "updated_at")
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ========== Project Data Source ==========
+
+// NewNixernetesProjectDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesProjectDataSource() datasource.DataSource {
+	return &NixernetesProjectDataSource{}
+}
+
+// NixernetesProjectDataSource reads one project together with the IDs of its
+// modules and configurations, e.g. to import an existing project tree.
+type NixernetesProjectDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesProjectDataSourceModel struct {
+	ID               types.String   `tfsdk:"id"`
+	Name             types.String   `tfsdk:"name"`
+	Description      types.String   `tfsdk:"description"`
+	Status           types.String   `tfsdk:"status"`
+	DefaultNamespace types.String   `tfsdk:"default_namespace"`
+	Paused           types.Bool     `tfsdk:"paused"`
+	ModuleIDs        []types.String `tfsdk:"module_ids"`
+	ConfigIDs        []types.String `tfsdk:"config_ids"`
+	ImportBlocks     types.String   `tfsdk:"import_blocks"`
+}
+
+func (d *NixernetesProjectDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project"
+}
+
+func (d *NixernetesProjectDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads a Nixernetes project and lists its modules and configurations, for instance to import an existing project and everything in it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Project ID",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Project name",
+				Computed:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Project description",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Project status",
+				Computed:            true,
+			},
+			"default_namespace": schema.StringAttribute{
+				MarkdownDescription: "Namespace used by modules in the project that do not set their own",
+				Computed:            true,
+			},
+			"paused": schema.BoolAttribute{
+				MarkdownDescription: "Whether the project is paused",
+				Computed:            true,
+			},
+			"module_ids": schema.ListAttribute{
+				MarkdownDescription: "IDs of the modules in the project",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"config_ids": schema.ListAttribute{
+				MarkdownDescription: "IDs of the configurations in the project",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"import_blocks": schema.StringAttribute{
+				MarkdownDescription: "Terraform `import` blocks for the project's modules and configurations, named after each one, e.g. to write to a file with `terraform console` and use with `terraform plan -generate-config-out`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NixernetesProjectDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesProjectDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesProjectDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projectID := state.ID.ValueString()
+	if !isValidID(projectID) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("id"),
+			"Invalid project ID",
+			"Project ID must be 1-64 characters of letters, digits, hyphens and underscores, starting with a letter or digit, got: "+projectID,
+		)
+		return
+	}
+
+	response, err := d.client.Get(ctx, d.client.projectsPath()+"/"+url.PathEscape(projectID))
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading project", "Could not read project: "+err.Error())
+		return
+	}
+	state.Name = stringFromResponse(response, "name")
+	state.Description = stringFromResponse(response, "description")
+	state.Status = stringFromResponse(response, "status")
+	state.DefaultNamespace = stringFromResponse(response, "default_namespace")
+	state.Paused = pausedFromResponse(response, types.BoolNull())
+
+	children, err := d.client.GetProjectChildren(ctx, projectID)
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading project", "Could not list the project's modules and configurations: "+err.Error())
+		return
+	}
+	state.ModuleIDs = childIDs(children.Modules)
+	state.ConfigIDs = childIDs(children.Configs)
+	state.ImportBlocks = types.StringValue(projectImportBlocks(children))
+
+	tflog.Debug(ctx, "Read project", map[string]any{
+		"id":      projectID,
+		"modules": len(state.ModuleIDs),
+		"configs": len(state.ConfigIDs),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// stringFromResponse returns the string field key of response, or null when
+// the server leaves it out.
+func stringFromResponse(response map[string]interface{}, key string) types.String {
+	if s, ok := response[key].(string); ok {
+		return types.StringValue(s)
+	}
+	return types.StringNull()
+}
+
+// childIDs returns the IDs of children.
+func childIDs(children []ProjectChild) []types.String {
+	ids := make([]types.String, 0, len(children))
+	for _, child := range children {
+		ids = append(ids, types.StringValue(child.ID))
+	}
+	return ids
+}
+
+// ========== Module Events Data Source ==========
+
+func NewNixernetesModuleEventsDataSource() datasource.DataSource {
+	return &NixernetesModuleEventsDataSource{}
+}
+
+type NixernetesModuleEventsDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesModuleEventsDataSourceModel struct {
+	ModuleID types.String                `tfsdk:"module_id"`
+	Since    types.String                `tfsdk:"since"`
+	Events   []NixernetesModuleEventData `tfsdk:"events"`
+}
+
+type NixernetesModuleEventData struct {
+	Type      types.String `tfsdk:"type"`
+	Reason    types.String `tfsdk:"reason"`
+	Message   types.String `tfsdk:"message"`
+	Timestamp types.String `tfsdk:"timestamp"`
+	Count     types.Int64  `tfsdk:"count"`
+}
+
+func (d *NixernetesModuleEventsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_events"
+}
+
+func (d *NixernetesModuleEventsDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Fetches the Kubernetes events recorded for a Nixernetes module, newest first.",
+		Attributes: map[string]schema.Attribute{
+			"module_id": schema.StringAttribute{
+				MarkdownDescription: "Module instance ID",
+				Required:            true,
+			},
+			"since": schema.StringAttribute{
+				MarkdownDescription: "Only return events at or after this RFC 3339 timestamp",
+				Optional:            true,
+			},
+			"events": schema.ListNestedAttribute{
+				MarkdownDescription: "List of events, newest first",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Event type (Normal, Warning)",
+							Computed:            true,
+						},
+						"reason": schema.StringAttribute{
+							MarkdownDescription: "Short machine-readable reason",
+							Computed:            true,
+						},
+						"message": schema.StringAttribute{
+							MarkdownDescription: "Human-readable description",
+							Computed:            true,
+						},
+						"timestamp": schema.StringAttribute{
+							MarkdownDescription: "Time the event was last observed",
+							Computed:            true,
+						},
+						"count": schema.Int64Attribute{
+							MarkdownDescription: "Number of times the event occurred",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *NixernetesModuleEventsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesModuleEventsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesModuleEventsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// API call to list module events
+	events, err := d.client.GetModuleEvents(ctx, state.ModuleID.ValueString(), state.Since.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading module events",
+			"Could not read events for module "+state.ModuleID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Events = []NixernetesModuleEventData{}
+	for _, event := range events {
+		state.Events = append(state.Events, NixernetesModuleEventData{
+			Type:      types.StringValue(event.Type),
+			Reason:    types.StringValue(event.Reason),
+			Message:   types.StringValue(event.Message),
+			Timestamp: types.StringValue(event.Timestamp),
+			Count:     types.Int64Value(event.Count),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ========== Module Status Data Source ==========
+
+// defaultModuleStatusTimeout bounds how long wait_for_ready polls when no
+// timeout is set.
+const defaultModuleStatusTimeout = 5 * time.Minute
+
+// NewNixernetesModuleStatusDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesModuleStatusDataSource() datasource.DataSource {
+	return &NixernetesModuleStatusDataSource{}
+}
+
+// NixernetesModuleStatusDataSource reads the runtime status of a module,
+// optionally waiting for it to become ready.
+type NixernetesModuleStatusDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesModuleStatusDataSourceModel struct {
+	ModuleID           types.String `tfsdk:"module_id"`
+	WaitForReady       types.Bool   `tfsdk:"wait_for_ready"`
+	Timeout            types.String `tfsdk:"timeout"`
+	Phase              types.String `tfsdk:"phase"`
+	ReadyReplicas      types.Int64  `tfsdk:"ready_replicas"`
+	AvailableReplicas  types.Int64  `tfsdk:"available_replicas"`
+	LastTransitionTime types.String `tfsdk:"last_transition_time"`
+}
+
+func (d *NixernetesModuleStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_status"
+}
+
+func (d *NixernetesModuleStatusDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the runtime status of a module. With `wait_for_ready`, the read waits until the module is ready, so dependent resources can be gated on a healthy module.",
+		Attributes: map[string]schema.Attribute{
+			"module_id": schema.StringAttribute{
+				MarkdownDescription: "Module instance ID",
+				Required:            true,
+			},
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Poll the status until the module is ready, failing if it fails or `timeout` elapses first",
+				Optional:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "How long `wait_for_ready` waits, as a duration such as `30s` or `10m` (default: `5m`)",
+				Optional:            true,
+			},
+			"phase": schema.StringAttribute{
+				MarkdownDescription: "Lifecycle phase of the module, e.g. `Pending`, `Ready` or `Failed`",
+				Computed:            true,
+			},
+			"ready_replicas": schema.Int64Attribute{
+				MarkdownDescription: "Number of replicas that are ready",
+				Computed:            true,
+			},
+			"available_replicas": schema.Int64Attribute{
+				MarkdownDescription: "Number of replicas that are available to serve traffic",
+				Computed:            true,
+			},
+			"last_transition_time": schema.StringAttribute{
+				MarkdownDescription: "When the module last changed phase",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NixernetesModuleStatusDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesModuleStatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesModuleStatusDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	moduleID := state.ModuleID.ValueString()
+
+	if state.WaitForReady.ValueBool() {
+		timeout := defaultModuleStatusTimeout
+		if !state.Timeout.IsNull() {
+			var err error
+			timeout, err = time.ParseDuration(state.Timeout.ValueString())
+			if err != nil || timeout <= 0 {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("timeout"),
+					"Invalid timeout",
+					fmt.Sprintf("Timeout must be a positive duration such as 30s or 10m, got: %s", state.Timeout.ValueString()),
+				)
+				return
+			}
+		}
+
+		if err := d.client.WaitForModuleReady(ctx, moduleID, timeout); err != nil {
+			resp.Diagnostics.AddError(
+				"Module not ready",
+				fmt.Sprintf("Module %s did not become ready: %s", moduleID, err),
+			)
+			return
+		}
+	}
+
+	status, err := d.client.GetModuleStatus(ctx, moduleID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading module status",
+			"Could not read the status of module "+moduleID+": "+err.Error(),
+		)
+		return
+	}
+
+	state.Phase = types.StringValue(status.Phase)
+	state.ReadyReplicas = types.Int64Value(status.ReadyReplicas)
+	state.AvailableReplicas = types.Int64Value(status.AvailableReplicas)
+	state.LastTransitionTime = types.StringNull()
+	if status.LastTransitionTime != "" {
+		state.LastTransitionTime = types.StringValue(status.LastTransitionTime)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ========== Inventory Data Source ==========
+
+// NewNixernetesInventoryDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesInventoryDataSource() datasource.DataSource {
+	return &NixernetesInventoryDataSource{}
+}
+
+// NixernetesInventoryDataSource reports every config, module and project in one read.
+type NixernetesInventoryDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesInventoryDataSourceModel struct {
+	Configs          []NixernetesConfigData  `tfsdk:"configs"`
+	Modules          []NixernetesModuleData  `tfsdk:"modules"`
+	Projects         []NixernetesProjectData `tfsdk:"projects"`
+	FailedCategories []types.String          `tfsdk:"failed_categories"`
+}
+
+type NixernetesConfigData struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Environment types.String `tfsdk:"environment"`
+}
+
+func (d *NixernetesInventoryDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_inventory"
+}
+
+func (d *NixernetesInventoryDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists all configs, modules and projects as a single report. A category that cannot be read is listed in `failed_categories` and the others are still returned.",
+		Attributes: map[string]schema.Attribute{
+			"configs": schema.ListNestedAttribute{
+				MarkdownDescription: "List of configurations",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Configuration ID",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Configuration name",
+							Computed:            true,
+						},
+						"environment": schema.StringAttribute{
+							MarkdownDescription: "Deployment environment",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"modules": schema.ListNestedAttribute{
+				MarkdownDescription: "List of modules",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Module ID",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Module name",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Module description",
+							Computed:            true,
+						},
+						"version": schema.StringAttribute{
+							MarkdownDescription: "Module version",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"projects": schema.ListNestedAttribute{
+				MarkdownDescription: "List of projects",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Project ID",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Project name",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Project description",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Project status",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"failed_categories": schema.ListAttribute{
+				MarkdownDescription: "Categories (`configs`, `modules`, `projects`) that could not be read; their lists are null",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NixernetesInventoryDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesInventoryDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesInventoryDataSourceModel
+
+	// Each category is listed concurrently and fails independently.
+	categories := []struct {
+		name     string
+		endpoint string
+		parse    func(map[string]interface{}) error
+	}{
+		{"configs", d.client.configsPath(), func(r map[string]interface{}) (err error) {
+			state.Configs, err = configListFromResponse(r)
+			return err
+		}},
+		{"modules", d.client.modulesPath(), func(r map[string]interface{}) (err error) {
+			state.Modules, err = moduleListFromResponse(r)
+			return err
+		}},
+		{"projects", d.client.projectsPath(), func(r map[string]interface{}) (err error) {
+			state.Projects, err = projectListFromResponse(r)
+			return err
+		}},
+	}
+
+	errs := make([]error, len(categories))
+	var wg sync.WaitGroup
+	for i, category := range categories {
+		wg.Add(1)
+		go func(i int, name, endpoint string, parse func(map[string]interface{}) error) {
+			defer wg.Done()
+			items, err := listAllPages(ctx, d.client, endpoint, url.Values{}, name)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			errs[i] = parse(map[string]interface{}{name: items})
+		}(i, category.name, category.endpoint, category.parse)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		name := categories[i].name
+		state.FailedCategories = append(state.FailedCategories, types.StringValue(name))
+		resp.Diagnostics.AddWarning(
+			"Incomplete inventory",
+			fmt.Sprintf("Could not read %s, so they are missing from the inventory: %s", name, err),
+		)
+	}
+
+	if len(state.FailedCategories) == len(categories) {
+		resp.Diagnostics.AddError("Error reading inventory", "Could not read configs, modules or projects")
+		return
+	}
+
+	tflog.Debug(ctx, "Read inventory", map[string]any{
+		"configs":  len(state.Configs),
+		"modules":  len(state.Modules),
+		"projects": len(state.Projects),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// configListFromResponse converts a GET /configs response into config data.
+func configListFromResponse(response map[string]interface{}) ([]NixernetesConfigData, error) {
+	configs, err := listFromResponse(response, "configs")
+	if err != nil {
+		return nil, err
+	}
+	var result []NixernetesConfigData
+	for i, config := range configs {
+		fields, err := requireStrings(config, "id", "name")
+		if err != nil {
+			return nil, fmt.Errorf("configs[%d]: %w", i, err)
+		}
+		result = append(result, NixernetesConfigData{
+			ID:          types.StringValue(fields["id"]),
+			Name:        types.StringValue(fields["name"]),
+			Environment: environmentFromResponse(config, types.StringNull()),
+		})
+	}
+	return result, nil
+}
+
+// ========== Cluster Data Source ==========
+
+// NewNixernetesClusterDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesClusterDataSource() datasource.DataSource {
+	return &NixernetesClusterDataSource{}
+}
+
+// NixernetesClusterDataSource reports the Kubernetes cluster behind the API server.
+type NixernetesClusterDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesClusterDataSourceModel struct {
+	KubernetesVersion   types.String          `tfsdk:"kubernetes_version"`
+	NodeCount           types.Int64           `tfsdk:"node_count"`
+	AvailableNamespaces []types.String        `tfsdk:"available_namespaces"`
+	Features            map[string]types.Bool `tfsdk:"features"`
+}
+
+func (d *NixernetesClusterDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster"
+}
+
+func (d *NixernetesClusterDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads information about the Kubernetes cluster behind the Nixernetes API server, for configuration that depends on what the cluster supports.",
+		Attributes: map[string]schema.Attribute{
+			"kubernetes_version": schema.StringAttribute{
+				MarkdownDescription: "Kubernetes version of the cluster, e.g. `v1.29.2`",
+				Computed:            true,
+			},
+			"node_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of nodes in the cluster",
+				Computed:            true,
+			},
+			"available_namespaces": schema.ListAttribute{
+				MarkdownDescription: "Namespaces modules can be deployed to",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"features": schema.MapAttribute{
+				MarkdownDescription: "Feature flags reported by the server, e.g. `{ autoscaling = true }`",
+				ElementType:         types.BoolType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NixernetesClusterDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesClusterDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	response, err := d.client.Get(ctx, "/cluster")
+	if httpErr, ok := err.(*HTTPError); ok {
+		switch httpErr.StatusCode {
+		case 404, 405, 501:
+			resp.Diagnostics.AddError(
+				"Cluster information not supported",
+				fmt.Sprintf("The Nixernetes API server does not provide GET /cluster (HTTP %d). "+
+					"It is probably older than this data source; upgrade the server or remove the nixernetes_cluster data source.", httpErr.StatusCode),
+			)
+			return
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading cluster", "Could not read cluster information: "+err.Error())
+		return
+	}
+
+	state := clusterFromResponse(response)
+
+	tflog.Debug(ctx, "Read cluster", map[string]any{
+		"kubernetes_version": state.KubernetesVersion.ValueString(),
+		"node_count":         state.NodeCount.ValueInt64(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// clusterFromResponse converts a GET /cluster response into cluster data.
+// Fields the server leaves out are null.
+func clusterFromResponse(response map[string]interface{}) NixernetesClusterDataSourceModel {
+	state := NixernetesClusterDataSourceModel{
+		KubernetesVersion: types.StringNull(),
+		NodeCount:         types.Int64Null(),
+	}
+
+	if version, ok := response["kubernetes_version"].(string); ok {
+		state.KubernetesVersion = types.StringValue(version)
+	}
+	if nodes, ok := response["node_count"].(float64); ok {
+		state.NodeCount = types.Int64Value(int64(nodes))
+	}
+	if namespaces, ok := response["available_namespaces"].([]interface{}); ok {
+		state.AvailableNamespaces = []types.String{}
+		for _, ns := range namespaces {
+			if name, ok := ns.(string); ok {
+				state.AvailableNamespaces = append(state.AvailableNamespaces, types.StringValue(name))
+			}
+		}
+	}
+	if features, ok := response["features"].(map[string]interface{}); ok {
+		state.Features = map[string]types.Bool{}
+		for name, value := range features {
+			if enabled, ok := value.(bool); ok {
+				state.Features[name] = types.BoolValue(enabled)
+			}
+		}
+	}
+
+	return state
+}
+
+// ========== Module Cost Data Source ==========
+
+// NewNixernetesModuleCostDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesModuleCostDataSource() datasource.DataSource {
+	return &NixernetesModuleCostDataSource{}
+}
+
+// NixernetesModuleCostDataSource reports the estimated cost of a module.
+type NixernetesModuleCostDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesModuleCostDataSourceModel struct {
+	ModuleID    types.String  `tfsdk:"module_id"`
+	MonthlyCost types.Float64 `tfsdk:"monthly_cost"`
+	CPUCost     types.Float64 `tfsdk:"cpu_cost"`
+	MemoryCost  types.Float64 `tfsdk:"memory_cost"`
+	Currency    types.String  `tfsdk:"currency"`
+}
+
+func (d *NixernetesModuleCostDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_cost"
+}
+
+func (d *NixernetesModuleCostDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads the estimated monthly cost of a module. The cost attributes are null, with a warning, when the server does not provide cost estimates.",
+		Attributes: map[string]schema.Attribute{
+			"module_id": schema.StringAttribute{
+				MarkdownDescription: "Module instance ID",
+				Required:            true,
+			},
+			"monthly_cost": schema.Float64Attribute{
+				MarkdownDescription: "Estimated total cost per month",
+				Computed:            true,
+			},
+			"cpu_cost": schema.Float64Attribute{
+				MarkdownDescription: "Part of `monthly_cost` for CPU",
+				Computed:            true,
+			},
+			"memory_cost": schema.Float64Attribute{
+				MarkdownDescription: "Part of `monthly_cost` for memory",
+				Computed:            true,
+			},
+			"currency": schema.StringAttribute{
+				MarkdownDescription: "ISO 4217 currency of the costs, e.g. `USD`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NixernetesModuleCostDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesModuleCostDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesModuleCostDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.MonthlyCost = types.Float64Null()
+	state.CPUCost = types.Float64Null()
+	state.MemoryCost = types.Float64Null()
+	state.Currency = types.StringNull()
+
+	cost, err := d.client.GetModuleCost(ctx, state.ModuleID.ValueString())
+	if httpErr, ok := err.(*HTTPError); ok {
+		switch httpErr.StatusCode {
+		case 405, 501:
+			resp.Diagnostics.AddWarning(
+				"Cost estimates not supported",
+				fmt.Sprintf("The Nixernetes API server does not provide cost estimates (HTTP %d), so the cost of module %s is unknown.", httpErr.StatusCode, state.ModuleID.ValueString()),
+			)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		case 404:
+			// Servers without the endpoint and missing modules both return
+			// 404; tell them apart by reading the module.
+			if _, err := d.client.Get(ctx, d.client.modulesPath()+"/"+url.PathEscape(state.ModuleID.ValueString())); err == nil {
+				resp.Diagnostics.AddWarning(
+					"Cost estimates not supported",
+					fmt.Sprintf("The Nixernetes API server does not provide cost estimates, so the cost of module %s is unknown.", state.ModuleID.ValueString()),
+				)
+				resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+				return
+			}
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading module cost",
+			"Could not read the cost of module "+state.ModuleID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.MonthlyCost = types.Float64Value(cost.MonthlyCost)
+	state.CPUCost = types.Float64Value(cost.CPUCost)
+	state.MemoryCost = types.Float64Value(cost.MemoryCost)
+	state.Currency = types.StringValue(cost.Currency)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ========== Deployments Data Source ==========
+
+// deploymentsPath is the API path listing what is running in the cluster.
+const deploymentsPath = "/deployments"
+
+// NewNixernetesDeploymentsDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesDeploymentsDataSource() datasource.DataSource {
+	return &NixernetesDeploymentsDataSource{}
+}
+
+// NixernetesDeploymentsDataSource lists the modules running in the cluster,
+// as the cluster reports them rather than as they are configured.
+type NixernetesDeploymentsDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesDeploymentsDataSourceModel struct {
+	Namespace   types.String               `tfsdk:"namespace"`
+	Deployments []NixernetesDeploymentData `tfsdk:"deployments"`
+}
+
+type NixernetesDeploymentData struct {
+	ModuleName    types.String `tfsdk:"module_name"`
+	Namespace     types.String `tfsdk:"namespace"`
+	Image         types.String `tfsdk:"image"`
+	Replicas      types.Int64  `tfsdk:"replicas"`
+	ReadyReplicas types.Int64  `tfsdk:"ready_replicas"`
+}
+
+func (d *NixernetesDeploymentsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployments"
+}
+
+func (d *NixernetesDeploymentsDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the modules running in the cluster. Unlike `nixernetes_modules`, this reflects the runtime state: the image and replica counts the cluster is actually running.",
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "Only list deployments in this namespace",
+				Optional:            true,
+			},
+			"deployments": schema.ListNestedAttribute{
+				MarkdownDescription: "List of running deployments",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"module_name": schema.StringAttribute{
+							MarkdownDescription: "Name of the deployed module",
+							Computed:            true,
+						},
+						"namespace": schema.StringAttribute{
+							MarkdownDescription: "Namespace the module runs in",
+							Computed:            true,
+						},
+						"image": schema.StringAttribute{
+							MarkdownDescription: "Container image currently running",
+							Computed:            true,
+						},
+						"replicas": schema.Int64Attribute{
+							MarkdownDescription: "Number of replicas the deployment runs",
+							Computed:            true,
+						},
+						"ready_replicas": schema.Int64Attribute{
+							MarkdownDescription: "Number of replicas that are ready",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *NixernetesDeploymentsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesDeploymentsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesDeploymentsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := state.Namespace.ValueString()
+	if !state.Namespace.IsNull() && !isValidNamespace(namespace) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("namespace"),
+			"Invalid namespace",
+			fmt.Sprintf("Namespace must be a valid Kubernetes namespace name, got: %s", namespace),
+		)
+		return
+	}
+
+	query := url.Values{}
+	if namespace != "" {
+		query.Set("namespace", namespace)
+	}
+	deployments, err := listAllPages(ctx, d.client, deploymentsPath, query, "deployments")
+	if err == nil {
+		state.Deployments, err = deploymentListFromResponse(map[string]interface{}{"deployments": deployments})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading deployments",
+			"Could not read deployments, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	// Servers that ignore the namespace filter list every deployment
+	if namespace != "" {
+		filtered := []NixernetesDeploymentData{}
+		for _, deployment := range state.Deployments {
+			if deployment.Namespace.ValueString() == namespace {
+				filtered = append(filtered, deployment)
+			}
+		}
+		state.Deployments = filtered
+	}
+
+	tflog.Debug(ctx, "Read deployments", map[string]any{
+		"namespace": namespace,
+		"count":     len(state.Deployments),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// deploymentListFromResponse converts a GET /deployments response into
+// deployment data. Replica counts the server leaves out are null.
+func deploymentListFromResponse(response map[string]interface{}) ([]NixernetesDeploymentData, error) {
+	deployments, err := listFromResponse(response, "deployments")
+	if err != nil {
+		return nil, err
+	}
+	result := []NixernetesDeploymentData{}
+	for i, deployment := range deployments {
+		fields, err := requireStrings(deployment, "module_name", "namespace")
+		if err != nil {
+			return nil, fmt.Errorf("deployments[%d]: %w", i, err)
+		}
+		result = append(result, NixernetesDeploymentData{
+			ModuleName:    types.StringValue(fields["module_name"]),
+			Namespace:     types.StringValue(fields["namespace"]),
+			Image:         stringFromResponse(deployment, "image"),
+			Replicas:      int64FromResponse(deployment, "replicas"),
+			ReadyReplicas: int64FromResponse(deployment, "ready_replicas"),
+		})
+	}
+	return result, nil
+}
+
+// int64FromResponse returns the numeric field key of response, or null when
+// the server leaves it out.
+func int64FromResponse(response map[string]interface{}, key string) types.Int64 {
+	if n, ok := response[key].(float64); ok {
+		return types.Int64Value(int64(n))
+	}
+	return types.Int64Null()
+}
+
+// ========== Config Version Data Source ==========
+
+// configVersionsPath is the path, under a configuration, of its versions.
+const configVersionsPath = "/versions"
+
+// latestConfigVersion is the version segment the API resolves to the
+// configuration's current version.
+const latestConfigVersion = "latest"
+
+// NewNixernetesConfigVersionDataSource is a helper function to simplify the provider implementation.
+func NewNixernetesConfigVersionDataSource() datasource.DataSource {
+	return &NixernetesConfigVersionDataSource{}
+}
+
+// NixernetesConfigVersionDataSource reads one version from the history of a
+// configuration, e.g. to pin a module to it or diff it against the current one.
+type NixernetesConfigVersionDataSource struct {
+	client *NixernetesClient
+}
+
+type NixernetesConfigVersionDataSourceModel struct {
+	ConfigID      types.String `tfsdk:"config_id"`
+	Version       types.Int64  `tfsdk:"version"`
+	Configuration types.String `tfsdk:"configuration"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+}
+
+func (d *NixernetesConfigVersionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_version"
+}
+
+func (d *NixernetesConfigVersionDataSource) Schema(ctx context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads a version from the history of a Nixernetes configuration. Every update of a configuration creates a new version.",
+		Attributes: map[string]schema.Attribute{
+			"config_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the configuration",
+				Required:            true,
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "Version number to read, starting at 1. Defaults to the latest version, and is set to its number.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"configuration": schema.StringAttribute{
+				MarkdownDescription: "Nix configuration content of the version",
+				Computed:            true,
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Timestamp at which the version was created",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *NixernetesConfigVersionDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	d.client = client
+}
+
+func (d *NixernetesConfigVersionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state NixernetesConfigVersionDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	version := latestConfigVersion
+	if !state.Version.IsNull() && !state.Version.IsUnknown() {
+		if state.Version.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("version"),
+				"Invalid version",
+				fmt.Sprintf("version must be at least 1, got: %d", state.Version.ValueInt64()),
+			)
+			return
+		}
+		version = strconv.FormatInt(state.Version.ValueInt64(), 10)
+	}
+
+	configID := state.ConfigID.ValueString()
+	response, err := d.client.Get(ctx, d.client.configsPath()+"/"+url.PathEscape(configID)+configVersionsPath+"/"+version)
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+		if version == latestConfigVersion {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("config_id"),
+				"Configuration not found",
+				fmt.Sprintf("Configuration %s does not exist or has no versions.", configID),
+			)
+		} else {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("version"),
+				"Configuration version not found",
+				fmt.Sprintf("Configuration %s has no version %s, or does not exist.", configID, version),
+			)
+		}
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading configuration version",
+			"Could not read version "+version+" of configuration "+configID+": "+err.Error(),
+		)
+		return
+	}
+
+	fields, err := requireStrings(response, "configuration")
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading configuration version",
+			"Could not read version "+version+" of configuration "+configID+": "+err.Error(),
+		)
+		return
+	}
+
+	if state.Version.IsNull() || state.Version.IsUnknown() {
+		state.Version = int64FromResponse(response, "version")
 	}
+	state.Configuration = types.StringValue(fields["configuration"])
+	state.CreatedAt = stringFromResponse(response, "created_at")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
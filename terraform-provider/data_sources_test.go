@@ -0,0 +1,945 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testDataSourceRead runs a data source Read with the given configuration
+// model and decodes the resulting state into target.
+func testDataSourceRead(t *testing.T, d datasource.DataSource, config interface{}, target interface{}) datasource.ReadResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	s := schemaResp.Schema
+	raw := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	if diags := raw.Set(ctx, config); diags.HasError() {
+		t.Fatalf("Unexpected config diagnostics: %v", diags)
+	}
+
+	req := datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: raw.Raw}}
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: s, Raw: raw.Raw}}
+	d.Read(ctx, req, &resp)
+
+	if target != nil && !resp.Diagnostics.HasError() {
+		if diags := resp.State.Get(ctx, target); diags.HasError() {
+			t.Fatalf("Unexpected state diagnostics: %v", diags)
+		}
+	}
+	return resp
+}
+
+func TestModuleEventsDataSourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"events": []map[string]interface{}{
+				{"type": "Normal", "reason": "Started", "message": "Started container", "timestamp": "2024-02-04T00:01:00Z", "count": 1},
+				{"type": "Warning", "reason": "Unhealthy", "message": "Readiness probe failed", "timestamp": "2024-02-04T00:02:00Z", "count": 3},
+			},
+		})
+	}))
+	defer server.Close()
+
+	d := &NixernetesModuleEventsDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesModuleEventsDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModuleEventsDataSourceModel{
+		ModuleID: types.StringValue("mod-123"),
+		Since:    types.StringNull(),
+	}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(got.Events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(got.Events))
+	}
+	if got.Events[0].Reason.ValueString() != "Unhealthy" {
+		t.Errorf("Expected newest event first, got %s", got.Events[0].Reason.ValueString())
+	}
+	if got.Events[0].Count.ValueInt64() != 3 {
+		t.Errorf("Expected count 3, got %d", got.Events[0].Count.ValueInt64())
+	}
+}
+
+func TestModulesDataSourceConsistentRead(t *testing.T) {
+	consistentReadInterval = time.Millisecond
+	defer func() { consistentReadInterval = 2 * time.Second }()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		modules := []map[string]interface{}{}
+		if calls >= 3 {
+			modules = append(modules, map[string]interface{}{
+				"id": "mod-1", "name": "api", "description": "API server", "version": "1.0.0",
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"modules": modules})
+	}))
+	defer server.Close()
+
+	d := &NixernetesModulesDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesModulesDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModulesDataSourceModel{
+		ConsistentRead:        types.BoolValue(true),
+		ExpectMinCount:        types.Int64Value(1),
+		ConsistentReadTimeout: types.StringValue("5s"),
+	}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if calls != 3 {
+		t.Errorf("Expected 3 list calls, got %d", calls)
+	}
+	if len(got.Modules) != 1 {
+		t.Errorf("Expected 1 module, got %d", len(got.Modules))
+	}
+}
+
+func TestModulesDataSourceProjectFilter(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("project_id")
+		// Ignore the filter, as an older server would
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"modules": []map[string]interface{}{
+			{"id": "mod-1", "name": "api", "description": "API", "version": "1.0", "project_id": "proj-1"},
+			{"id": "mod-2", "name": "worker", "description": "Worker", "version": "1.0", "project_id": "proj-2"},
+		}})
+	}))
+	defer server.Close()
+
+	d := &NixernetesModulesDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesModulesDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModulesDataSourceModel{ProjectID: types.StringValue("proj-1")}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if query != "proj-1" {
+		t.Errorf("Expected project_id query proj-1, got %q", query)
+	}
+	if len(got.Modules) != 1 || got.Modules[0].ID.ValueString() != "mod-1" {
+		t.Errorf("Expected only mod-1, got %v", got.Modules)
+	}
+}
+
+func TestModulesDataSourceProjectWithoutModules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"modules":null}`))
+	}))
+	defer server.Close()
+
+	d := &NixernetesModulesDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesModulesDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModulesDataSourceModel{ProjectID: types.StringValue("proj-empty")}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if got.Modules == nil || len(got.Modules) != 0 {
+		t.Errorf("Expected an empty module list, got %v", got.Modules)
+	}
+}
+
+func TestModulesDataSourceInvalidProjectID(t *testing.T) {
+	server := newUnreachableServer(t)
+	d := &NixernetesModulesDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	resp := testDataSourceRead(t, d, NixernetesModulesDataSourceModel{ProjectID: types.StringValue("proj/1")}, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected error for invalid project ID")
+	}
+}
+
+func TestModulesDataSourcePagination(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"modules": []map[string]interface{}{
+					{"id": "mod-1", "name": "api", "description": "API", "version": "1.0"},
+				},
+				"next_page": "page-2",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"modules": []map[string]interface{}{
+				{"id": "mod-2", "name": "worker", "description": "Worker", "version": "1.0"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	d := &NixernetesModulesDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesModulesDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModulesDataSourceModel{PageSize: types.Int64Value(1)}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 list calls, got %d", len(queries))
+	}
+	if queries[0].Get("page_size") != "1" || queries[1].Get("page_size") != "1" {
+		t.Errorf("Expected page_size 1 on every page, got %v", queries)
+	}
+	if queries[1].Get("cursor") != "page-2" {
+		t.Errorf("Expected cursor page-2 on the second page, got %q", queries[1].Get("cursor"))
+	}
+	if len(got.Modules) != 2 || got.Modules[0].ID.ValueString() != "mod-1" || got.Modules[1].ID.ValueString() != "mod-2" {
+		t.Errorf("Expected mod-1 and mod-2, got %v", got.Modules)
+	}
+}
+
+func TestModulesDataSourceRepeatedCursor(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"modules":[],"next":"same"}`))
+	}))
+	defer server.Close()
+
+	d := &NixernetesModulesDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	resp := testDataSourceRead(t, d, NixernetesModulesDataSourceModel{}, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected error for a repeated cursor")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 list calls, got %d", calls)
+	}
+}
+
+func TestProjectsDataSourcePagination(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"projects":  []map[string]interface{}{{"id": "proj-1", "name": "shop", "status": "active"}},
+				"next_page": "page-2",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"projects": []map[string]interface{}{{"id": "proj-2", "name": "shop-api", "status": "active"}},
+		})
+	}))
+	defer server.Close()
+
+	d := &NixernetesProjectsDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesProjectsDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesProjectsDataSourceModel{PageSize: types.Int64Value(1)}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(queries) != 2 || queries[1].Get("cursor") != "page-2" || queries[1].Get("page_size") != "1" {
+		t.Fatalf("Expected a second call with cursor page-2 and page_size 1, got %v", queries)
+	}
+	if len(got.Projects) != 2 || got.Projects[0].ID.ValueString() != "proj-1" || got.Projects[1].ID.ValueString() != "proj-2" {
+		t.Errorf("Expected proj-1 and proj-2, got %v", got.Projects)
+	}
+}
+
+func TestProjectsDataSourceFilters(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		// Like an older server, ignore the filters
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"projects": []map[string]interface{}{
+				{"id": "proj-1", "name": "shop", "status": "active"},
+				{"id": "proj-2", "name": "shop-api", "status": "paused"},
+				{"id": "proj-3", "name": "blog", "status": "active"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	d := &NixernetesProjectsDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesProjectsDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesProjectsDataSourceModel{
+		Status:     types.StringValue("active"),
+		NamePrefix: types.StringValue("shop"),
+	}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if query.Get("status") != "active" || query.Get("name_prefix") != "shop" {
+		t.Errorf("Expected the filters as query parameters, got %v", query)
+	}
+	if len(got.Projects) != 1 || got.Projects[0].ID.ValueString() != "proj-1" {
+		t.Errorf("Expected only proj-1, got %v", got.Projects)
+	}
+}
+
+func TestProjectsDataSourceInvalidStatus(t *testing.T) {
+	d := &NixernetesProjectsDataSource{client: &NixernetesClient{Endpoint: newUnreachableServer(t).URL}}
+
+	resp := testDataSourceRead(t, d, NixernetesProjectsDataSourceModel{Status: types.StringValue("running")}, nil)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Invalid status" {
+		t.Errorf("Expected an invalid status error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestListDataSourcesPartialResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/modules":
+			w.Write([]byte(`{"modules":[{"id":"mod-1","name":"api"},{"id":"mod-2"}]}`))
+		case "/projects":
+			w.Write([]byte(`{"projects":"none"}`))
+		case "/configs":
+			w.Write([]byte(`{"configs":[{"id":"cfg-1","name":"web"}]}`))
+		}
+	}))
+	defer server.Close()
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	resp := testDataSourceRead(t, &NixernetesModulesDataSource{client: client}, NixernetesModulesDataSourceModel{}, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "modules[1]: response is missing the string field(s) name") {
+		t.Errorf("Expected an error naming modules[1].name, got %v", resp.Diagnostics)
+	}
+
+	resp = testDataSourceRead(t, &NixernetesProjectsDataSource{client: client}, NixernetesProjectsDataSourceModel{}, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "projects is not a list") {
+		t.Errorf("Expected an error for the projects field, got %v", resp.Diagnostics)
+	}
+
+	// The inventory reports malformed categories like unreadable ones
+	var got NixernetesInventoryDataSourceModel
+	resp = testDataSourceRead(t, &NixernetesInventoryDataSource{client: client}, NixernetesInventoryDataSourceModel{}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if len(got.FailedCategories) != 2 || len(got.Configs) != 1 || !got.Configs[0].Environment.IsNull() {
+		t.Errorf("Expected modules and projects to fail and one config without environment, got %+v", got)
+	}
+}
+
+func TestReadListTimeout(t *testing.T) {
+	consistentReadInterval = time.Millisecond
+	defer func() { consistentReadInterval = 2 * time.Second }()
+
+	read := func() (int, error) { return 0, nil }
+	err := readList(context.Background(), types.BoolValue(true), types.Int64Value(2), types.StringValue("20ms"), read)
+	if err == nil {
+		t.Fatal("Expected timeout error")
+	}
+}
+
+func TestReadListCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	read := func() (int, error) { return 0, nil }
+	err := readList(ctx, types.BoolValue(true), types.Int64Null(), types.StringNull(), read)
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestReadListSingleAttempt(t *testing.T) {
+	calls := 0
+	read := func() (int, error) { calls++; return 0, nil }
+	err := readList(context.Background(), types.BoolNull(), types.Int64Value(5), types.StringNull(), read)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single read without consistent_read, got %d", calls)
+	}
+}
+
+// newInventoryServer serves the three list endpoints, failing those in failing.
+func newInventoryServer(t *testing.T, failing ...string) *httptest.Server {
+	t.Helper()
+
+	lists := map[string]interface{}{
+		"/configs": map[string]interface{}{"configs": []map[string]interface{}{
+			{"id": "config-1", "name": "web", "environment": "production"},
+		}},
+		"/modules": map[string]interface{}{"modules": []map[string]interface{}{
+			{"id": "mod-1", "name": "api", "description": "API", "version": "1.0"},
+			{"id": "mod-2", "name": "worker", "description": "Worker", "version": "2.0"},
+		}},
+		"/projects": map[string]interface{}{"projects": []map[string]interface{}{
+			{"id": "proj-1", "name": "shop", "description": "Shop", "status": "active"},
+		}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, f := range failing {
+			if r.URL.Path == f {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":"list unavailable"}`))
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(lists[r.URL.Path])
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestInventoryDataSourceRead(t *testing.T) {
+	server := newInventoryServer(t)
+	d := &NixernetesInventoryDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesInventoryDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesInventoryDataSourceModel{}, &got)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() > 0 {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(got.Configs) != 1 || got.Configs[0].Environment.ValueString() != "production" {
+		t.Errorf("Unexpected configs: %v", got.Configs)
+	}
+	if len(got.Modules) != 2 {
+		t.Errorf("Expected 2 modules, got %d", len(got.Modules))
+	}
+	if len(got.Projects) != 1 || got.Projects[0].Status.ValueString() != "active" {
+		t.Errorf("Unexpected projects: %v", got.Projects)
+	}
+	if len(got.FailedCategories) != 0 {
+		t.Errorf("Expected no failed categories, got %v", got.FailedCategories)
+	}
+}
+
+func TestInventoryDataSourceReadPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		category := strings.TrimPrefix(r.URL.Path, "/")
+		singular := strings.TrimSuffix(category, "s")
+		page := map[string]interface{}{
+			category: []map[string]interface{}{
+				{"id": singular + "-1", "name": "first", "status": "active"},
+			},
+			"next_page": "page-2",
+		}
+		if r.URL.Query().Get("cursor") == "page-2" {
+			page = map[string]interface{}{
+				category: []map[string]interface{}{
+					{"id": singular + "-2", "name": "second", "status": "active"},
+				},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	d := &NixernetesInventoryDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesInventoryDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesInventoryDataSourceModel{}, &got)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() > 0 {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if len(got.Configs) != 2 || got.Configs[1].ID.ValueString() != "config-2" {
+		t.Errorf("Expected configs from both pages, got %v", got.Configs)
+	}
+	if len(got.Modules) != 2 || got.Modules[1].ID.ValueString() != "module-2" {
+		t.Errorf("Expected modules from both pages, got %v", got.Modules)
+	}
+	if len(got.Projects) != 2 || got.Projects[1].ID.ValueString() != "project-2" {
+		t.Errorf("Expected projects from both pages, got %v", got.Projects)
+	}
+}
+
+func TestInventoryDataSourcePartialFailure(t *testing.T) {
+	server := newInventoryServer(t, "/modules")
+	d := &NixernetesInventoryDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	var got NixernetesInventoryDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesInventoryDataSourceModel{}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected error diagnostics: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("Expected 1 warning, got %v", resp.Diagnostics)
+	}
+
+	if len(got.FailedCategories) != 1 || got.FailedCategories[0].ValueString() != "modules" {
+		t.Errorf("Expected modules to be reported as failed, got %v", got.FailedCategories)
+	}
+	if got.Modules != nil {
+		t.Errorf("Expected no modules, got %v", got.Modules)
+	}
+	if len(got.Configs) != 1 || len(got.Projects) != 1 {
+		t.Errorf("Expected configs and projects to be returned, got %v and %v", got.Configs, got.Projects)
+	}
+}
+
+func TestInventoryDataSourceAllFailed(t *testing.T) {
+	server := newInventoryServer(t, "/configs", "/modules", "/projects")
+	d := &NixernetesInventoryDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	resp := testDataSourceRead(t, d, NixernetesInventoryDataSourceModel{}, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected an error when every category fails")
+	}
+}
+
+func TestClusterDataSourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cluster" {
+			t.Errorf("Expected path /cluster, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"kubernetes_version":   "v1.29.2",
+			"node_count":           5,
+			"available_namespaces": []string{"default", "team-payments"},
+			"features":             map[string]bool{"autoscaling": true, "gpu": false},
+		})
+	}))
+	defer server.Close()
+
+	d := &NixernetesClusterDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	var got NixernetesClusterDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesClusterDataSourceModel{}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if got.KubernetesVersion.ValueString() != "v1.29.2" || got.NodeCount.ValueInt64() != 5 {
+		t.Errorf("Expected v1.29.2 with 5 nodes, got %v with %v", got.KubernetesVersion, got.NodeCount)
+	}
+	if len(got.AvailableNamespaces) != 2 || got.AvailableNamespaces[1].ValueString() != "team-payments" {
+		t.Errorf("Expected namespaces default and team-payments, got %v", got.AvailableNamespaces)
+	}
+	if !got.Features["autoscaling"].ValueBool() || got.Features["gpu"].ValueBool() {
+		t.Errorf("Expected autoscaling enabled and gpu disabled, got %v", got.Features)
+	}
+}
+
+func TestClusterDataSourceUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	d := &NixernetesClusterDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	resp := testDataSourceRead(t, d, NixernetesClusterDataSourceModel{}, nil)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Cluster information not supported" {
+		t.Errorf("Expected unsupported server error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestDeploymentsDataSourceRead(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/deployments" {
+			t.Errorf("Expected path /deployments, got %s", r.URL.Path)
+		}
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		// Like an older server, ignore the namespace filter
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"deployments": []map[string]interface{}{
+				{"module_name": "api", "namespace": "team-payments", "image": "ghcr.io/acme/api:1.4.0", "replicas": 3, "ready_replicas": 2},
+				{"module_name": "worker", "namespace": "team-payments"},
+				{"module_name": "web", "namespace": "default", "image": "nginx:1.25", "replicas": 1, "ready_replicas": 1},
+			},
+		})
+	}))
+	defer server.Close()
+
+	d := &NixernetesDeploymentsDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	var got NixernetesDeploymentsDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesDeploymentsDataSourceModel{Namespace: types.StringValue("team-payments")}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if query.Get("namespace") != "team-payments" {
+		t.Errorf("Expected the namespace as a query parameter, got %v", query)
+	}
+	if len(got.Deployments) != 2 {
+		t.Fatalf("Expected 2 deployments in team-payments, got %v", got.Deployments)
+	}
+	api := got.Deployments[0]
+	if api.ModuleName.ValueString() != "api" || api.Image.ValueString() != "ghcr.io/acme/api:1.4.0" ||
+		api.Replicas.ValueInt64() != 3 || api.ReadyReplicas.ValueInt64() != 2 {
+		t.Errorf("Expected api running 1.4.0 with 2 of 3 replicas ready, got %+v", api)
+	}
+	if worker := got.Deployments[1]; !worker.Image.IsNull() || !worker.Replicas.IsNull() || !worker.ReadyReplicas.IsNull() {
+		t.Errorf("Expected null image and replica counts for worker, got %+v", worker)
+	}
+}
+
+func TestDeploymentsDataSourceInvalidNamespace(t *testing.T) {
+	d := &NixernetesDeploymentsDataSource{client: &NixernetesClient{Endpoint: newUnreachableServer(t).URL}}
+
+	resp := testDataSourceRead(t, d, NixernetesDeploymentsDataSourceModel{Namespace: types.StringValue("Team_Payments")}, nil)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics[0].Summary() != "Invalid namespace" {
+		t.Errorf("Expected an invalid namespace error, got %v", resp.Diagnostics)
+	}
+}
+
+// newModuleStatusServer serves the status of module mod-123, reporting it
+// Pending until the readyAfter-th request and Ready from then on.
+func newModuleStatusServer(t *testing.T, readyAfter int) (*httptest.Server, *int) {
+	t.Helper()
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/modules/mod-123/status" {
+			t.Errorf("Expected path /modules/mod-123/status, got %s", r.URL.Path)
+		}
+		polls++
+		status := map[string]interface{}{"phase": "Pending", "ready_replicas": 0, "available_replicas": 0}
+		if polls >= readyAfter {
+			status = map[string]interface{}{"phase": "Ready", "ready_replicas": 2, "available_replicas": 2, "last_transition_time": "2024-05-01T12:00:00Z"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &polls
+}
+
+func TestModuleStatusDataSourceRead(t *testing.T) {
+	server, polls := newModuleStatusServer(t, 1)
+
+	d := &NixernetesModuleStatusDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	var got NixernetesModuleStatusDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModuleStatusDataSourceModel{ModuleID: types.StringValue("mod-123")}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if *polls != 1 {
+		t.Errorf("Expected a single status request, got %d", *polls)
+	}
+	if got.Phase.ValueString() != "Ready" || got.ReadyReplicas.ValueInt64() != 2 || got.AvailableReplicas.ValueInt64() != 2 {
+		t.Errorf("Expected Ready with 2 replicas, got %+v", got)
+	}
+	if got.LastTransitionTime.ValueString() != "2024-05-01T12:00:00Z" {
+		t.Errorf("Expected the last transition time, got %v", got.LastTransitionTime)
+	}
+}
+
+func TestModuleStatusDataSourceWaitForReady(t *testing.T) {
+	moduleReadyPollInterval = time.Millisecond
+	defer func() { moduleReadyPollInterval = 5 * time.Second }()
+
+	server, polls := newModuleStatusServer(t, 3)
+
+	d := &NixernetesModuleStatusDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	var got NixernetesModuleStatusDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModuleStatusDataSourceModel{
+		ModuleID:     types.StringValue("mod-123"),
+		WaitForReady: types.BoolValue(true),
+		Timeout:      types.StringValue("10s"),
+	}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if *polls < 3 {
+		t.Errorf("Expected to poll until ready, got %d requests", *polls)
+	}
+	if got.Phase.ValueString() != "Ready" || got.ReadyReplicas.ValueInt64() != 2 {
+		t.Errorf("Expected Ready with 2 replicas, got %+v", got)
+	}
+}
+
+func TestModuleStatusDataSourceWaitForReadyTimeout(t *testing.T) {
+	moduleReadyPollInterval = time.Millisecond
+	defer func() { moduleReadyPollInterval = 5 * time.Second }()
+
+	server, _ := newModuleStatusServer(t, 1000000)
+
+	d := &NixernetesModuleStatusDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	resp := testDataSourceRead(t, d, NixernetesModuleStatusDataSourceModel{
+		ModuleID:     types.StringValue("mod-123"),
+		WaitForReady: types.BoolValue(true),
+		Timeout:      types.StringValue("20ms"),
+	}, nil)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Module not ready" {
+		t.Errorf("Expected a not ready error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestProjectDataSourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/projects/proj-1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":                "proj-1",
+				"name":              "payments",
+				"status":            "active",
+				"default_namespace": "team-payments",
+				"paused":            false,
+			})
+		case "/modules":
+			if r.URL.Query().Get("project_id") != "proj-1" {
+				t.Errorf("Expected modules to be listed by project, got %s", r.URL.RequestURI())
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"modules": []map[string]interface{}{
+				{"id": "mod-1", "name": "api", "project_id": "proj-1"},
+				{"id": "mod-2", "name": "other", "project_id": "proj-2"},
+			}})
+		case "/configs":
+			json.NewEncoder(w).Encode(map[string]interface{}{"configs": []map[string]interface{}{
+				{"id": "config-1", "name": "web", "project_id": "proj-1"},
+				{"id": "config-2", "name": "unowned"},
+			}})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.RequestURI())
+		}
+	}))
+	defer server.Close()
+
+	d := &NixernetesProjectDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	var got NixernetesProjectDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesProjectDataSourceModel{ID: types.StringValue("proj-1")}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if got.Name.ValueString() != "payments" || got.DefaultNamespace.ValueString() != "team-payments" {
+		t.Errorf("Unexpected project: %+v", got)
+	}
+	if len(got.ModuleIDs) != 1 || got.ModuleIDs[0].ValueString() != "mod-1" {
+		t.Errorf("Expected module mod-1, got %v", got.ModuleIDs)
+	}
+	if len(got.ConfigIDs) != 1 || got.ConfigIDs[0].ValueString() != "config-1" {
+		t.Errorf("Expected config config-1, got %v", got.ConfigIDs)
+	}
+	if !strings.Contains(got.ImportBlocks.ValueString(), "to = nixernetes_module.api\n  id = \"mod-1\"") {
+		t.Errorf("Expected an import block for mod-1, got %q", got.ImportBlocks.ValueString())
+	}
+}
+
+func TestConfigDataSourceRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		configs     []map[string]interface{}
+		wantSummary string
+	}{
+		{"found", []map[string]interface{}{{"id": "config-1", "name": "web"}, {"id": "config-2", "name": "web-staging"}}, ""},
+		{"not found", []map[string]interface{}{{"id": "config-2", "name": "web-staging"}}, "Configuration not found"},
+		{"ambiguous", []map[string]interface{}{{"id": "config-1", "name": "web"}, {"id": "config-3", "name": "web"}}, "Multiple configurations found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/configs":
+					if r.URL.Query().Get("name") != "web" {
+						t.Errorf("Expected configs to be listed by name, got %s", r.URL.RequestURI())
+					}
+					// Returns non-matching names too, like servers that ignore the filter
+					json.NewEncoder(w).Encode(map[string]interface{}{"configs": tt.configs})
+				case "/configs/config-1":
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"id":            "config-1",
+						"name":          "web",
+						"configuration": "{ }",
+						"environment":   "production",
+						"created_at":    "2024-02-04T00:00:00Z",
+						"updated_at":    "2024-02-05T00:00:00Z",
+					})
+				default:
+					t.Errorf("Unexpected request %s", r.URL.RequestURI())
+				}
+			}))
+			defer server.Close()
+
+			d := &NixernetesConfigDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+			var got NixernetesConfigDataSourceModel
+			resp := testDataSourceRead(t, d, NixernetesConfigDataSourceModel{Name: types.StringValue("web")}, &got)
+
+			if tt.wantSummary != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantSummary {
+					t.Errorf("Expected %q error, got %v", tt.wantSummary, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if got.ID.ValueString() != "config-1" || got.Configuration.ValueString() != "{ }" || got.Environment.ValueString() != "production" {
+				t.Errorf("Unexpected configuration: %+v", got)
+			}
+		})
+	}
+}
+
+func TestModuleCostDataSourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/modules/mod-1/cost" {
+			t.Errorf("Expected path /modules/mod-1/cost, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"monthly_cost": 42.5,
+			"cpu_cost":     30,
+			"memory_cost":  12.5,
+			"currency":     "EUR",
+		})
+	}))
+	defer server.Close()
+
+	d := &NixernetesModuleCostDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+	var got NixernetesModuleCostDataSourceModel
+	resp := testDataSourceRead(t, d, NixernetesModuleCostDataSourceModel{ModuleID: types.StringValue("mod-1")}, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if got.MonthlyCost.ValueFloat64() != 42.5 || got.CPUCost.ValueFloat64() != 30 || got.MemoryCost.ValueFloat64() != 12.5 {
+		t.Errorf("Unexpected costs: %+v", got)
+	}
+	if got.Currency.ValueString() != "EUR" {
+		t.Errorf("Expected currency EUR, got %v", got.Currency)
+	}
+}
+
+func TestModuleCostDataSourceUnsupported(t *testing.T) {
+	tests := []struct {
+		name        string
+		moduleFound bool
+		wantError   bool
+	}{
+		{"endpoint missing", true, false},
+		{"module missing", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/modules/mod-1" && tt.moduleFound {
+					w.Header().Set("Content-Type", "application/json")
+					json.NewEncoder(w).Encode(map[string]interface{}{"id": "mod-1"})
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer server.Close()
+
+			d := &NixernetesModuleCostDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+			var got NixernetesModuleCostDataSourceModel
+			resp := testDataSourceRead(t, d, NixernetesModuleCostDataSourceModel{ModuleID: types.StringValue("mod-1")}, &got)
+
+			if tt.wantError {
+				if !resp.Diagnostics.HasError() {
+					t.Error("Expected an error for a missing module")
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() || len(resp.Diagnostics.Warnings()) != 1 {
+				t.Fatalf("Expected only a warning, got %v", resp.Diagnostics)
+			}
+			if !got.MonthlyCost.IsNull() || !got.Currency.IsNull() {
+				t.Errorf("Expected null costs, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestConfigVersionDataSourceRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		versions := map[string]map[string]interface{}{
+			"1": {"version": 1, "configuration": "{ }", "created_at": "2024-02-04T00:00:00Z"},
+			"2": {"version": 2, "configuration": "{ services.nginx.enable = true; }", "created_at": "2024-02-05T00:00:00Z"},
+		}
+		versions["latest"] = versions["2"]
+		version, ok := versions[strings.TrimPrefix(r.URL.Path, "/configs/config-1/versions/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"message": "version not found"})
+			return
+		}
+		json.NewEncoder(w).Encode(version)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name              string
+		version           types.Int64
+		wantVersion       int64
+		wantConfiguration string
+		wantSummary       string
+	}{
+		{"latest", types.Int64Null(), 2, "{ services.nginx.enable = true; }", ""},
+		{"explicit", types.Int64Value(1), 1, "{ }", ""},
+		{"nonexistent", types.Int64Value(7), 0, "", "Configuration version not found"},
+		{"invalid", types.Int64Value(0), 0, "", "Invalid version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &NixernetesConfigVersionDataSource{client: &NixernetesClient{Endpoint: server.URL}}
+			var got NixernetesConfigVersionDataSourceModel
+			resp := testDataSourceRead(t, d, NixernetesConfigVersionDataSourceModel{
+				ConfigID: types.StringValue("config-1"),
+				Version:  tt.version,
+			}, &got)
+
+			if tt.wantSummary != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantSummary {
+					t.Errorf("Expected %q error, got %v", tt.wantSummary, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if got.Version.ValueInt64() != tt.wantVersion {
+				t.Errorf("Expected version %d, got %v", tt.wantVersion, got.Version)
+			}
+			if got.Configuration.ValueString() != tt.wantConfiguration {
+				t.Errorf("Expected configuration %q, got %v", tt.wantConfiguration, got.Configuration)
+			}
+			if got.CreatedAt.IsNull() {
+				t.Error("Expected created_at to be set")
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &NixernetesDeployTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &NixernetesDeployTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &NixernetesDeployTokenEphemeralResource{}
+)
+
+// ========== Deploy Token Ephemeral Resource ==========
+
+func NewNixernetesDeployTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &NixernetesDeployTokenEphemeralResource{}
+}
+
+// NixernetesDeployTokenEphemeralResource mints a short-lived deploy token.
+// The token is never written to plan or state, and is revoked once Terraform
+// no longer needs it.
+type NixernetesDeployTokenEphemeralResource struct {
+	client *NixernetesClient
+}
+
+// NixernetesDeployTokenModel describes the ephemeral resource data model.
+type NixernetesDeployTokenModel struct {
+	Scopes    types.List   `tfsdk:"scopes"`
+	TTL       types.String `tfsdk:"ttl"`
+	Token     types.String `tfsdk:"token"`
+	ExpiresAt types.String `tfsdk:"expires_at"`
+}
+
+// tokensPath is the API path under which deploy tokens are minted.
+const tokensPath = "/tokens"
+
+// deployTokenPrivateKey is the private data key holding the ID of an open
+// token, for Close to revoke it.
+const deployTokenPrivateKey = "token_id"
+
+func (r *NixernetesDeployTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deploy_token"
+}
+
+func (r *NixernetesDeployTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Mints a short-lived deploy token, for example to hand a scoped token to another provider in a CI run. The token is never stored in the plan or state, and is revoked when Terraform is done with it.",
+
+		Attributes: map[string]schema.Attribute{
+			"scopes": schema.ListAttribute{
+				MarkdownDescription: "Scopes granted to the token, such as `modules:write`. The server's default scopes apply when unset.",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"ttl": schema.StringAttribute{
+				MarkdownDescription: "How long the token is valid, as a duration such as `15m`. The server's default lifetime applies when unset.",
+				Optional:            true,
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The deploy token",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "Expiry timestamp of the token",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *NixernetesDeployTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	r.client = client
+}
+
+// parseTokenTTL parses the ttl attribute, which is zero when unset.
+func parseTokenTTL(value types.String) (time.Duration, error) {
+	if value.IsNull() || value.IsUnknown() {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(value.ValueString())
+	if err != nil || ttl < time.Second {
+		return 0, fmt.Errorf("ttl must be a duration of at least one second such as \"15m\", got: %s", value.ValueString())
+	}
+	return ttl, nil
+}
+
+// Open mints the token.
+func (r *NixernetesDeployTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data NixernetesDeployTokenModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ttl, err := parseTokenTTL(data.TTL)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid ttl", err.Error())
+		return
+	}
+
+	body := map[string]interface{}{}
+	if ttl > 0 {
+		body["ttl_seconds"] = int64(ttl / time.Second)
+	}
+	if !data.Scopes.IsNull() && !data.Scopes.IsUnknown() {
+		var scopes []string
+		resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		body["scopes"] = scopes
+	}
+
+	response, err := r.client.Post(ctx, tokensPath, body)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating deploy token",
+			"Could not create deploy token, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	fields, err := requireStrings(response, "token", "expires_at")
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected API response", "The deploy token was created, but its "+err.Error())
+		return
+	}
+	data.Token = types.StringValue(fields["token"])
+	data.ExpiresAt = types.StringValue(fields["expires_at"])
+
+	// Private data is only missing outside of Terraform, e.g. in unit tests.
+	if id, ok := response["id"].(string); ok && resp.Private != nil {
+		value, _ := json.Marshal(id)
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, deployTokenPrivateKey, value)...)
+	}
+
+	tflog.Trace(ctx, "Created deploy token", map[string]any{"expires_at": fields["expires_at"]})
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Close revokes the token, so it cannot be used after the run. A token that
+// already expired or was revoked is ignored.
+func (r *NixernetesDeployTokenEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	value, diags := req.Private.GetKey(ctx, deployTokenPrivateKey)
+	resp.Diagnostics.Append(diags...)
+	var id string
+	if resp.Diagnostics.HasError() || json.Unmarshal(value, &id) != nil || id == "" {
+		return
+	}
+
+	err := r.client.Delete(ctx, tokensPath+"/"+url.PathEscape(id))
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error revoking deploy token",
+			"Could not revoke deploy token "+id+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "Revoked deploy token", map[string]any{"id": id})
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testEphemeralOpen opens the ephemeral resource with config and decodes the
+// result into target when it succeeds.
+func testEphemeralOpen(t *testing.T, r ephemeral.EphemeralResource, config interface{}, target interface{}) ephemeral.OpenResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	var schemaResp ephemeral.SchemaResponse
+	r.Schema(ctx, ephemeral.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected schema diagnostics: %v", schemaResp.Diagnostics)
+	}
+
+	s := schemaResp.Schema
+	raw := tfsdk.EphemeralResultData{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	if diags := raw.Set(ctx, config); diags.HasError() {
+		t.Fatalf("Unexpected config diagnostics: %v", diags)
+	}
+
+	req := ephemeral.OpenRequest{Config: tfsdk.Config{Schema: s, Raw: raw.Raw}}
+	resp := ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: s, Raw: raw.Raw}}
+	r.Open(ctx, req, &resp)
+
+	if target != nil && !resp.Diagnostics.HasError() {
+		if diags := resp.Result.Get(ctx, target); diags.HasError() {
+			t.Fatalf("Unexpected result diagnostics: %v", diags)
+		}
+	}
+	return resp
+}
+
+func TestDeployTokenEphemeralResourceOpen(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/tokens" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "tok-1",
+			"token":      "nxt_abc123",
+			"expires_at": "2024-02-04T00:15:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesDeployTokenEphemeralResource{client: &NixernetesClient{Endpoint: server.URL}}
+	config := NixernetesDeployTokenModel{
+		Scopes:    types.ListValueMust(types.StringType, []attr.Value{types.StringValue("modules:write")}),
+		TTL:       types.StringValue("15m"),
+		Token:     types.StringNull(),
+		ExpiresAt: types.StringNull(),
+	}
+
+	var got NixernetesDeployTokenModel
+	resp := testEphemeralOpen(t, r, config, &got)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if sent["ttl_seconds"] != float64(900) {
+		t.Errorf("Expected ttl_seconds 900 in the request, got %v", sent["ttl_seconds"])
+	}
+	if scopes, _ := sent["scopes"].([]interface{}); len(scopes) != 1 || scopes[0] != "modules:write" {
+		t.Errorf("Expected scopes [modules:write] in the request, got %v", sent["scopes"])
+	}
+	if got.Token.ValueString() != "nxt_abc123" {
+		t.Errorf("Expected token nxt_abc123, got %v", got.Token)
+	}
+	if got.ExpiresAt.ValueString() != "2024-02-04T00:15:00Z" {
+		t.Errorf("Expected expires_at 2024-02-04T00:15:00Z, got %v", got.ExpiresAt)
+	}
+}
+
+func TestDeployTokenEphemeralResourceOpenDefaults(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "tok-1",
+			"token":      "nxt_abc123",
+			"expires_at": "2024-02-04T01:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesDeployTokenEphemeralResource{client: &NixernetesClient{Endpoint: server.URL}}
+	config := NixernetesDeployTokenModel{
+		Scopes:    types.ListNull(types.StringType),
+		TTL:       types.StringNull(),
+		Token:     types.StringNull(),
+		ExpiresAt: types.StringNull(),
+	}
+
+	resp := testEphemeralOpen(t, r, config, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if len(sent) != 0 {
+		t.Errorf("Expected an empty request body so the server defaults apply, got %v", sent)
+	}
+}
+
+func TestDeployTokenEphemeralResourceOpenInvalidTTL(t *testing.T) {
+	r := &NixernetesDeployTokenEphemeralResource{client: &NixernetesClient{Endpoint: newUnreachableServer(t).URL}}
+	config := NixernetesDeployTokenModel{
+		Scopes:    types.ListNull(types.StringType),
+		TTL:       types.StringValue("soon"),
+		Token:     types.StringNull(),
+		ExpiresAt: types.StringNull(),
+	}
+
+	resp := testEphemeralOpen(t, r, config, nil)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Summary(), "Invalid ttl") {
+		t.Errorf("Expected an invalid ttl error, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ function.Function = &ValidateConfigFunction{}
+	_ function.Function = &CanonicalImageFunction{}
+	_ function.Function = &ToNamespaceFunction{}
+	_ function.Function = &ConfigAttrFunction{}
+)
+
+// ========== validate_config Function ==========
+
+func NewValidateConfigFunction() function.Function {
+	return &ValidateConfigFunction{}
+}
+
+// ValidateConfigFunction checks a Nix configuration string without creating a resource.
+type ValidateConfigFunction struct{}
+
+func (f *ValidateConfigFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_config"
+}
+
+func (f *ValidateConfigFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Validate a Nix configuration",
+		MarkdownDescription: "Runs the same checks as `nixernetes_config` against a Nix configuration string. Returns `true` when the configuration is valid and fails with the validation errors otherwise.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "configuration",
+				MarkdownDescription: "Nix configuration content",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *ValidateConfigFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var configuration string
+
+	resp.Error = req.Arguments.Get(ctx, &configuration)
+	if resp.Error != nil {
+		return
+	}
+
+	// Only the configuration itself is under test, so borrow a known-good name.
+	v := ValidateConfigModel(ctx, &NixernetesConfigModel{
+		Name:          types.StringValue("validate-config"),
+		Configuration: types.StringValue(configuration),
+		Environment:   types.StringNull(),
+	}, nil, defaultMaxConfigSize)
+	if v.HasErrors() {
+		var messages []string
+		for _, e := range v.Errors {
+			messages = append(messages, e.Message)
+		}
+		resp.Error = function.NewArgumentFuncError(0, "Invalid configuration: "+strings.Join(messages, "; "))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, true)
+}
+
+// ========== canonical_image Function ==========
+
+func NewCanonicalImageFunction() function.Function {
+	return &CanonicalImageFunction{}
+}
+
+// CanonicalImageFunction normalizes a container image reference.
+type CanonicalImageFunction struct{}
+
+func (f *CanonicalImageFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "canonical_image"
+}
+
+func (f *CanonicalImageFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Normalize a container image reference",
+		MarkdownDescription: "Returns the fully qualified form of a container image reference, filling in the `docker.io` registry, the `library/` namespace for official images, and the `latest` tag when neither a tag nor a digest is given.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "image",
+				MarkdownDescription: "Container image reference",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *CanonicalImageFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var image string
+
+	resp.Error = req.Arguments.Get(ctx, &image)
+	if resp.Error != nil {
+		return
+	}
+
+	canonical, err := canonicalizeImage(image)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid image: "+err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, canonical)
+}
+
+// ========== to_namespace Function ==========
+
+func NewToNamespaceFunction() function.Function {
+	return &ToNamespaceFunction{}
+}
+
+// ToNamespaceFunction builds a valid Kubernetes namespace name from arbitrary input.
+type ToNamespaceFunction struct{}
+
+func (f *ToNamespaceFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "to_namespace"
+}
+
+func (f *ToNamespaceFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Build a valid namespace name",
+		MarkdownDescription: "Converts arbitrary input, such as a team name, into a valid Kubernetes namespace: lowercases it, replaces invalid characters with `-`, trims leading and trailing dashes and truncates to 63 characters. Fails if nothing usable remains.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "input",
+				MarkdownDescription: "Text to convert",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ToNamespaceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var input string
+
+	resp.Error = req.Arguments.Get(ctx, &input)
+	if resp.Error != nil {
+		return
+	}
+
+	ns, err := toNamespace(input)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid namespace: "+err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, ns)
+}
+
+// ========== config_attr Function ==========
+
+func NewConfigAttrFunction() function.Function {
+	return &ConfigAttrFunction{}
+}
+
+// ConfigAttrFunction extracts an attribute value from a Nix configuration.
+type ConfigAttrFunction struct{}
+
+func (f *ConfigAttrFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "config_attr"
+}
+
+func (f *ConfigAttrFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Extract an attribute from a Nix configuration",
+		MarkdownDescription: "Returns the value of an attribute path such as `services.nginx.enable` in a Nix configuration, or null when the path is not set. The configuration is read on a best-effort basis: both `a.b = 1;` and `a = { b = 1; };` are followed, string literals are returned without quotes and other values as their source text, e.g. `true` or `[ 80 443 ]`. Fails if the configuration cannot be parsed.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "config",
+				MarkdownDescription: "Nix configuration content",
+			},
+			function.StringParameter{
+				Name:                "path",
+				MarkdownDescription: "Dot-separated attribute path",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ConfigAttrFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var configuration, attrPath string
+
+	resp.Error = req.Arguments.Get(ctx, &configuration, &attrPath)
+	if resp.Error != nil {
+		return
+	}
+
+	if attrPath == "" {
+		resp.Error = function.NewArgumentFuncError(1, "Attribute path cannot be empty")
+		return
+	}
+
+	value, ok, err := nixAttribute(configuration, attrPath)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid configuration: "+err.Error())
+		return
+	}
+	if !ok {
+		resp.Error = resp.Result.Set(ctx, types.StringNull())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, value)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// testFunctionRun runs f with the given arguments and returns the response.
+// result is an unknown value of the function's return type.
+func testFunctionRun(t *testing.T, f function.Function, result attr.Value, args ...attr.Value) function.RunResponse {
+	t.Helper()
+
+	req := function.RunRequest{Arguments: function.NewArgumentsData(args)}
+	resp := function.RunResponse{Result: function.NewResultData(result)}
+	f.Run(context.Background(), req, &resp)
+	return resp
+}
+
+func TestValidateConfigFunction(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration string
+		wantErr       bool
+	}{
+		{"valid configuration", "{ services.nginx.enable = true; }", false},
+		{"nested attribute sets", "{ a = { b = [ 1 2 ]; }; }", false},
+		{"braces inside strings", `{ msg = "}"; }`, false},
+		{"empty configuration", "", true},
+		{"unbalanced braces", "{ services.nginx.enable = true;", true},
+		{"unterminated string", `{ msg = "oops; }`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testFunctionRun(t, NewValidateConfigFunction(), types.BoolUnknown(), types.StringValue(tt.configuration))
+
+			if tt.wantErr {
+				if resp.Error == nil {
+					t.Error("Expected function error, got none")
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("Unexpected function error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(types.BoolValue(true)) {
+				t.Errorf("Expected true, got %s", resp.Result.Value())
+			}
+		})
+	}
+}
+
+func TestCanonicalImageFunction(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		want    string
+		wantErr bool
+	}{
+		{"official image", "nginx", "docker.io/library/nginx:latest", false},
+		{"official image with tag", "postgres:16", "docker.io/library/postgres:16", false},
+		{"user image", "bitnami/redis:7.2", "docker.io/bitnami/redis:7.2", false},
+		{"custom registry", "ghcr.io/acme/api", "ghcr.io/acme/api:latest", false},
+		{"already canonical", "docker.io/library/nginx:latest", "docker.io/library/nginx:latest", false},
+		{"invalid image", "nginx|bash", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testFunctionRun(t, NewCanonicalImageFunction(), types.StringUnknown(), types.StringValue(tt.image))
+
+			if tt.wantErr {
+				if resp.Error == nil {
+					t.Error("Expected function error, got none")
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("Unexpected function error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(types.StringValue(tt.want)) {
+				t.Errorf("Expected %q, got %s", tt.want, resp.Result.Value())
+			}
+		})
+	}
+}
+
+func TestToNamespaceFunction(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"already valid", "payments", "payments", false},
+		{"mixed case and spaces", "Team Payments", "team-payments", false},
+		{"punctuation", "Data & ML (EU)", "data---ml--eu", false},
+		{"leading and trailing junk", "__platform__", "platform", false},
+		{"unicode", "équipe", "quipe", false},
+		{"truncated", strings.Repeat("a", 62) + "-bc", strings.Repeat("a", 62), false},
+		{"truncated after leading junk", strings.Repeat("!", 70) + "team", "team", false},
+		{"nothing usable", "!!!", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testFunctionRun(t, NewToNamespaceFunction(), types.StringUnknown(), types.StringValue(tt.input))
+
+			if tt.wantErr {
+				if resp.Error == nil {
+					t.Error("Expected function error, got none")
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("Unexpected function error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(types.StringValue(tt.want)) {
+				t.Errorf("Expected %q, got %s", tt.want, resp.Result.Value())
+			}
+		})
+	}
+}
+
+func TestConfigAttrFunction(t *testing.T) {
+	config := `{ pkgs, ... }:
+{
+  services.nginx.enable = true;
+  services.nginx.virtualHosts."example.com".root = "/var/www"; # a comment
+  networking = {
+    hostName = "web-1";
+    firewall.allowedTCPPorts = [ 80 443 ];
+  };
+  users.motd = "port ${toString 80}";
+}`
+
+	tests := []struct {
+		name    string
+		config  string
+		path    string
+		want    types.String
+		wantErr bool
+	}{
+		{"dotted binding", config, "services.nginx.enable", types.StringValue("true"), false},
+		{"nested attribute set", config, "networking.hostName", types.StringValue("web-1"), false},
+		{"nested dotted binding", config, "networking.firewall.allowedTCPPorts", types.StringValue("[ 80 443 ]"), false},
+		{"whole attribute set", config, "networking", types.StringValue(`{ hostName = "web-1"; firewall.allowedTCPPorts = [ 80 443 ]; }`), false},
+		{"interpolated string kept quoted", config, "users.motd", types.StringValue(`"port ${toString 80}"`), false},
+		{"not found", config, "services.postgresql.enable", types.StringNull(), false},
+		{"below a scalar", config, "services.nginx.enable.foo", types.StringNull(), false},
+		{"unparseable", "{ a = 1;", "a", types.StringNull(), true},
+		{"empty path", config, "", types.StringNull(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testFunctionRun(t, NewConfigAttrFunction(), types.StringUnknown(), types.StringValue(tt.config), types.StringValue(tt.path))
+
+			if tt.wantErr {
+				if resp.Error == nil {
+					t.Error("Expected function error, got none")
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("Unexpected function error: %s", resp.Error)
+			}
+			if !resp.Result.Value().Equal(tt.want) {
+				t.Errorf("Expected %s, got %s", tt.want, resp.Result.Value())
+			}
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// nixSummaryDepth is how many components of an attribute path are kept when
+// summarizing, so "services.nginx.virtualHosts" is reported as "services.nginx".
+const nixSummaryDepth = 2
+
+// nixArgumentPrefix matches the argument of a module function written as
+// "args: { ... }" or "args@{ ... }: { ... }".
+var nixArgumentPrefix = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_'-]*\s*[:@]$`)
+
+// maskNix returns content with comments blanked out and string contents
+// replaced by placeholders, so structural characters inside them are ignored.
+// The result has the same length in runes as content.
+func maskNix(content []rune) []rune {
+	masked := make([]rune, len(content))
+	copy(masked, content)
+
+	blank := func(from, to int, r rune) {
+		for k := from; k < to && k < len(masked); k++ {
+			if masked[k] != '\n' {
+				masked[k] = r
+			}
+		}
+	}
+
+	for i := 0; i < len(content); i++ {
+		switch {
+		case content[i] == '#':
+			start := i
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			blank(start, i, ' ')
+		case content[i] == '/' && i+1 < len(content) && content[i+1] == '*':
+			start := i
+			for i += 2; i < len(content) && !(content[i] == '*' && i+1 < len(content) && content[i+1] == '/'); i++ {
+			}
+			blank(start, i+2, ' ')
+			i++
+		case content[i] == '"':
+			start := i
+			for i++; i < len(content) && content[i] != '"'; i++ {
+				if content[i] == '\\' {
+					i++
+				}
+			}
+			blank(start, i+1, 'x')
+		case content[i] == '\'' && i+1 < len(content) && content[i+1] == '\'':
+			start := i
+			for i += 2; i < len(content) && !(content[i] == '\'' && i+1 < len(content) && content[i+1] == '\''); i++ {
+			}
+			blank(start, i+2, 'x')
+			i++
+		}
+	}
+
+	return masked
+}
+
+// stripNixComments returns text with comments removed but string contents
+// kept, using the masked form produced by maskNix.
+func stripNixComments(text, masked []rune) string {
+	var b strings.Builder
+	for i, r := range masked {
+		if r == 'x' {
+			r = text[i]
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// matchingBrace returns the index of the brace closing the one at open.
+func matchingBrace(masked []rune, open int) int {
+	depth := 0
+	for i := open; i < len(masked); i++ {
+		switch masked[i] {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// nixTopLevelAttributes parses the top-level attribute set of a Nix
+// configuration, which may be wrapped in a module function such as
+// "{ pkgs, ... }: { ... }". It returns each attribute path, truncated to
+// nixSummaryDepth components, mapped to its value text with whitespace
+// normalized. Configurations it cannot follow, such as a top-level let
+// expression, return an error.
+func nixTopLevelAttributes(content string) (map[string]string, error) {
+	text, masked, open, closing, err := nixAttributeSet(content)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := nixBindings(text, masked, open, closing)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := map[string]string{}
+	for _, b := range bindings {
+		components := b.path
+		if len(components) > nixSummaryDepth {
+			components = components[:nixSummaryDepth]
+		}
+		key := strings.Join(components, ".")
+
+		value := b.valueText(text, masked)
+		if existing, ok := attributes[key]; ok {
+			value = existing + "; " + value
+		}
+		attributes[key] = value
+	}
+	return attributes, nil
+}
+
+// nixAttribute looks up the value of an attribute path such as
+// "services.nginx.enable" in a Nix configuration, following both dotted
+// bindings and nested attribute sets. String literals are returned without
+// their quotes, other values as their source text with whitespace normalized.
+// It reports false when the path is not bound.
+func nixAttribute(content, attrPath string) (string, bool, error) {
+	text, masked, open, closing, err := nixAttributeSet(content)
+	if err != nil {
+		return "", false, err
+	}
+	return nixLookup(text, masked, open, closing, strings.Split(attrPath, "."))
+}
+
+// nixLookup finds want among the bindings of the attribute set between the
+// braces at open and closing.
+func nixLookup(text, masked []rune, open, closing int, want []string) (string, bool, error) {
+	bindings, err := nixBindings(text, masked, open, closing)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, b := range bindings {
+		n := len(b.path)
+		if n > len(want) || strings.Join(b.path, ".") != strings.Join(want[:n], ".") {
+			continue
+		}
+		if n == len(want) {
+			return unquoteNixString(b.valueText(text, masked), masked[b.start:b.end]), true, nil
+		}
+
+		// A binding of a prefix of the path, e.g. "services.nginx = { ... }".
+		inner := strings.TrimLeft(string(masked[b.start:b.end]), " \t\r\n")
+		if !strings.HasPrefix(inner, "{") {
+			continue
+		}
+		innerOpen := b.end - len([]rune(inner))
+		innerClosing := matchingBrace(masked, innerOpen)
+		if innerClosing < 0 || innerClosing >= b.end {
+			continue
+		}
+		if value, ok, err := nixLookup(text, masked, innerOpen, innerClosing, want[n:]); err != nil || ok {
+			return value, ok, err
+		}
+	}
+	return "", false, nil
+}
+
+// unquoteNixString returns value without its quotes when it is a plain
+// string literal, and unchanged otherwise.
+func unquoteNixString(value string, masked []rune) string {
+	literal := strings.Trim(strings.TrimSpace(string(masked)), "x") == ""
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !literal || strings.Contains(value, "${") {
+		return value
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value[1 : len(value)-1]
+}
+
+// nixAttributeSet locates the top-level attribute set of a configuration,
+// skipping a module function header. It returns the configuration, its
+// masked form and the indexes of the set's braces.
+func nixAttributeSet(content string) (text, masked []rune, open, closing int, err error) {
+	if err := checkNixSyntax(content); err != nil {
+		return nil, nil, 0, 0, err
+	}
+
+	text = []rune(content)
+	masked = maskNix(text)
+
+	open = strings.IndexRune(string(masked), '{')
+	if open < 0 {
+		return nil, nil, 0, 0, fmt.Errorf("configuration is not an attribute set")
+	}
+	open = len([]rune(string(masked)[:open]))
+	closing = matchingBrace(masked, open)
+
+	// Only a module function argument such as "pkgs:" or "args@" may precede
+	// the first brace; anything else (e.g. "let") is beyond this parser.
+	if prefix := strings.TrimSpace(string(masked[:open])); prefix != "" && !nixArgumentPrefix.MatchString(prefix) {
+		return nil, nil, 0, 0, fmt.Errorf("configuration is not an attribute set")
+	}
+
+	// Skip a module function header and move on to its body.
+	rest := strings.TrimLeft(string(masked[closing+1:]), " \t\r\n")
+	if strings.HasPrefix(rest, "@") {
+		rest = strings.TrimLeft(strings.TrimLeft(rest[1:], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-'"), " \t\r\n")
+	}
+	if strings.HasPrefix(rest, ":") {
+		body := strings.TrimLeft(rest[1:], " \t\r\n")
+		if !strings.HasPrefix(body, "{") {
+			return nil, nil, 0, 0, fmt.Errorf("module body is not an attribute set")
+		}
+		open = len(masked) - len([]rune(body))
+		closing = matchingBrace(masked, open)
+	}
+
+	return text, masked, open, closing, nil
+}
+
+// nixBinding is a single "path = value" binding of an attribute set. start
+// and end delimit the value in the configuration.
+type nixBinding struct {
+	path       []string
+	start, end int
+}
+
+// valueText returns the binding's value with comments removed and whitespace normalized.
+func (b nixBinding) valueText(text, masked []rune) string {
+	return strings.Join(strings.Fields(stripNixComments(text[b.start:b.end], masked[b.start:b.end])), " ")
+}
+
+// nixBindings splits the attribute set between the braces at open and
+// closing into its bindings. inherit statements are skipped.
+func nixBindings(text, masked []rune, open, closing int) ([]nixBinding, error) {
+	var bindings []nixBinding
+	depth := 0
+	start := open + 1
+	for i := open + 1; i < closing; i++ {
+		switch masked[i] {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+		case ';':
+			if depth > 0 {
+				continue
+			}
+			b, ok, err := parseNixBinding(text, masked, start, i)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				bindings = append(bindings, b)
+			}
+			start = i + 1
+		}
+	}
+	return bindings, nil
+}
+
+// parseNixBinding parses the statement between start and end, reporting
+// false for statements that bind nothing.
+func parseNixBinding(text, masked []rune, start, end int) (nixBinding, bool, error) {
+	statement := strings.TrimSpace(string(masked[start:end]))
+	if statement == "" || strings.HasPrefix(statement, "inherit") {
+		return nixBinding{}, false, nil
+	}
+
+	eq := -1
+	for i := start; i < end; i++ {
+		if masked[i] == '=' && (i+1 >= end || masked[i+1] != '=') && (i == start || masked[i-1] != '=') {
+			eq = i
+			break
+		}
+	}
+	if eq < 0 {
+		return nixBinding{}, false, fmt.Errorf("expected an attribute binding, got %q", statement)
+	}
+
+	components := strings.Split(strings.TrimSpace(stripNixComments(text[start:eq], masked[start:eq])), ".")
+	for i := range components {
+		components[i] = strings.TrimSpace(components[i])
+	}
+	return nixBinding{path: components, start: eq + 1, end: end}, true, nil
+}
+
+// configurationSummary lists the top-level attribute paths that differ
+// between prior and current, or all of current's paths when there is no
+// prior configuration. It is null when current cannot be parsed.
+func configurationSummary(prior, current types.String) types.String {
+	if current.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	now, err := nixTopLevelAttributes(current.ValueString())
+	if err != nil {
+		return types.StringNull()
+	}
+
+	// An unparseable prior configuration counts as every path changing.
+	var before map[string]string
+	if !prior.IsNull() && !prior.IsUnknown() {
+		before, _ = nixTopLevelAttributes(prior.ValueString())
+	}
+
+	var changed []string
+	for key, value := range now {
+		if old, ok := before[key]; !ok || old != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, ok := now[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+
+	if len(changed) == 0 {
+		return types.StringValue("no attribute changes")
+	}
+	sort.Strings(changed)
+	return types.StringValue(strings.Join(changed, ", "))
+}
+
+// decodedConfiguration returns the configuration content to summarize: the
+// decoded configuration_base64 when that is set, otherwise configuration.
+// Content that does not decode is null, like an unparseable configuration.
+func decodedConfiguration(configuration, encoded types.String) types.String {
+	if encoded.IsNull() {
+		return configuration
+	}
+	if encoded.IsUnknown() {
+		return types.StringUnknown()
+	}
+	content, err := decodeConfiguration(configuration, encoded)
+	if err != nil {
+		return types.StringNull()
+	}
+	return types.StringValue(content)
+}
+
+// configurationSummaryModifier plans configuration_summary from the change to
+// configuration or configuration_base64, keeping the prior summary when the
+// configuration is unchanged.
+type configurationSummaryModifier struct{}
+
+func (m configurationSummaryModifier) Description(_ context.Context) string {
+	return "Summarizes the top-level attributes changed in the configuration."
+}
+
+func (m configurationSummaryModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m configurationSummaryModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	var configuration, encoded types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("configuration"), &configuration)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("configuration_base64"), &encoded)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	configuration = decodedConfiguration(configuration, encoded)
+
+	prior := types.StringNull()
+	if !req.State.Raw.IsNull() {
+		var priorEncoded types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("configuration"), &prior)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("configuration_base64"), &priorEncoded)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		prior = decodedConfiguration(prior, priorEncoded)
+		if prior.Equal(configuration) {
+			resp.PlanValue = req.StateValue
+			return
+		}
+	}
+
+	resp.PlanValue = configurationSummary(prior, configuration)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNixTopLevelAttributes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "dotted paths",
+			input: "{ services.nginx.enable = true; networking.firewall.allowedTCPPorts = [ 80 443 ]; }",
+			want:  []string{"networking.firewall", "services.nginx"},
+		},
+		{
+			name:  "nested attribute set",
+			input: "{ services = { nginx.enable = true; }; time.timeZone = \"UTC\"; }",
+			want:  []string{"services", "time.timeZone"},
+		},
+		{
+			name:  "module function",
+			input: "{ config, pkgs, ... }:\n{\n  # comment; with = signs\n  environment.systemPackages = [ pkgs.git ];\n}",
+			want:  []string{"environment.systemPackages"},
+		},
+		{
+			name:  "semicolons in strings",
+			input: `{ users.motd = "a; b = c"; }`,
+			want:  []string{"users.motd"},
+		},
+		{
+			name:    "let expression",
+			input:   "let x = 1; in { a = x; }",
+			wantErr: true,
+		},
+		{
+			name:    "not an attribute set",
+			input:   "42",
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced",
+			input:   "{ a = 1;",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nixTopLevelAttributes(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected attributes %v, got %v", tt.want, got)
+			}
+			for _, key := range tt.want {
+				if _, ok := got[key]; !ok {
+					t.Errorf("Expected attribute %q in %v", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigurationSummary(t *testing.T) {
+	base := "{ services.nginx.enable = true; networking.firewall.enable = true; time.timeZone = \"UTC\"; }"
+
+	tests := []struct {
+		name    string
+		prior   types.String
+		current types.String
+		want    types.String
+	}{
+		{
+			name:    "new configuration",
+			prior:   types.StringNull(),
+			current: types.StringValue(base),
+			want:    types.StringValue("networking.firewall, services.nginx, time.timeZone"),
+		},
+		{
+			name:    "changed and removed attributes",
+			prior:   types.StringValue(base),
+			current: types.StringValue("{ services.nginx.enable = false; time.timeZone = \"UTC\"; }"),
+			want:    types.StringValue("networking.firewall, services.nginx"),
+		},
+		{
+			name:    "formatting only",
+			prior:   types.StringValue(base),
+			current: types.StringValue("{\n  services.nginx.enable = true;\n  networking.firewall.enable = true;\n  time.timeZone = \"UTC\";\n}"),
+			want:    types.StringValue("no attribute changes"),
+		},
+		{
+			name:    "unparseable",
+			prior:   types.StringValue(base),
+			current: types.StringValue("let a = 1; in { }"),
+			want:    types.StringNull(),
+		},
+		{
+			name:    "unknown",
+			prior:   types.StringValue(base),
+			current: types.StringUnknown(),
+			want:    types.StringUnknown(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := configurationSummary(tt.prior, tt.current)
+			if !got.Equal(tt.want) {
+				t.Errorf("configurationSummary() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,18 +2,40 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/metaschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
 )
 
 // Ensure provider is defined with compile-time check
-var _ provider.Provider = &NixernetesProvider{}
+var (
+	_ provider.Provider                       = &NixernetesProvider{}
+	_ provider.ProviderWithFunctions          = &NixernetesProvider{}
+	_ provider.ProviderWithEphemeralResources = &NixernetesProvider{}
+)
 
 // New is a helper function to simplify provider server initialization.
 func New(version string) func() provider.Provider {
@@ -36,6 +58,52 @@ type NixernetesProviderModel struct {
 	Endpoint types.String `tfsdk:"endpoint"`
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
+
+	LogRedactionPatterns  types.List   `tfsdk:"log_redaction_patterns"`
+	UpdateMethod          types.String `tfsdk:"update_method"`
+	ResponseHeaderTimeout types.String `tfsdk:"response_header_timeout"`
+	Timeout               types.String `tfsdk:"timeout"`
+	DialTimeout           types.String `tfsdk:"dial_timeout"`
+	OperationTimeout      types.String `tfsdk:"operation_timeout"`
+
+	APIPrefix    types.String `tfsdk:"api_prefix"`
+	ConfigsPath  types.String `tfsdk:"configs_path"`
+	ModulesPath  types.String `tfsdk:"modules_path"`
+	ProjectsPath types.String `tfsdk:"projects_path"`
+
+	AllowLocalEndpoint types.Bool `tfsdk:"allow_local_endpoint"`
+	SendContentMD5     types.Bool `tfsdk:"send_content_md5"`
+	DebugHTTP          types.Bool `tfsdk:"debug_http"`
+	OTel               types.Bool `tfsdk:"otel"`
+
+	ValidateAgainstServerSchema types.Bool   `tfsdk:"validate_against_server_schema"`
+	DefaultEnvironment          types.String `tfsdk:"default_environment"`
+	DefaultReplicas             types.Int64  `tfsdk:"default_replicas"`
+
+	MaxRetries      types.Int64  `tfsdk:"max_retries"`
+	RetryBaseDelay  types.String `tfsdk:"retry_base_delay"`
+	RetryBackoff    types.String `tfsdk:"retry_backoff"`
+	RetryBackoffMax types.String `tfsdk:"retry_backoff_max"`
+
+	KeepAlive       types.String `tfsdk:"keep_alive"`
+	MaxConnLifetime types.String `tfsdk:"max_conn_lifetime"`
+
+	MaxIdleConnections    types.Int64  `tfsdk:"max_idle_connections"`
+	IdleConnectionTimeout types.String `tfsdk:"idle_connection_timeout"`
+
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+
+	TLSInsecureHosts types.List `tfsdk:"tls_insecure_hosts"`
+
+	CACertificate      types.String `tfsdk:"ca_certificate"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+
+	ClientCertificate types.String `tfsdk:"client_certificate"`
+	ClientKey         types.String `tfsdk:"client_key"`
+
+	RequestLogFile    types.String `tfsdk:"request_log_file"`
+	RequestLogMaxSize types.Int64  `tfsdk:"request_log_max_size"`
 }
 
 // Metadata returns the provider type name.
@@ -62,6 +130,146 @@ func (p *NixernetesProvider) Schema(ctx context.Context, req provider.SchemaRequ
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"log_redaction_patterns": metaschema.ListAttribute{
+				MarkdownDescription: "Regular expressions whose matches are replaced with `[REDACTED]` in logged request URLs and bodies, in addition to the built-in credential patterns.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"update_method": metaschema.StringAttribute{
+				MarkdownDescription: "HTTP method used to update existing configs, modules and projects: `PUT`, `POST` or `PATCH`. By default configs are updated with `PUT`, and modules and projects with a JSON merge patch of the changed fields. When set, the full body is sent with this method instead.",
+				Optional:            true,
+			},
+			"response_header_timeout": metaschema.StringAttribute{
+				MarkdownDescription: "How long to wait for the API server to start responding to a request, as a duration such as `30s`. Reading the response body is not limited by this timeout. Defaults to no limit.",
+				Optional:            true,
+			},
+			"timeout": metaschema.StringAttribute{
+				MarkdownDescription: "Overall limit on a single API request, including connecting and reading the response body, as a duration such as `2m`. Retries each get the full timeout. Defaults to no limit.",
+				Optional:            true,
+			},
+			"operation_timeout": metaschema.StringAttribute{
+				MarkdownDescription: "Default limit on each resource create, read, update and delete, including retries and waits such as `ready_timeout`, as a duration such as `30m`. A resource's `timeouts` block takes precedence. Defaults to `1h`.",
+				Optional:            true,
+			},
+			"dial_timeout": metaschema.StringAttribute{
+				MarkdownDescription: "How long to wait for a connection to the API server to be established, as a duration such as `10s`. Defaults to `30s`.",
+				Optional:            true,
+			},
+			"api_prefix": metaschema.StringAttribute{
+				MarkdownDescription: "Path prepended to every API request, for servers that version their API, e.g. `/api/v1` turns `/configs` into `/api/v1/configs`. Leading and trailing slashes are optional. Defaults to none.",
+				Optional:            true,
+			},
+			"configs_path": metaschema.StringAttribute{
+				MarkdownDescription: "API path under which configurations live, e.g. `/v2/configurations`. Defaults to `/configs`.",
+				Optional:            true,
+			},
+			"modules_path": metaschema.StringAttribute{
+				MarkdownDescription: "API path under which modules live. Defaults to `/modules`.",
+				Optional:            true,
+			},
+			"projects_path": metaschema.StringAttribute{
+				MarkdownDescription: "API path under which projects live. Defaults to `/projects`.",
+				Optional:            true,
+			},
+			"max_retries": metaschema.Int64Attribute{
+				MarkdownDescription: "How many times to retry a request that failed with a retryable error, such as a 429, a 5xx or a network error. Creates and other `POST` requests are never retried, as the server may have acted on the failed request. Defaults to `0`, no retries.",
+				Optional:            true,
+			},
+			"retry_base_delay": metaschema.StringAttribute{
+				MarkdownDescription: "Pause before the first retry, as a duration such as `1s`. Doubled after each retry, up to `retry_backoff_max`. Defaults to `1s`.",
+				Optional:            true,
+			},
+			"retry_backoff": metaschema.StringAttribute{
+				MarkdownDescription: "Deprecated alias of `retry_base_delay`.",
+				Optional:            true,
+				DeprecationMessage:  "Use retry_base_delay instead.",
+			},
+			"retry_backoff_max": metaschema.StringAttribute{
+				MarkdownDescription: "Longest pause between retries, as a duration such as `30s`. Defaults to `1m`.",
+				Optional:            true,
+			},
+			"keep_alive": metaschema.StringAttribute{
+				MarkdownDescription: "TCP keep-alive period for connections to the API server, as a duration such as `15s`. Shorter periods keep connections alive through load balancers that drop idle connections. Defaults to `30s`.",
+				Optional:            true,
+			},
+			"max_conn_lifetime": metaschema.StringAttribute{
+				MarkdownDescription: "How long connections to the API server are reused before being closed and re-established, as a duration such as `5m`. Defaults to no limit.",
+				Optional:            true,
+			},
+			"max_idle_connections": metaschema.Int64Attribute{
+				MarkdownDescription: "How many idle connections to the API server are kept open for reuse by later requests. Raise it when many resources are refreshed in parallel, so requests reuse connections instead of opening new ones. Defaults to `2`.",
+				Optional:            true,
+			},
+			"idle_connection_timeout": metaschema.StringAttribute{
+				MarkdownDescription: "How long an idle connection to the API server is kept open for reuse, as a duration such as `30s`. Defaults to `90s`.",
+				Optional:            true,
+			},
+			"requests_per_second": metaschema.Float64Attribute{
+				MarkdownDescription: "Maximum number of requests per second sent to the API server, across all resources and data sources. Fractions such as `0.5` are allowed. Requests beyond the rate wait their turn instead of tripping the server's rate limit. Defaults to no limit.",
+				Optional:            true,
+			},
+			"max_concurrent_requests": metaschema.Int64Attribute{
+				MarkdownDescription: "Maximum number of requests in flight to the API server at once, across all resources and data sources. Further requests wait until one finishes, so a high `-parallelism` does not overwhelm the server. Defaults to no limit.",
+				Optional:            true,
+			},
+			"send_content_md5": metaschema.BoolAttribute{
+				MarkdownDescription: "Send a `Content-MD5` header with request bodies and reject responses whose body does not match their `Content-MD5` header. Useful over unreliable links. Defaults to `false`.",
+				Optional:            true,
+			},
+			"debug_http": metaschema.BoolAttribute{
+				MarkdownDescription: "Log every API request and response, including headers and bodies, at trace level (`TF_LOG=TRACE`). `Authorization` and cookie headers and password, token, secret and API key fields are redacted, as are matches of `log_redaction_patterns`. Defaults to `false`.",
+				Optional:            true,
+			},
+			"otel": metaschema.BoolAttribute{
+				MarkdownDescription: "Send the W3C trace context (`traceparent` and `tracestate` headers) with every API request, so the API server's traces can be correlated with the Terraform run. The trace is taken from the `TRACEPARENT` and `TRACESTATE` environment variables, which tracing-aware CI systems set. Defaults to `false`.",
+				Optional:            true,
+			},
+			"validate_against_server_schema": metaschema.BoolAttribute{
+				MarkdownDescription: "Fetch the configuration schema from the API server (`<configs_path>/schema`) and validate JSON configurations against it before sending them. Skipped when the server does not publish a schema. Defaults to `false`.",
+				Optional:            true,
+			},
+			"default_environment": metaschema.StringAttribute{
+				MarkdownDescription: "Environment planned for `nixernetes_config` resources that do not set `environment` (development, staging, production). Applied when the plan is made, so the plan and validation see the effective environment instead of a value the server picks during apply.",
+				Optional:            true,
+			},
+			"default_replicas": metaschema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Replica count of `nixernetes_module` resources that set neither `replicas` nor `autoscaling`, between 0 and %d. When unset the server's default applies.", maxModuleReplicas),
+				Optional:            true,
+			},
+			"tls_insecure_hosts": metaschema.ListAttribute{
+				MarkdownDescription: "Hostnames or IP addresses whose TLS certificates are not verified, e.g. an internal host with a self-signed certificate. Certificates of every other host are verified as usual. Only set this for hosts you trust on a network you trust.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"ca_certificate": metaschema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificate(s), or the path of a file containing them, trusted in addition to the system roots when verifying the API server's certificate. Use this when the API server's certificate is issued by a private CA.",
+				Optional:            true,
+			},
+			"insecure_skip_verify": metaschema.BoolAttribute{
+				MarkdownDescription: "Do not verify the API server's TLS certificate at all. Only meant for development setups with self-signed certificates; prefer `ca_certificate`. Defaults to `false`.",
+				Optional:            true,
+			},
+			"client_certificate": metaschema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate, or the path of a file containing it, presented to API gateways that require mutual TLS. Must be set together with `client_key`.",
+				Optional:            true,
+			},
+			"client_key": metaschema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of `client_certificate`, or the path of a file containing it.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"request_log_file": metaschema.StringAttribute{
+				MarkdownDescription: "Path of a file to which one JSON line is appended per API request, with its timestamp, method, path, status, duration and request ID. Paths and errors are redacted like the provider logs. Useful for debugging without `TF_LOG`. Created if missing.",
+				Optional:            true,
+			},
+			"request_log_max_size": metaschema.Int64Attribute{
+				MarkdownDescription: "Size in bytes beyond which `request_log_file` is rotated: the current file is renamed with a `.1` suffix, replacing any previous one, and a new file is started. Defaults to 10 MiB.",
+				Optional:            true,
+			},
+			"allow_local_endpoint": metaschema.BoolAttribute{
+				MarkdownDescription: "Suppress the warning shown when `endpoint` points at `localhost` or a loopback address. Set this when the API server really does run on the same machine.",
+				Optional:            true,
+			},
 		},
 	}.GetSchemaBlock()
 }
@@ -124,10 +332,276 @@ func (p *NixernetesProvider) Configure(ctx context.Context, req provider.Configu
 		)
 	}
 
+	var redactionPatterns []*regexp.Regexp
+	if !config.LogRedactionPatterns.IsNull() && !config.LogRedactionPatterns.IsUnknown() {
+		var patterns []string
+		resp.Diagnostics.Append(config.LogRedactionPatterns.ElementsAs(ctx, &patterns, false)...)
+
+		compiled, err := compileRedactionPatterns(patterns)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("log_redaction_patterns"),
+				"Invalid Log Redaction Pattern",
+				"The provider cannot create the Nixernetes API client as a log redaction pattern is not a valid regular expression: "+err.Error(),
+			)
+		}
+		redactionPatterns = compiled
+	}
+
+	var updateMethod string
+	if !config.UpdateMethod.IsNull() && !config.UpdateMethod.IsUnknown() {
+		updateMethod = strings.ToUpper(config.UpdateMethod.ValueString())
+		if !isValidUpdateMethod(updateMethod) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("update_method"),
+				"Invalid Update Method",
+				"The provider cannot create the Nixernetes API client as update_method must be one of "+
+					strings.Join(updateMethods, ", ")+", got: "+config.UpdateMethod.ValueString(),
+			)
+		}
+	}
+
+	retryPolicy := RetryPolicy{Backoff: defaultRetryBackoff, MaxBackoff: maxRetryBackoff}
+	if !config.MaxRetries.IsNull() && !config.MaxRetries.IsUnknown() {
+		if config.MaxRetries.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_retries"),
+				"Invalid Max Retries",
+				fmt.Sprintf("The provider cannot create the Nixernetes API client as max_retries cannot be negative, got: %d", config.MaxRetries.ValueInt64()),
+			)
+		}
+		retryPolicy.MaxRetries = int(config.MaxRetries.ValueInt64())
+	}
+
+	var maxIdleConns int
+	if !config.MaxIdleConnections.IsNull() && !config.MaxIdleConnections.IsUnknown() {
+		if config.MaxIdleConnections.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_idle_connections"),
+				"Invalid Max Idle Connections",
+				fmt.Sprintf("The provider cannot create the Nixernetes API client as max_idle_connections must be at least 1, got: %d", config.MaxIdleConnections.ValueInt64()),
+			)
+		}
+		maxIdleConns = int(config.MaxIdleConnections.ValueInt64())
+	}
+
+	var limiter *rate.Limiter
+	if !config.RequestsPerSecond.IsNull() && !config.RequestsPerSecond.IsUnknown() {
+		if config.RequestsPerSecond.ValueFloat64() <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("requests_per_second"),
+				"Invalid Requests Per Second",
+				fmt.Sprintf("The provider cannot create the Nixernetes API client as requests_per_second must be positive, got: %g", config.RequestsPerSecond.ValueFloat64()),
+			)
+		}
+		// A burst of one spaces requests evenly instead of letting a
+		// refresh of many resources start with a spike.
+		limiter = rate.NewLimiter(rate.Limit(config.RequestsPerSecond.ValueFloat64()), 1)
+	}
+
+	var concurrency *semaphore.Weighted
+	if !config.MaxConcurrentRequests.IsNull() && !config.MaxConcurrentRequests.IsUnknown() {
+		if config.MaxConcurrentRequests.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_concurrent_requests"),
+				"Invalid Max Concurrent Requests",
+				fmt.Sprintf("The provider cannot create the Nixernetes API client as max_concurrent_requests must be at least 1, got: %d", config.MaxConcurrentRequests.ValueInt64()),
+			)
+		}
+		concurrency = semaphore.NewWeighted(config.MaxConcurrentRequests.ValueInt64())
+	}
+
+	var propagator propagation.TextMapPropagator
+	var traceParent trace.SpanContext
+	if config.OTel.ValueBool() {
+		propagator = propagation.TraceContext{}
+		traceParent = traceParentFromEnv(propagator)
+	}
+
+	var responseHeaderTimeout, timeout, dialTimeout, operationTimeout, keepAlive, maxConnLifetime, idleConnTimeout time.Duration
+	durationSettings := []struct {
+		name   string
+		value  types.String
+		target *time.Duration
+	}{
+		{"response_header_timeout", config.ResponseHeaderTimeout, &responseHeaderTimeout},
+		{"timeout", config.Timeout, &timeout},
+		{"dial_timeout", config.DialTimeout, &dialTimeout},
+		{"operation_timeout", config.OperationTimeout, &operationTimeout},
+		{"retry_base_delay", config.RetryBaseDelay, &retryPolicy.Backoff},
+		{"retry_backoff", config.RetryBackoff, &retryPolicy.Backoff},
+		{"retry_backoff_max", config.RetryBackoffMax, &retryPolicy.MaxBackoff},
+		{"keep_alive", config.KeepAlive, &keepAlive},
+		{"max_conn_lifetime", config.MaxConnLifetime, &maxConnLifetime},
+		{"idle_connection_timeout", config.IdleConnectionTimeout, &idleConnTimeout},
+	}
+	if !config.RetryBaseDelay.IsNull() && !config.RetryBackoff.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_backoff"),
+			"Conflicting Retry Delay",
+			"The provider cannot create the Nixernetes API client as retry_backoff and retry_base_delay are both set. "+
+				"retry_backoff is a deprecated alias of retry_base_delay; remove it.",
+		)
+	}
+	for _, d := range durationSettings {
+		if d.value.IsNull() || d.value.IsUnknown() {
+			continue
+		}
+		parsed, err := parseDurationSetting(d.value.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(d.name),
+				"Invalid Duration",
+				"The provider cannot create the Nixernetes API client as "+d.name+" "+err.Error(),
+			)
+			continue
+		}
+		*d.target = parsed
+	}
+
+	var configsPath, modulesPath, projectsPath string
+	apiPaths := []struct {
+		name   string
+		value  types.String
+		target *string
+	}{
+		{"configs_path", config.ConfigsPath, &configsPath},
+		{"modules_path", config.ModulesPath, &modulesPath},
+		{"projects_path", config.ProjectsPath, &projectsPath},
+	}
+	for _, p := range apiPaths {
+		if p.value.IsNull() || p.value.IsUnknown() {
+			continue
+		}
+		if !strings.HasPrefix(p.value.ValueString(), "/") {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(p.name),
+				"Invalid API Path",
+				"The provider cannot create the Nixernetes API client as "+p.name+" must start with \"/\", got: "+p.value.ValueString(),
+			)
+		}
+		*p.target = strings.TrimSuffix(p.value.ValueString(), "/")
+	}
+
+	defaultEnvironment := config.DefaultEnvironment.ValueString()
+	if defaultEnvironment != "" && !isValidEnvironment(defaultEnvironment) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_environment"),
+			"Invalid Default Environment",
+			"The provider cannot create the Nixernetes API client as default_environment must be \"development\", \"staging\" or \"production\", got: "+defaultEnvironment,
+		)
+	}
+
+	var defaultReplicas *int64
+	if !config.DefaultReplicas.IsNull() && !config.DefaultReplicas.IsUnknown() {
+		replicas := config.DefaultReplicas.ValueInt64()
+		if replicas < 0 || replicas > maxModuleReplicas {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_replicas"),
+				"Invalid Default Replicas",
+				fmt.Sprintf("The provider cannot create the Nixernetes API client as default_replicas must be between 0 and %d, got: %d", maxModuleReplicas, replicas),
+			)
+		}
+		defaultReplicas = &replicas
+	}
+
+	var tlsInsecureHosts []string
+	if !config.TLSInsecureHosts.IsNull() && !config.TLSInsecureHosts.IsUnknown() {
+		resp.Diagnostics.Append(config.TLSInsecureHosts.ElementsAs(ctx, &tlsInsecureHosts, false)...)
+		for _, host := range tlsInsecureHosts {
+			if !isValidHost(host) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("tls_insecure_hosts"),
+					"Invalid TLS Insecure Host",
+					"The provider cannot create the Nixernetes API client as tls_insecure_hosts entries must be hostnames or IP addresses without a scheme or port, got: "+host,
+				)
+			}
+		}
+	}
+
+	var rootCAs *x509.CertPool
+	if caCertificate := config.CACertificate.ValueString(); caCertificate != "" {
+		var err error
+		rootCAs, err = loadCACertificate(caCertificate)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("ca_certificate"),
+				"Invalid CA Certificate",
+				"The provider cannot create the Nixernetes API client as ca_certificate must be PEM-encoded certificates or the path of a file containing them: "+err.Error(),
+			)
+		}
+	}
+
+	var clientCertificate *tls.Certificate
+	certificate, key := config.ClientCertificate.ValueString(), config.ClientKey.ValueString()
+	switch {
+	case certificate != "" && key == "":
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_key"),
+			"Missing Client Key",
+			"The provider cannot create the Nixernetes API client as client_key must be set together with client_certificate.",
+		)
+	case certificate == "" && key != "":
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_certificate"),
+			"Missing Client Certificate",
+			"The provider cannot create the Nixernetes API client as client_certificate must be set together with client_key.",
+		)
+	case certificate != "":
+		cert, err := loadClientCertificate(certificate, key)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("client_certificate"),
+				"Invalid Client Certificate",
+				"The provider cannot create the Nixernetes API client as client_certificate and client_key must be a PEM-encoded certificate and its private key, or the paths of files containing them: "+err.Error(),
+			)
+		}
+		clientCertificate = &cert
+	}
+
+	var requestLog *requestLog
+	if !config.RequestLogMaxSize.IsNull() && !config.RequestLogMaxSize.IsUnknown() && config.RequestLogMaxSize.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("request_log_max_size"),
+			"Invalid Request Log Size",
+			fmt.Sprintf("The provider cannot create the Nixernetes API client as request_log_max_size must be positive, got: %d", config.RequestLogMaxSize.ValueInt64()),
+		)
+	}
+	if logFile := config.RequestLogFile.ValueString(); logFile != "" {
+		requestLog = newRequestLog(logFile, config.RequestLogMaxSize.ValueInt64())
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// The examples use a localhost endpoint, which is easy to leave in place
+	// by mistake. Local builds and tests legitimately talk to localhost.
+	allowLocal := config.AllowLocalEndpoint.ValueBool() || p.version == "dev" || p.version == "test"
+	if !allowLocal && isLocalEndpoint(endpoint) {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("endpoint"),
+			"Local API Endpoint",
+			"The Nixernetes API endpoint "+endpoint+" points at this machine, which is usually the placeholder from the examples. "+
+				"Set endpoint or NIXERNETES_ENDPOINT to the address of your Nixernetes API server. "+
+				"If the server does run locally, set allow_local_endpoint = true to silence this warning.",
+		)
+	}
+
+	if len(tlsInsecureHosts) > 0 {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("tls_insecure_hosts"),
+			"TLS Verification Disabled",
+			"TLS certificates are not verified for: "+strings.Join(tlsInsecureHosts, ", ")+". "+
+				"Connections to these hosts can be intercepted. Certificates of all other hosts are verified.",
+		)
+	}
+
+	insecureSkipVerify := config.InsecureSkipVerify.ValueBool()
+	if insecureSkipVerify {
+		tflog.Warn(ctx, "TLS certificate verification is disabled for the Nixernetes API, connections can be intercepted", map[string]any{"endpoint": endpoint})
+	}
+
 	ctx = tflog.SetField(ctx, "nixernetes_endpoint", endpoint)
 	ctx = tflog.SetField(ctx, "nixernetes_username", username)
 	ctx = tflog.MaskFieldValues(ctx, "nixernetes_password")
@@ -138,11 +612,78 @@ func (p *NixernetesProvider) Configure(ctx context.Context, req provider.Configu
 		Endpoint: endpoint,
 		Username: username,
 		Password: password,
+
+		RedactionPatterns: redactionPatterns,
+		UpdateMethod:      updateMethod,
+
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		Timeout:               timeout,
+		DialTimeout:           dialTimeout,
+		OperationTimeout:      operationTimeout,
+
+		APIPrefix:    config.APIPrefix.ValueString(),
+		ConfigsPath:  configsPath,
+		ModulesPath:  modulesPath,
+		ProjectsPath: projectsPath,
+
+		KeepAlive:       keepAlive,
+		MaxConnLifetime: maxConnLifetime,
+		MaxIdleConns:    maxIdleConns,
+		IdleConnTimeout: idleConnTimeout,
+
+		Limiter:     limiter,
+		Concurrency: concurrency,
+
+		RetryPolicy:    retryPolicy,
+		SendContentMD5: config.SendContentMD5.ValueBool(),
+		DebugHTTP:      config.DebugHTTP.ValueBool(),
+
+		Propagator:  propagator,
+		TraceParent: traceParent,
+
+		DefaultEnvironment: normalizeEnvironment(defaultEnvironment),
+		DefaultReplicas:    defaultReplicas,
+
+		TLSInsecureHosts:   tlsInsecureHosts,
+		RootCAs:            rootCAs,
+		InsecureSkipVerify: insecureSkipVerify,
+		ClientCertificate:  clientCertificate,
+
+		RequestLog: requestLog,
+
+		UserAgent: userAgent(p.version, req.TerraformVersion),
+	}
+
+	if config.ValidateAgainstServerSchema.ValueBool() {
+		schema, err := client.FetchConfigSchema(ctx)
+		switch {
+		case err != nil:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("validate_against_server_schema"),
+				"Server Configuration Schema Unavailable",
+				"Configurations will not be validated against the server's schema, as it could not be loaded: "+err.Error(),
+			)
+		case schema == nil:
+			tflog.Info(ctx, "API server does not publish a configuration schema, skipping schema validation")
+		default:
+			client.ConfigSchema = schema
+		}
+	}
+
+	// Servers that do not advertise capabilities get the built-in defaults.
+	capabilities, err := client.FetchCapabilities(ctx)
+	switch {
+	case err != nil:
+		tflog.Warn(ctx, "Could not read API server capabilities, using defaults", map[string]any{"error": err.Error()})
+	case capabilities != nil:
+		client.MaxConfigSize = capabilities.MaxConfigSize
 	}
 
-	// Make the client available during DataSource and Resource type Configure methods.
+	// Make the client available during DataSource, Resource and
+	// EphemeralResource type Configure methods.
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.EphemeralResourceData = client
 
 	tflog.Info(ctx, "Configured Nixernetes provider", map[string]any{"success": true})
 }
@@ -153,6 +694,10 @@ func (p *NixernetesProvider) Resources(ctx context.Context) []func() resource.Re
 		NewNixernetesConfigResource,
 		NewNixernetesModuleResource,
 		NewNixernetesProjectResource,
+		NewNixernetesResourceQuotaResource,
+		NewNixernetesModuleRolloutResource,
+		NewNixernetesSecretResource,
+		NewNixernetesModuleGroupResource,
 	}
 }
 
@@ -161,6 +706,32 @@ func (p *NixernetesProvider) DataSources(ctx context.Context) []func() datasourc
 	return []func() datasource.DataSource{
 		NewNixernetesModulesDataSource,
 		NewNixernetesProjectsDataSource,
+		NewNixernetesConfigDataSource,
+		NewNixernetesProjectDataSource,
+		NewNixernetesModuleEventsDataSource,
+		NewNixernetesModuleStatusDataSource,
+		NewNixernetesInventoryDataSource,
+		NewNixernetesClusterDataSource,
+		NewNixernetesModuleCostDataSource,
+		NewNixernetesDeploymentsDataSource,
+		NewNixernetesConfigVersionDataSource,
+	}
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *NixernetesProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewNixernetesDeployTokenEphemeralResource,
+	}
+}
+
+// Functions defines the provider-defined functions available in the provider.
+func (p *NixernetesProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewValidateConfigFunction,
+		NewCanonicalImageFunction,
+		NewToNamespaceFunction,
+		NewConfigAttrFunction,
 	}
 }
 
@@ -169,4 +740,185 @@ type NixernetesClient struct {
 	Endpoint string
 	Username string
 	Password string
+
+	// RedactionPatterns are additional regexes scrubbed from logged URLs and bodies.
+	RedactionPatterns []*regexp.Regexp
+
+	// UpdateMethod is the HTTP method used by Update; empty means PUT, and
+	// UpdateChanges sending a merge patch.
+	UpdateMethod string
+
+	// ResponseHeaderTimeout limits the wait for response headers; zero means no limit.
+	ResponseHeaderTimeout time.Duration
+
+	// Timeout limits each request as a whole and DialTimeout the connection
+	// setup; zero means no limit and the Go default respectively.
+	Timeout     time.Duration
+	DialTimeout time.Duration
+
+	// OperationTimeout bounds resource operations without their own timeout;
+	// zero means defaultOperationTimeout.
+	OperationTimeout time.Duration
+
+	// APIPrefix is prepended to every request path, e.g. "/api/v1"; see
+	// requestURL.
+	APIPrefix string
+
+	// ConfigsPath, ModulesPath and ProjectsPath override the API base path of
+	// each resource type; empty means the default, e.g. "/configs".
+	ConfigsPath  string
+	ModulesPath  string
+	ProjectsPath string
+
+	// ConfigSchema is the API server's configuration schema, fetched when
+	// validate_against_server_schema is set; nil disables the check.
+	ConfigSchema *jsonschema.Schema
+
+	// RetryPolicy is used by Get, Post, Put, Update and Delete; the zero
+	// value never retries. The *WithRetry variants take a policy per call.
+	RetryPolicy RetryPolicy
+
+	// SendContentMD5 adds a Content-MD5 header to request bodies and verifies
+	// the Content-MD5 header of responses that carry one.
+	SendContentMD5 bool
+
+	// DebugHTTP logs every request and response, headers and bodies
+	// included, at trace level. Credentials are redacted; see debugPayload.
+	DebugHTTP bool
+
+	// Propagator, when set, injects the trace context of each request into
+	// its headers. TraceParent is the trace used for requests whose context
+	// carries none. Without a Propagator no trace headers are sent.
+	Propagator  propagation.TextMapPropagator
+	TraceParent trace.SpanContext
+
+	// MaxConfigSize is the largest configuration, in bytes, the API server
+	// advertises it accepts; zero means defaultMaxConfigSize applies.
+	MaxConfigSize int64
+
+	// DefaultEnvironment is planned for configurations that do not set an
+	// environment; empty leaves the choice to the server.
+	DefaultEnvironment string
+
+	// DefaultReplicas, when set, is the replica count of modules that set
+	// neither replicas nor autoscaling; nil leaves it to the server.
+	DefaultReplicas *int64
+
+	// KeepAlive is the TCP keep-alive period of new connections; zero uses
+	// the Go default. MaxConnLifetime bounds how long connections are reused;
+	// zero means no limit.
+	KeepAlive       time.Duration
+	MaxConnLifetime time.Duration
+
+	// MaxIdleConns is how many idle connections are kept for reuse, and
+	// IdleConnTimeout how long each is kept; zero uses the Go defaults.
+	MaxIdleConns    int
+	IdleConnTimeout time.Duration
+
+	// Limiter, when set, bounds the rate of requests. The client is created
+	// once per provider, so the limit applies to the whole run.
+	Limiter *rate.Limiter
+
+	// Concurrency, when set, bounds the number of requests in flight. Like
+	// Limiter it is shared by every resource and data source.
+	Concurrency *semaphore.Weighted
+
+	// TLSInsecureHosts are hosts whose TLS certificates are not verified.
+	TLSInsecureHosts []string
+
+	// RootCAs, when set, holds the system roots plus the configured CA
+	// certificates for verifying the API server's certificate.
+	// InsecureSkipVerify disables verification.
+	RootCAs            *x509.CertPool
+	InsecureSkipVerify bool
+
+	// ClientCertificate, when set, is presented to servers that request a
+	// client certificate, for gateways that require mutual TLS.
+	ClientCertificate *tls.Certificate
+
+	// RequestLog, when set, receives a line for every request; see
+	// request_log_file.
+	RequestLog *requestLog
+
+	// UserAgent is sent with every request; empty means userAgentProduct
+	// alone. See userAgent.
+	UserAgent string
+
+	httpClientOnce sync.Once
+	httpClient     *http.Client
+	lastConnSweep  atomic.Int64
+
+	// metrics accumulates payload sizes across requests; see Metrics.
+	metrics clientMetrics
+}
+
+// userAgentProduct names the provider in the User-Agent header.
+const userAgentProduct = "terraform-provider-nixernetes"
+
+// userAgent builds the User-Agent header from the provider and Terraform
+// versions, e.g. "terraform-provider-nixernetes/1.2.3 (+terraform 1.7.0)",
+// leaving out whichever is unknown.
+func userAgent(providerVersion, terraformVersion string) string {
+	ua := userAgentProduct
+	if providerVersion != "" {
+		ua += "/" + providerVersion
+	}
+	if terraformVersion != "" {
+		ua += " (+terraform " + terraformVersion + ")"
+	}
+	return ua
+}
+
+// parseDurationSetting parses a provider duration setting: a Go duration
+// such as "30s" or "2m", or a whole number of seconds such as "30".
+func parseDurationSetting(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if seconds, convErr := strconv.ParseInt(value, 10, 64); convErr == nil {
+		d, err = time.Duration(seconds)*time.Second, nil
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("must be a positive duration such as \"30s\" or \"2m\", or a whole number of seconds, got: %s", value)
+	}
+	return d, nil
+}
+
+// loadCACertificate returns the system roots plus the certificates in value,
+// which is either PEM data or the path of a PEM file.
+func loadCACertificate(value string) (*x509.CertPool, error) {
+	data, err := readPEMSetting(value)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM-encoded certificate found")
+	}
+	return pool, nil
+}
+
+// loadClientCertificate parses a client certificate and its private key, each
+// either PEM data or the path of a PEM file, checking that they match.
+func loadClientCertificate(certificate, key string) (tls.Certificate, error) {
+	certPEM, err := readPEMSetting(certificate)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := readPEMSetting(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// readPEMSetting returns value when it is PEM data, and otherwise the
+// contents of the file it names.
+func readPEMSetting(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
 }
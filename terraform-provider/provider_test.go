@@ -2,10 +2,20 @@ package main
 
 import (
 	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
@@ -154,6 +164,45 @@ func TestAccProjectsDataSource(t *testing.T) {
 	})
 }
 
+func TestAccDeploymentsDataSource(t *testing.T) {
+	rName := acctest.RandomWithPrefix("test-module-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDeploymentsDataSourceConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.nixernetes_deployments.test", "deployments.#"),
+					resource.TestCheckResourceAttrSet("data.nixernetes_deployments.test", "deployments.0.module_name"),
+					resource.TestCheckResourceAttr("data.nixernetes_deployments.test", "deployments.0.namespace", "default"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccConfigDataSource(t *testing.T) {
+	rName := acctest.RandomWithPrefix("test-config-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: protoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccConfigDataSourceConfig(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.nixernetes_config.test", "id", "nixernetes_config.test", "id"),
+					resource.TestCheckResourceAttr("data.nixernetes_config.test", "environment", "development"),
+					resource.TestCheckResourceAttrSet("data.nixernetes_config.test", "configuration"),
+					resource.TestCheckResourceAttrSet("data.nixernetes_config.test", "created_at"),
+				),
+			},
+		},
+	})
+}
+
 func testAccPreCheck(t *testing.T) {
 	// TODO: Verify that environment variables are set
 	// Typically this would check for:
@@ -282,6 +331,22 @@ data "nixernetes_modules" "test" {}
 `
 }
 
+func testAccConfigDataSourceConfig(name string) string {
+	return testAccConfigResourceConfig(name) + `
+data "nixernetes_config" "test" {
+  name = nixernetes_config.test.name
+}
+`
+}
+
+func testAccDeploymentsDataSourceConfig(name string) string {
+	return testAccModuleResourceConfig(name) + `
+data "nixernetes_deployments" "test" {
+  namespace = nixernetes_module.test.namespace
+}
+`
+}
+
 func testAccProjectsDataSourceConfig() string {
 	return `
 provider "nixernetes" {
@@ -293,3 +358,170 @@ provider "nixernetes" {
 data "nixernetes_projects" "test" {}
 `
 }
+
+func TestParseDurationSetting(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"30s", 30 * time.Second, false},
+		{"2m", 2 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"500ms", 500 * time.Millisecond, false},
+		{"30", 30 * time.Second, false},
+		{"0", 0, true},
+		{"-5s", 0, true},
+		{"-5", 0, true},
+		{"1.5", 0, true},
+		{"thirty seconds", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDurationSetting(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDurationSetting(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDurationSetting(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+// testProviderConfigure configures the provider with the credentials and the
+// given string attributes, leaving the others unset.
+func testProviderConfigure(t *testing.T, attributes map[string]string) provider.ConfigureResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	p := &NixernetesProvider{version: "test"}
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	config := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	values := map[string]string{"endpoint": "http://127.0.0.1:1", "username": "admin", "password": "secret"}
+	for name, value := range attributes {
+		values[name] = value
+	}
+	for name, value := range values {
+		if diags := config.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatalf("Unexpected config diagnostics: %v", diags)
+		}
+	}
+
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw}}, &resp)
+	return resp
+}
+
+func TestProviderRetryBaseDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]string
+		want       time.Duration
+		wantErr    bool
+	}{
+		{"default", nil, defaultRetryBackoff, false},
+		{"retry_base_delay", map[string]string{"retry_base_delay": "2s"}, 2 * time.Second, false},
+		{"deprecated retry_backoff", map[string]string{"retry_backoff": "3s"}, 3 * time.Second, false},
+		{"both", map[string]string{"retry_base_delay": "2s", "retry_backoff": "3s"}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testProviderConfigure(t, tt.attributes)
+			if tt.wantErr {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Conflicting Retry Delay" {
+					t.Errorf("Expected a conflicting retry delay error, got %v", resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if got := resp.ResourceData.(*NixernetesClient).RetryPolicy.Backoff; got != tt.want {
+				t.Errorf("Expected a base delay of %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadCACertificate(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, caPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	invalidFile := filepath.Join(t.TempDir(), "invalid.pem")
+	if err := os.WriteFile(invalidFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"PEM", string(caPEM), false},
+		{"file", caFile, false},
+		{"invalid PEM", "-----BEGIN CERTIFICATE-----\nnot base64\n-----END CERTIFICATE-----\n", true},
+		{"file without certificates", invalidFile, true},
+		{"missing file", filepath.Join(t.TempDir(), "missing.pem"), true},
+	}
+
+	for _, tt := range tests {
+		pool, err := loadCACertificate(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && pool == nil {
+			t.Errorf("%s: expected a certificate pool", tt.name)
+		}
+	}
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	certPEM, keyPEM := testClientCertificate(t)
+	_, otherKeyPEM := testClientCertificate(t)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		certificate string
+		key         string
+		wantErr     bool
+	}{
+		{"PEM", string(certPEM), string(keyPEM), false},
+		{"files", certFile, keyFile, false},
+		{"PEM certificate and key file", string(certPEM), keyFile, false},
+		{"mismatched key", string(certPEM), string(otherKeyPEM), true},
+		{"key as certificate", string(keyPEM), string(keyPEM), true},
+		{"missing file", filepath.Join(dir, "missing.pem"), keyFile, true},
+	}
+
+	for _, tt := range tests {
+		cert, err := loadClientCertificate(tt.certificate, tt.key)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && len(cert.Certificate) == 0 {
+			t.Errorf("%s: expected a certificate", tt.name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// redactedPlaceholder replaces secret values in logged text.
+const redactedPlaceholder = "[REDACTED]"
+
+// redactionRule replaces every match of pattern with replacement.
+type redactionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// defaultRedactionRules scrub secret shapes that are always hidden from logs,
+// keeping the key so the log line stays readable.
+var defaultRedactionRules = []redactionRule{
+	{
+		// password=..., "token": "...", api_key: ...
+		pattern:     regexp.MustCompile(`(?i)("?(?:password|passwd|secret|token|api[_-]?key)"?\s*[:=]\s*"?)[^"&\s,}]+`),
+		replacement: "${1}" + redactedPlaceholder,
+	},
+	{
+		// Authorization header values
+		pattern:     regexp.MustCompile(`(?i)\b(bearer|basic)\s+[a-z0-9._~+/=-]+`),
+		replacement: "${1} " + redactedPlaceholder,
+	},
+	{
+		// Credentials embedded in URLs
+		pattern:     regexp.MustCompile(`//[^/@\s]+:[^/@\s]+@`),
+		replacement: "//" + redactedPlaceholder + "@",
+	},
+}
+
+// compileRedactionPatterns compiles user-supplied redaction regexes.
+func compileRedactionPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// redact scrubs secrets from text before it is logged, applying the built-in
+// rules followed by the provider-configured patterns.
+func (c *NixernetesClient) redact(text string) string {
+	for _, rule := range defaultRedactionRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	for _, re := range c.RedactionPatterns {
+		text = re.ReplaceAllString(text, redactedPlaceholder)
+	}
+	return text
+}
+
+// sensitiveFieldPattern matches the names of body fields whose values are
+// always redacted from debug_http logs.
+var sensitiveFieldPattern = regexp.MustCompile(`(?i)password|passwd|secret|token|api[_-]?key`)
+
+// sensitiveHeaders are the headers whose values are always redacted from
+// debug_http logs.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// redactSensitive returns a copy of body with the values of sensitive fields,
+// at any depth, replaced by the placeholder.
+func redactSensitive(body map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(body))
+	for key, value := range body {
+		if sensitiveFieldPattern.MatchString(key) {
+			redacted[key] = redactedPlaceholder
+			continue
+		}
+		redacted[key] = redactSensitiveValue(value)
+	}
+	return redacted
+}
+
+// redactSensitiveValue applies redactSensitive to the objects in value.
+func redactSensitiveValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactSensitive(v)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactSensitiveValue(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// redactHeaders flattens headers for logging, replacing the values of
+// sensitive headers with the placeholder.
+func redactHeaders(headers http.Header) map[string]string {
+	flat := make(map[string]string, len(headers))
+	for name, values := range headers {
+		flat[name] = strings.Join(values, ", ")
+	}
+	for _, name := range sensitiveHeaders {
+		if _, ok := flat[name]; ok {
+			flat[name] = redactedPlaceholder
+		}
+	}
+	return flat
+}
+
+// debugPayload renders a request or response body for debug_http logs. JSON
+// objects have their sensitive fields redacted; any body then goes through
+// the same redaction as other logged text.
+func (c *NixernetesClient) debugPayload(data []byte) string {
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err == nil && body != nil {
+		if redacted, err := json.Marshal(redactSensitive(body)); err == nil {
+			data = redacted
+		}
+	}
+	return c.redact(string(data))
+}
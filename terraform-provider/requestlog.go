@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// defaultRequestLogMaxBytes is the size at which the request log is rotated
+// when request_log_max_size is not set.
+const defaultRequestLogMaxBytes = 10 * 1024 * 1024
+
+// requestLogEntry is one line of the request log.
+type requestLogEntry struct {
+	Timestamp  string  `json:"timestamp"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+	RequestID  string  `json:"request_id,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// requestLog appends JSON lines to a file, moving it aside to "<path>.1"
+// once it would grow beyond maxBytes. It is safe for concurrent use.
+type requestLog struct {
+	path     string
+	maxBytes int64
+
+	mu sync.Mutex
+}
+
+// newRequestLog returns a request log writing to path. A maxBytes of zero or
+// less uses defaultRequestLogMaxBytes.
+func newRequestLog(path string, maxBytes int64) *requestLog {
+	if maxBytes <= 0 {
+		maxBytes = defaultRequestLogMaxBytes
+	}
+	return &requestLog{path: path, maxBytes: maxBytes}
+}
+
+// write appends entry as a single line, rotating the file first if needed.
+func (l *requestLog) write(entry requestLogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode request log entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if info, err := os.Stat(l.path); err == nil && info.Size() > 0 && info.Size()+int64(len(line)) > l.maxBytes {
+		if err := os.Rename(l.path, l.path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate request log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open request log: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write request log: %w", err)
+	}
+	return f.Close()
+}
+
+// logRequest records a finished request in the request log, if one is
+// configured. The path and error are redacted like every other log output.
+func (c *NixernetesClient) logRequest(method, endpoint string, start time.Time, status int, requestID string, reqErr error) error {
+	if c.RequestLog == nil {
+		return nil
+	}
+	entry := requestLogEntry{
+		Timestamp:  start.UTC().Format(time.RFC3339Nano),
+		Method:     method,
+		Path:       c.redact(endpoint),
+		Status:     status,
+		DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+		RequestID:  requestID,
+	}
+	if reqErr != nil {
+		entry.Error = c.redact(reqErr.Error())
+	}
+	return c.RequestLog.write(entry)
+}
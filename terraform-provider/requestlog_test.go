@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func readRequestLog(t *testing.T, path string) []requestLogEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	var entries []requestLogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry requestLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Request log line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIDHeader, "req-42")
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"config-123"}`))
+	}))
+	defer server.Close()
+
+	logPath := filepath.Join(t.TempDir(), "requests.jsonl")
+	client := &NixernetesClient{
+		Endpoint:   server.URL,
+		Username:   "testuser",
+		Password:   "testpass",
+		RequestLog: newRequestLog(logPath, 0),
+	}
+
+	if _, err := client.Get(context.Background(), "/configs/config-123?token=hunter2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.Post(context.Background(), "/configs", map[string]interface{}{"name": "test"}); err == nil {
+		t.Fatal("Expected error for 500 response")
+	}
+
+	entries := readRequestLog(t, logPath)
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 request log entries, got %d", len(entries))
+	}
+
+	get := entries[0]
+	if get.Method != "GET" || get.Status != 200 || get.RequestID != "req-42" {
+		t.Errorf("Unexpected GET entry: %+v", get)
+	}
+	if strings.Contains(get.Path, "hunter2") {
+		t.Errorf("Expected token to be redacted from path, got %q", get.Path)
+	}
+	if get.Timestamp == "" || get.DurationMS < 0 {
+		t.Errorf("Expected timestamp and duration, got %+v", get)
+	}
+
+	post := entries[1]
+	if post.Method != "POST" || post.Status != 500 || post.Error == "" {
+		t.Errorf("Unexpected POST entry: %+v", post)
+	}
+}
+
+func TestRequestLogRotation(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "requests.jsonl")
+	log := newRequestLog(logPath, 200)
+
+	for i := 0; i < 5; i++ {
+		if err := log.write(requestLogEntry{Method: "GET", Path: "/configs/config-123"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info.Size() > 200 {
+		t.Errorf("Expected log to be rotated below 200 bytes, got %d", info.Size())
+	}
+	if _, err := os.Stat(logPath + ".1"); err != nil {
+		t.Errorf("Expected rotated log file: %v", err)
+	}
+}
+
+func TestRequestLogConcurrentWrites(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "requests.jsonl")
+	log := newRequestLog(logPath, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := log.write(requestLogEntry{Method: "GET", Path: "/modules"}); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if entries := readRequestLog(t, logPath); len(entries) != 50 {
+		t.Errorf("Expected 50 request log entries, got %d", len(entries))
+	}
+}
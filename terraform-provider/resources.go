@@ -2,21 +2,61 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &NixernetesConfigResource{}
-	_ resource.ResourceWithConfigure = &NixernetesConfigResource{}
-	_ resource.Resource              = &NixernetesModuleResource{}
-	_ resource.ResourceWithConfigure = &NixernetesModuleResource{}
-	_ resource.Resource              = &NixernetesProjectResource{}
-	_ resource.ResourceWithConfigure = &NixernetesProjectResource{}
+	_ resource.Resource                     = &NixernetesConfigResource{}
+	_ resource.ResourceWithConfigure        = &NixernetesConfigResource{}
+	_ resource.ResourceWithConfigValidators = &NixernetesConfigResource{}
+	_ resource.ResourceWithValidateConfig   = &NixernetesConfigResource{}
+	_ resource.ResourceWithModifyPlan       = &NixernetesConfigResource{}
+	_ resource.ResourceWithImportState      = &NixernetesConfigResource{}
+	_ resource.Resource                     = &NixernetesModuleResource{}
+	_ resource.ResourceWithConfigure        = &NixernetesModuleResource{}
+	_ resource.ResourceWithValidateConfig   = &NixernetesModuleResource{}
+	_ resource.ResourceWithImportState      = &NixernetesModuleResource{}
+	_ resource.Resource                     = &NixernetesProjectResource{}
+	_ resource.ResourceWithConfigure        = &NixernetesProjectResource{}
+	_ resource.ResourceWithValidateConfig   = &NixernetesProjectResource{}
+	_ resource.ResourceWithModifyPlan       = &NixernetesProjectResource{}
+	_ resource.ResourceWithImportState      = &NixernetesProjectResource{}
+	_ resource.Resource                     = &NixernetesResourceQuotaResource{}
+	_ resource.ResourceWithConfigure        = &NixernetesResourceQuotaResource{}
+	_ resource.ResourceWithValidateConfig   = &NixernetesResourceQuotaResource{}
+	_ resource.ResourceWithImportState      = &NixernetesResourceQuotaResource{}
+	_ resource.Resource                     = &NixernetesModuleRolloutResource{}
+	_ resource.ResourceWithConfigure        = &NixernetesModuleRolloutResource{}
+	_ resource.ResourceWithValidateConfig   = &NixernetesModuleRolloutResource{}
+	_ resource.Resource                     = &NixernetesSecretResource{}
+	_ resource.ResourceWithConfigure        = &NixernetesSecretResource{}
+	_ resource.ResourceWithValidateConfig   = &NixernetesSecretResource{}
+	_ resource.Resource                     = &NixernetesModuleGroupResource{}
+	_ resource.ResourceWithConfigure        = &NixernetesModuleGroupResource{}
+	_ resource.ResourceWithValidateConfig   = &NixernetesModuleGroupResource{}
 )
 
 // NewNixernetesConfigResource is a helper function to simplify the provider implementation.
@@ -33,10 +73,47 @@ type NixernetesConfigResource struct {
 type NixernetesConfigModel struct {
 	ID            types.String `tfsdk:"id"`
 	Name          types.String `tfsdk:"name"`
+	EffectiveName types.String `tfsdk:"effective_name"`
 	Configuration types.String `tfsdk:"configuration"`
 	Environment   types.String `tfsdk:"environment"`
+	Enabled       types.Bool   `tfsdk:"enabled"`
 	CreatedAt     types.String `tfsdk:"created_at"`
 	UpdatedAt     types.String `tfsdk:"updated_at"`
+
+	ConfigurationBase64  types.String `tfsdk:"configuration_base64"`
+	ConfigurationSummary types.String `tfsdk:"configuration_summary"`
+
+	VerifyBuild  types.Bool   `tfsdk:"verify_build"`
+	BuildTimeout types.String `tfsdk:"build_timeout"`
+
+	Active          types.Bool `tfsdk:"active"`
+	ForceDeactivate types.Bool `tfsdk:"force_deactivate"`
+
+	Priority types.Int64 `tfsdk:"priority"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+// defaultBuildTimeout bounds a verify_build dry build when build_timeout is unset.
+const defaultBuildTimeout = 30 * time.Minute
+
+// content returns the Nix configuration content, decoding
+// configuration_base64 when it is set instead of configuration.
+func (m *NixernetesConfigModel) content() (string, error) {
+	return decodeConfiguration(m.Configuration, m.ConfigurationBase64)
+}
+
+// decodeConfiguration returns configuration, or the decoded content of
+// encoded when that is set instead.
+func decodeConfiguration(configuration, encoded types.String) (string, error) {
+	if encoded.IsNull() || encoded.IsUnknown() {
+		return configuration.ValueString(), nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.ValueString())
+	if err != nil {
+		return "", fmt.Errorf("configuration_base64 is not valid base64: %w", err)
+	}
+	return string(decoded), nil
 }
 
 // Metadata returns the resource type name.
@@ -54,18 +131,35 @@ func (r *NixernetesConfigResource) Schema(ctx context.Context, _ resource.Schema
 				Computed:            true,
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Configuration name",
+				MarkdownDescription: "Configuration name. Changing it replaces the configuration.",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"effective_name": schema.StringAttribute{
+				MarkdownDescription: "Name the server assigned to the configuration. Usually equal to `name`, but servers that slugify or suffix names may return e.g. `api-7f3a` for `api`.",
+				Computed:            true,
 			},
 			"configuration": schema.StringAttribute{
-				MarkdownDescription: "Nix configuration content",
-				Required:            true,
+				MarkdownDescription: "Nix configuration content. Exactly one of `configuration` and `configuration_base64` must be set.",
+				Optional:            true,
+			},
+			"configuration_base64": schema.StringAttribute{
+				MarkdownDescription: "Base64-encoded Nix configuration content, for configurations that are awkward to escape in HCL. The provider decodes it before validating and sending it to the API.",
+				Optional:            true,
 			},
 			"environment": schema.StringAttribute{
 				MarkdownDescription: "Deployment environment (development, staging, production)",
 				Optional:            true,
 				Computed:            true,
 			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the configuration should exist. When false the resource stays in configuration but nothing is created, and an existing configuration is deleted.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
 			"created_at": schema.StringAttribute{
 				MarkdownDescription: "Creation timestamp",
 				Computed:            true,
@@ -74,10 +168,142 @@ func (r *NixernetesConfigResource) Schema(ctx context.Context, _ resource.Schema
 				MarkdownDescription: "Last update timestamp",
 				Computed:            true,
 			},
+			"verify_build": schema.BoolAttribute{
+				MarkdownDescription: "Have the API server dry-build the configuration after each create and update, and fail the apply with the tail of the build log if it does not build. Builds can take much longer than validation, so this is off by default.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"build_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for a `verify_build` dry build, as a duration such as `45m`. Defaults to `30m`.",
+				Optional:            true,
+			},
+			"active": schema.BoolAttribute{
+				MarkdownDescription: "Whether the configuration is active. Set to `false` to stage a configuration: it is uploaded but not applied until `active` is set to `true`. When unset the server's default is used and recorded in state.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"priority": schema.Int64Attribute{
+				MarkdownDescription: "Ordering weight when several configurations apply to the same target, from `0` to `1000`. Configurations are applied in ascending priority, so where they set the same option the one with the higher priority takes precedence. Configurations with equal priority are applied in creation order. Defaults to `0`.",
+				Optional:            true,
+			},
+			"force_deactivate": schema.BoolAttribute{
+				MarkdownDescription: "Deactivate the configuration even while running modules use it. Without it, setting `active = false` on a configuration in use fails. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"configuration_summary": schema.StringAttribute{
+				MarkdownDescription: "Top-level attribute paths touched by the latest change to `configuration`, e.g. `networking.firewall, services.nginx`. Null when the configuration cannot be parsed.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					configurationSummaryModifier{},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
 		},
 	}
 }
 
+// ConfigValidators returns the configuration-wide validators for the resource.
+func (r *NixernetesConfigResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		exactlyOneOfValidator{attributes: []string{"configuration", "configuration_base64"}},
+	}
+}
+
+// ValidateConfig checks build_timeout, that configuration_base64 decodes
+// to a configuration that passes the same syntax checks as configuration,
+// and that the configuration fits the size limit the API server advertises.
+func (r *NixernetesConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var configuration, encoded, buildTimeout types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("configuration"), &configuration)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("configuration_base64"), &encoded)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("build_timeout"), &buildTimeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !buildTimeout.IsNull() && !buildTimeout.IsUnknown() {
+		if _, err := parseBuildTimeout(buildTimeout); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("build_timeout"), "Invalid build timeout", err.Error())
+		}
+	}
+
+	if !configuration.IsNull() && !configuration.IsUnknown() {
+		if err := checkConfigSize(configuration.ValueString(), maxConfigSize(r.client)); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("configuration"), "Configuration too large", err.Error())
+		}
+	}
+
+	if encoded.IsNull() || encoded.IsUnknown() {
+		return
+	}
+
+	content, err := decodeConfiguration(types.StringNull(), encoded)
+	if err == nil && content == "" {
+		err = fmt.Errorf("configuration_base64 decodes to an empty configuration")
+	}
+	if err == nil {
+		err = checkConfigSize(content, maxConfigSize(r.client))
+	}
+	if err == nil {
+		err = checkNixSyntax(content)
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("configuration_base64"), "Invalid configuration", err.Error())
+	}
+}
+
+// exactlyOneOfValidator requires exactly one of a set of top-level string
+// attributes to be set. Unknown values are only checked once they are known,
+// unless the attributes already set rule them out.
+type exactlyOneOfValidator struct {
+	attributes []string
+}
+
+func (v exactlyOneOfValidator) Description(_ context.Context) string {
+	return "Exactly one of " + strings.Join(v.attributes, ", ") + " must be set."
+}
+
+func (v exactlyOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v exactlyOneOfValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var set []string
+	unknown := false
+	for _, name := range v.attributes {
+		var value types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+		switch {
+		case value.IsUnknown():
+			unknown = true
+		case !value.IsNull():
+			set = append(set, name)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case len(set) > 1:
+		resp.Diagnostics.AddAttributeError(
+			path.Root(set[1]),
+			"Conflicting attributes",
+			strings.Join(set, " and ")+" cannot both be set. "+v.Description(ctx),
+		)
+	case len(set) == 0 && !unknown:
+		resp.Diagnostics.AddError("Missing attribute", v.Description(ctx))
+	}
+}
+
 // Configure adds the provider configured client to the resource.
 func (r *NixernetesConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
@@ -92,6 +318,24 @@ func (r *NixernetesConfigResource) Configure(_ context.Context, req resource.Con
 	r.client = client
 }
 
+// ModifyPlan plans the provider's default_environment for a configuration
+// that does not set environment, so validation sees the environment the
+// configuration will be created with.
+func (r *NixernetesConfigResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil || r.client.DefaultEnvironment == "" {
+		return
+	}
+
+	var environment types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("environment"), &environment)...)
+	if resp.Diagnostics.HasError() || !environment.IsNull() {
+		return
+	}
+
+	tflog.Debug(ctx, "Planning default environment", map[string]any{"environment": r.client.DefaultEnvironment})
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("environment"), types.StringValue(r.client.DefaultEnvironment))...)
+}
+
 // Create creates a new configuration.
 func (r *NixernetesConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan NixernetesConfigModel
@@ -102,15 +346,34 @@ func (r *NixernetesConfigResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	// API call to create configuration
-	body := map[string]interface{}{
-		"name":          plan.Name.ValueString(),
-		"configuration": plan.Configuration.ValueString(),
-		"environment":   plan.Environment.ValueString(),
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	response, err := r.client.Post(ctx, "/configs", body)
-	if err != nil {
+	resp.Diagnostics.Append(r.validatePlan(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ConfigurationSummary.IsUnknown() {
+		plan.ConfigurationSummary = configurationSummary(types.StringNull(), decodedConfiguration(plan.Configuration, plan.ConfigurationBase64))
+	}
+
+	if !isEnabled(plan.Enabled) {
+		tflog.Debug(ctx, "Configuration disabled, skipping creation", map[string]any{"name": plan.Name.ValueString()})
+		plan.clearRemote()
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if err := r.createRemote(ctx, &plan, &resp.State); err != nil {
+		if diags := fieldErrorDiagnostics(err, configFieldPaths); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
 		resp.Diagnostics.AddError(
 			"Error creating configuration",
 			"Could not create configuration, unexpected error: "+err.Error(),
@@ -118,16 +381,232 @@ func (r *NixernetesConfigResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	plan.ID = types.StringValue(response["id"].(string))
-	plan.CreatedAt = types.StringValue(response["created_at"].(string))
-	plan.UpdatedAt = types.StringValue(response["updated_at"].(string))
-
 	tflog.Trace(ctx, "Created configuration", map[string]any{"id": plan.ID.ValueString()})
 
+	// The configuration exists from here on, so state is saved even if the
+	// build fails; Terraform taints a resource whose create errors with state set.
+	resp.Diagnostics.Append(r.verifyBuild(ctx, &plan)...)
+
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
 
+// createRemote creates the configuration through the API and records the
+// server-assigned attributes on the model.
+func (r *NixernetesConfigResource) createRemote(ctx context.Context, plan *NixernetesConfigModel, state *tfsdk.State) error {
+	// A staged configuration is created inactive rather than deactivated
+	// after the fact, so it is never applied.
+	body := configRequestBody(plan)
+	if !plan.Active.IsNull() && !plan.Active.IsUnknown() {
+		body["active"] = plan.Active.ValueBool()
+	}
+
+	// API call to create configuration
+	response, err := r.client.Post(ctx, r.client.configsPath(), body)
+	if err != nil {
+		return err
+	}
+
+	id, ok := response["id"].(string)
+	if !ok {
+		return fmt.Errorf("create response did not include an id")
+	}
+	plan.ID = types.StringValue(id)
+	recordCreatedID(ctx, state, plan.ID)
+	fields, err := requireStrings(response, "created_at", "updated_at")
+	if err != nil {
+		return err
+	}
+	plan.EffectiveName = effectiveName(response, plan.Name)
+	plan.CreatedAt = types.StringValue(fields["created_at"])
+	plan.UpdatedAt = types.StringValue(fields["updated_at"])
+	plan.Active = activeFromResponse(response, plan.Active)
+	plan.Environment = environmentFromResponse(response, plan.Environment)
+
+	return nil
+}
+
+// environmentFromResponse returns the environment the API reports for the
+// configuration in its canonical form, so one the server picked is recorded
+// in state. A configured environment that differs from the remote one only
+// in casing is kept as written: Terraform rejects state that does not match
+// the configuration, and keeping it avoids a perpetual diff.
+func environmentFromResponse(response map[string]interface{}, prior types.String) types.String {
+	remote, ok := response["environment"].(string)
+	if !ok {
+		if prior.IsUnknown() {
+			return types.StringNull()
+		}
+		return prior
+	}
+	if !prior.IsNull() && !prior.IsUnknown() && strings.EqualFold(prior.ValueString(), remote) {
+		return prior
+	}
+	return types.StringValue(normalizeEnvironment(remote))
+}
+
+// activeFromResponse returns whether the API reports the configuration as
+// active, falling back to fallback for servers that do not report it.
+func activeFromResponse(response map[string]interface{}, fallback types.Bool) types.Bool {
+	if active, ok := response["active"].(bool); ok {
+		return types.BoolValue(active)
+	}
+	if fallback.IsUnknown() {
+		return types.BoolNull()
+	}
+	return fallback
+}
+
+// configInUseCode is the error code of a 409 returned when deactivating a
+// configuration that running modules still use.
+const configInUseCode = "config_in_use"
+
+// setActive activates or deactivates the configuration through the API. A
+// deactivation that running modules block is reported as such, unless
+// force_deactivate is set, in which case the server is asked to force it.
+func (r *NixernetesConfigResource) setActive(ctx context.Context, plan *NixernetesConfigModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	endpoint := r.client.configsPath() + "/" + url.PathEscape(plan.ID.ValueString()) + "/activate"
+	if !plan.Active.ValueBool() {
+		endpoint = r.client.configsPath() + "/" + url.PathEscape(plan.ID.ValueString()) + "/deactivate"
+		if plan.ForceDeactivate.ValueBool() {
+			endpoint += "?force=true"
+		}
+	}
+
+	tflog.Debug(ctx, "Changing configuration activation", map[string]any{"id": plan.ID.ValueString(), "active": plan.Active.ValueBool()})
+	response, err := r.client.Post(ctx, endpoint, nil)
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 409 && httpErr.Code == configInUseCode {
+		diags.AddAttributeError(
+			path.Root("active"),
+			"Configuration in use",
+			fmt.Sprintf("Configuration %q cannot be deactivated while running modules use it: %s. "+
+				"Stop the modules or move them to another configuration first, or set force_deactivate = true to deactivate it anyway.",
+				plan.Name.ValueString(), httpErr.Message),
+		)
+		return diags
+	}
+	if err != nil {
+		verb := "activate"
+		if !plan.Active.ValueBool() {
+			verb = "deactivate"
+		}
+		diags.AddError("Error changing configuration activation", fmt.Sprintf("Could not %s configuration: %s", verb, err))
+		return diags
+	}
+
+	plan.Active = activeFromResponse(response, plan.Active)
+	return diags
+}
+
+// parseBuildTimeout returns the build_timeout duration, or the default when
+// it is unset.
+func parseBuildTimeout(value types.String) (time.Duration, error) {
+	if value.IsNull() {
+		return defaultBuildTimeout, nil
+	}
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("build_timeout must be a positive duration such as \"30m\", got: %s", value.ValueString())
+	}
+	return timeout, nil
+}
+
+// verifyBuild has the API server dry-build the configuration when
+// verify_build is set, reporting the tail of the build log if it fails.
+func (r *NixernetesConfigResource) verifyBuild(ctx context.Context, plan *NixernetesConfigModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !plan.VerifyBuild.ValueBool() {
+		return diags
+	}
+
+	timeout, err := parseBuildTimeout(plan.BuildTimeout)
+	if err != nil {
+		diags.AddAttributeError(path.Root("build_timeout"), "Invalid build timeout", err.Error())
+		return diags
+	}
+
+	tflog.Debug(ctx, "Verifying configuration build", map[string]any{"id": plan.ID.ValueString(), "timeout": timeout.String()})
+	err = r.client.WaitForDryBuild(ctx, plan.ID.ValueString(), timeout)
+	if buildErr, ok := err.(*BuildFailedError); ok {
+		diags.AddAttributeError(
+			path.Root("verify_build"),
+			"Configuration build failed",
+			fmt.Sprintf("Configuration %q was saved but does not build (%s). Last lines of the build log:\n\n%s",
+				plan.Name.ValueString(), buildErr, buildErr.LogTail()),
+		)
+	} else if err != nil {
+		diags.AddAttributeError(
+			path.Root("verify_build"),
+			"Error verifying configuration build",
+			fmt.Sprintf("Could not dry-build configuration %q: %s", plan.Name.ValueString(), err),
+		)
+	}
+	return diags
+}
+
+// validatePlan checks that configuration_base64 decodes, which may not have
+// been known during validation, and runs ValidateConfigModel against the
+// API server's size limit and, when the provider fetched one, its schema.
+func (r *NixernetesConfigResource) validatePlan(ctx context.Context, plan *NixernetesConfigModel) diag.Diagnostics {
+	if _, err := plan.content(); err != nil {
+		var diags diag.Diagnostics
+		diags.AddAttributeError(path.Root("configuration_base64"), "Invalid configuration", err.Error())
+		return diags
+	}
+	return ValidateConfigModel(ctx, plan, r.client.ConfigSchema, maxConfigSize(r.client)).ToDiagnosticsWithPath(configFieldPaths)
+}
+
+// configRequestBody builds the create and update request body for a
+// configuration. Unset optional fields are left out so the server applies
+// its defaults instead of storing empty values. configuration_base64 is sent
+// decoded; validatePlan has already rejected content that does not decode.
+func configRequestBody(plan *NixernetesConfigModel) map[string]interface{} {
+	content, _ := plan.content()
+	body := map[string]interface{}{
+		"name":          plan.Name.ValueString(),
+		"configuration": content,
+	}
+	if !plan.Environment.IsNull() && !plan.Environment.IsUnknown() {
+		body["environment"] = normalizeEnvironment(plan.Environment.ValueString())
+	}
+	if !plan.Priority.IsNull() && !plan.Priority.IsUnknown() {
+		body["priority"] = plan.Priority.ValueInt64()
+	}
+	return body
+}
+
+// defaultConfigPriority is the priority the server gives configurations
+// created without one.
+const defaultConfigPriority = 0
+
+// priorityFromResponse returns the priority the API reports for the
+// configuration. The default priority of a configuration that does not set
+// one is left null, as are servers that do not report it.
+func priorityFromResponse(response map[string]interface{}, prior types.Int64) types.Int64 {
+	priority, ok := response["priority"].(float64)
+	if !ok || (prior.IsNull() && int64(priority) == defaultConfigPriority) {
+		return prior
+	}
+	return types.Int64Value(int64(priority))
+}
+
+// clearRemote resets the server-assigned attributes of a disabled configuration.
+func (m *NixernetesConfigModel) clearRemote() {
+	m.ID = types.StringNull()
+	m.EffectiveName = types.StringNull()
+	m.CreatedAt = types.StringNull()
+	m.UpdatedAt = types.StringNull()
+	if m.Environment.IsUnknown() {
+		m.Environment = types.StringNull()
+	}
+	if m.Active.IsUnknown() {
+		m.Active = types.BoolNull()
+	}
+}
+
 // Read refreshes the configuration state.
 func (r *NixernetesConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state NixernetesConfigModel
@@ -138,8 +617,34 @@ func (r *NixernetesConfigResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A disabled configuration has nothing to refresh.
+	if !isEnabled(state.Enabled) || state.ID.IsNull() {
+		return
+	}
+
 	// API call to get configuration
-	response, err := r.client.Get(ctx, "/configs/"+state.ID.ValueString())
+	response, err := r.client.Get(ctx, r.client.configsPath()+"/"+url.PathEscape(state.ID.ValueString()))
+	if err != nil {
+		// Deleted outside Terraform; removing it plans a recreate.
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading configuration",
+			"Could not read configuration "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Servers that do not report the environment keep the one in state.
+	fields, err := requireStrings(response, "name", "configuration", "updated_at")
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error reading configuration",
@@ -148,50 +653,2895 @@ func (r *NixernetesConfigResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	state.Name = types.StringValue(response["name"].(string))
-	state.Configuration = types.StringValue(response["configuration"].(string))
-	state.Environment = types.StringValue(response["environment"].(string))
-	state.UpdatedAt = types.StringValue(response["updated_at"].(string))
+	state.Name, state.EffectiveName = namesFromResponse(fields["name"], state.Name, state.EffectiveName)
+	state.Configuration, state.ConfigurationBase64 = configurationFromResponse(fields["configuration"], state.Configuration, state.ConfigurationBase64)
+	state.Environment = environmentFromResponse(response, state.Environment)
+	state.UpdatedAt = types.StringValue(fields["updated_at"])
+	state.Active = activeFromResponse(response, state.Active)
+	state.Priority = priorityFromResponse(response, state.Priority)
+	// Needed after an import; created_at never changes otherwise.
+	if createdAt, ok := response["created_at"].(string); ok {
+		state.CreatedAt = types.StringValue(createdAt)
+	}
+	resp.Diagnostics.Append(recordGeneration(ctx, resp.Private, response)...)
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 }
 
+// ImportState imports a configuration by ID. The content is read into
+// configuration; switch the configuration to configuration_base64 after the
+// import if that is preferred.
+func (r *NixernetesConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, diags := resolveImportID(ctx, r.client, req.ID, r.client.configsPath(), "configs", "configuration")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(setImportDefaults(ctx, &resp.State, map[string]interface{}{
+		"enabled":          true,
+		"verify_build":     false,
+		"force_deactivate": false,
+	})...)
+}
+
+// configurationFromResponse records the remote configuration content in
+// whichever of configuration and configuration_base64 the resource uses. An
+// encoded value that decodes to the remote content is kept as written.
+func configurationFromResponse(remote string, configuration, encoded types.String) (types.String, types.String) {
+	if encoded.IsNull() {
+		return types.StringValue(remote), encoded
+	}
+	if current, err := decodeConfiguration(configuration, encoded); err == nil && current == remote {
+		return configuration, encoded
+	}
+	return configuration, types.StringValue(base64.StdEncoding.EncodeToString([]byte(remote)))
+}
+
 // Update updates the configuration.
 func (r *NixernetesConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan NixernetesConfigModel
+	var plan, state NixernetesConfigModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// API call to update configuration
-	body := map[string]interface{}{
-		"name":          plan.Name.ValueString(),
-		"configuration": plan.Configuration.ValueString(),
-		"environment":   plan.Environment.ValueString(),
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.validatePlan(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ConfigurationSummary.IsUnknown() {
+		plan.ConfigurationSummary = configurationSummary(
+			decodedConfiguration(state.Configuration, state.ConfigurationBase64),
+			decodedConfiguration(plan.Configuration, plan.ConfigurationBase64),
+		)
+	}
+
+	switch {
+	case !isEnabled(plan.Enabled):
+		// Disabling removes the configuration but keeps the resource in state.
+		if !state.ID.IsNull() {
+			err := r.client.Delete(ctx, r.client.configsPath()+"/"+url.PathEscape(state.ID.ValueString()))
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error disabling configuration",
+					"Could not delete configuration, unexpected error: "+err.Error(),
+				)
+				return
+			}
+			tflog.Trace(ctx, "Disabled configuration", map[string]any{"id": state.ID.ValueString()})
+		}
+		plan.clearRemote()
+
+	case state.ID.IsNull():
+		// Re-enabling a disabled configuration creates it from scratch.
+		if err := r.createRemote(ctx, &plan, &resp.State); err != nil {
+			if diags := fieldErrorDiagnostics(err, configFieldPaths); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+			resp.Diagnostics.AddError(
+				"Error enabling configuration",
+				"Could not create configuration, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		tflog.Trace(ctx, "Enabled configuration", map[string]any{"id": plan.ID.ValueString()})
+
+	default:
+		plan.ID = state.ID
+		plan.CreatedAt = state.CreatedAt
+
+		// API call to update configuration
+		body := configRequestBody(&plan)
+		resp.Diagnostics.Append(addGeneration(ctx, req.Private, body)...)
+		response, err := r.client.Update(ctx, r.client.configsPath()+"/"+url.PathEscape(plan.ID.ValueString()), body)
+		if isGenerationConflict(err) {
+			resp.Diagnostics.Append(generationConflictDiagnostic("configuration", plan.Name.ValueString()))
+			return
+		}
+		if diags := fieldErrorDiagnostics(err, configFieldPaths); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating configuration",
+				"Could not update configuration, unexpected error: "+err.Error(),
+			)
+			return
+		}
+
+		fields, err := requireStrings(response, "updated_at")
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error updating configuration",
+				"The configuration was updated, but its "+err.Error(),
+			)
+			return
+		}
+		plan.EffectiveName = effectiveName(response, plan.Name)
+		plan.UpdatedAt = types.StringValue(fields["updated_at"])
+		plan.Environment = environmentFromResponse(response, plan.Environment)
+		resp.Diagnostics.Append(recordGeneration(ctx, resp.Private, response)...)
+
+		if plan.Active.IsUnknown() {
+			plan.Active = state.Active
+		}
+		if !plan.Active.IsNull() && !plan.Active.Equal(state.Active) {
+			resp.Diagnostics.Append(r.setActive(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	if isEnabled(plan.Enabled) {
+		resp.Diagnostics.Append(r.verifyBuild(ctx, &plan)...)
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the configuration.
+func (r *NixernetesConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NixernetesConfigModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Nothing exists remotely for a disabled configuration.
+	if state.ID.IsNull() {
+		return
+	}
+
+	// API call to delete configuration
+	err := r.client.Delete(ctx, r.client.configsPath()+"/"+url.PathEscape(state.ID.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error deleting configuration",
+			"Could not delete configuration, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "Deleted configuration", map[string]any{"id": state.ID.ValueString()})
+}
+
+// recordCreatedID writes the ID of a just-created object to state ahead of
+// its other attributes. Should anything after the API call fail, Terraform
+// still tracks the object and a later apply reconciles it rather than
+// creating a duplicate.
+func recordCreatedID(ctx context.Context, state *tfsdk.State, id types.String) {
+	if diags := state.SetAttribute(ctx, path.Root("id"), id); diags.HasError() {
+		tflog.Warn(ctx, "Could not record created ID in state", map[string]any{"id": id.ValueString()})
+	}
+}
+
+// effectiveName returns the name the server gave an object, which differs
+// from the requested one on servers that slugify or suffix names. Responses
+// without a name keep the requested one.
+func effectiveName(response map[string]interface{}, requested types.String) types.String {
+	if name, ok := response["name"].(string); ok && name != "" {
+		return types.StringValue(name)
+	}
+	return requested
+}
+
+// namesFromResponse returns the name and effective name to store after a
+// read. The configured name is only replaced when the server's name moved
+// away from the effective name recorded earlier, so a name the server
+// assigned itself is not reported as drift.
+func namesFromResponse(remote string, name, effective types.String) (types.String, types.String) {
+	if name.IsNull() || effective.IsNull() || remote != effective.ValueString() {
+		name = types.StringValue(remote)
+	}
+	return name, types.StringValue(remote)
+}
+
+// setImportDefaults records the defaults of arguments the API does not
+// store in the state of an imported resource, so the first plan after an
+// import does not show them changing from null.
+func setImportDefaults(ctx context.Context, state *tfsdk.State, defaults map[string]interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for name, value := range defaults {
+		diags.Append(state.SetAttribute(ctx, path.Root(name), value)...)
+	}
+	return diags
+}
+
+// importNamePrefix marks an import ID that names the object to import
+// instead of giving its ID, as in "name:production".
+const importNamePrefix = "name:"
+
+// resolveImportID returns the ID of the object an import ID refers to. An
+// import ID of the form "name:<name>" is looked up in the list at basePath,
+// whose items are listed under key; exactly one of them must have the name.
+// Any other import ID is taken to be the ID itself, and must be a valid ID.
+func resolveImportID(ctx context.Context, client *NixernetesClient, importID, basePath, key, kind string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	name, ok := strings.CutPrefix(importID, importNamePrefix)
+	if !ok {
+		if !isValidID(importID) {
+			diags.AddError("Cannot import "+kind, fmt.Sprintf(
+				"%q is not a valid %s ID. Use the ID of the %s, or %s<name> to import it by name.",
+				importID, kind, kind, importNamePrefix))
+			return "", diags
+		}
+		return importID, diags
+	}
+
+	items, err := listAllPages(ctx, client, basePath, url.Values{"name": {name}}, key)
+	if err != nil {
+		diags.AddError("Error importing "+kind, fmt.Sprintf("Could not look up the %s named %q: %s", kind, name, err))
+		return "", diags
+	}
+
+	// Servers that ignore the name parameter list everything, so match the
+	// name here as well.
+	var ids []string
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		if n, _ := m["name"].(string); n != name {
+			continue
+		}
+		if id, ok := m["id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		diags.AddError("Cannot import "+kind, fmt.Sprintf("No %s is named %q.", kind, name))
+		return "", diags
+	case 1:
+		tflog.Debug(ctx, "Resolved import name", map[string]any{"name": name, "id": ids[0]})
+		return ids[0], diags
+	default:
+		diags.AddError(
+			"Cannot import "+kind,
+			fmt.Sprintf("%d %ss are named %q (IDs %s); import one of them by ID instead.", len(ids), kind, name, strings.Join(ids, ", ")),
+		)
+		return "", diags
+	}
+}
+
+// generationPrivateKey is the private state key holding the generation of
+// an object as last read from the API.
+const generationPrivateKey = "generation"
+
+// generationConflictCode is the error code of a 409 returned when an update
+// carries a generation the object no longer has.
+const generationConflictCode = "generation_conflict"
+
+// privateState is the private state of a resource in a request or response.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// recordGeneration keeps the generation the API reports in response in
+// private state, for the next update to send as a precondition. A server
+// that reports none clears the recorded one, which would be stale.
+func recordGeneration(ctx context.Context, private privateState, response map[string]interface{}) diag.Diagnostics {
+	// Private state is only missing outside of Terraform, e.g. in unit tests.
+	if private == nil || reflect.ValueOf(private).IsNil() {
+		return nil
+	}
+	value := []byte("null")
+	if generation, ok := response["generation"].(float64); ok {
+		value = []byte(strconv.FormatInt(int64(generation), 10))
+	}
+	return private.SetKey(ctx, generationPrivateKey, value)
+}
+
+// addGeneration adds the generation recorded by the last read to an update
+// body, so the server rejects the update if the object changed since.
+func addGeneration(ctx context.Context, private privateState, body map[string]interface{}) diag.Diagnostics {
+	if private == nil || reflect.ValueOf(private).IsNil() {
+		return nil
+	}
+	value, diags := private.GetKey(ctx, generationPrivateKey)
+	if generation, err := strconv.ParseInt(string(value), 10, 64); err == nil {
+		body["generation"] = generation
+	}
+	return diags
+}
+
+// isGenerationConflict reports whether err rejects an update because the
+// object changed since it was read.
+func isGenerationConflict(err error) bool {
+	httpErr, ok := err.(*HTTPError)
+	return ok && httpErr.StatusCode == 409 && httpErr.Code == generationConflictCode
+}
+
+// generationConflictDiagnostic explains an update rejected by
+// isGenerationConflict.
+func generationConflictDiagnostic(kind, name string) diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Resource changed since last read",
+		fmt.Sprintf("The %s %q was changed outside of this Terraform run since it was last read, so the update was not applied to avoid overwriting those changes. "+
+			"Run terraform plan again to refresh it and review the differences before applying.", kind, name),
+	)
+}
+
+// isEnabled reports whether a resource's enabled flag is on. State written
+// before the flag existed holds null, which counts as enabled.
+func isEnabled(enabled types.Bool) bool {
+	return enabled.IsNull() || enabled.IsUnknown() || enabled.ValueBool()
+}
+
+// ========== Module Resource ==========
+
+func NewNixernetesModuleResource() resource.Resource {
+	return &NixernetesModuleResource{}
+}
+
+type NixernetesModuleResource struct {
+	client *NixernetesClient
+}
+
+type NixernetesModuleModel struct {
+	ID             types.String                 `tfsdk:"id"`
+	Name           types.String                 `tfsdk:"name"`
+	Replicas       types.Int64                  `tfsdk:"replicas"`
+	Image          types.String                 `tfsdk:"image"`
+	Namespace      types.String                 `tfsdk:"namespace"`
+	Environment    types.String                 `tfsdk:"environment"`
+	ServiceAccount types.String                 `tfsdk:"service_account"`
+	Platform       types.String                 `tfsdk:"platform"`
+	ProjectID      types.String                 `tfsdk:"project_id"`
+	Volumes        []NixernetesVolumeModel      `tfsdk:"volumes"`
+	VolumeMounts   []NixernetesVolumeMountModel `tfsdk:"volume_mounts"`
+	Autoscaling    *NixernetesAutoscalingModel  `tfsdk:"autoscaling"`
+	Enabled        types.Bool                   `tfsdk:"enabled"`
+	CreatedAt      types.String                 `tfsdk:"created_at"`
+
+	EffectiveName     types.String `tfsdk:"effective_name"`
+	CurrentReplicas   types.Int64  `tfsdk:"current_replicas"`
+	RestartCount      types.Int64  `tfsdk:"restart_count"`
+	LastRestartReason types.String `tfsdk:"last_restart_reason"`
+
+	ReadyTimeout   types.String `tfsdk:"ready_timeout"`
+	OnReadyTimeout types.String `tfsdk:"on_ready_timeout"`
+	Ready          types.Bool   `tfsdk:"ready"`
+
+	NetworkPolicies *NixernetesNetworkPoliciesModel `tfsdk:"network_policies"`
+
+	TerminationGracePeriodSeconds types.Int64 `tfsdk:"termination_grace_period_seconds"`
+	WaitForDeletion               types.Bool  `tfsdk:"wait_for_deletion"`
+
+	RefreshTrigger     types.String `tfsdk:"refresh_trigger"`
+	RefreshAfterUpdate types.Bool   `tfsdk:"refresh_after_update"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+type NixernetesAutoscalingModel struct {
+	MinReplicas          types.Int64 `tfsdk:"min_replicas"`
+	MaxReplicas          types.Int64 `tfsdk:"max_replicas"`
+	TargetCPUUtilization types.Int64 `tfsdk:"target_cpu_utilization"`
+}
+
+// NixernetesNetworkPoliciesModel describes the traffic a module accepts and
+// sends. Traffic no rule allows is denied in that direction once it has rules.
+type NixernetesNetworkPoliciesModel struct {
+	Ingress []NixernetesNetworkPolicyRuleModel `tfsdk:"ingress"`
+	Egress  []NixernetesNetworkPolicyRuleModel `tfsdk:"egress"`
+}
+
+// NixernetesNetworkPolicyRuleModel allows traffic on some ports to or from
+// peers selected by IP range or pod labels.
+type NixernetesNetworkPolicyRuleModel struct {
+	Ports       []types.Int64  `tfsdk:"ports"`
+	Protocol    types.String   `tfsdk:"protocol"`
+	CIDRs       []types.String `tfsdk:"cidrs"`
+	PodSelector types.Map      `tfsdk:"pod_selector"`
+}
+
+// NixernetesVolumeModel describes a volume made available to a module.
+type NixernetesVolumeModel struct {
+	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+	Size types.String `tfsdk:"size"`
+}
+
+// NixernetesVolumeMountModel describes where a volume is mounted in a module's containers.
+type NixernetesVolumeMountModel struct {
+	Name      types.String `tfsdk:"name"`
+	MountPath types.String `tfsdk:"mount_path"`
+	ReadOnly  types.Bool   `tfsdk:"read_only"`
+}
+
+func (r *NixernetesModuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module"
+}
+
+func (r *NixernetesModuleResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a Nixernetes module instance.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Module instance ID",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Module instance name. Changing it replaces the module.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"effective_name": schema.StringAttribute{
+				MarkdownDescription: "Name the server assigned to the module. Usually equal to `name`, but servers that slugify or suffix names may return e.g. `api-7f3a` for `api`.",
+				Computed:            true,
+			},
+			"replicas": schema.Int64Attribute{
+				MarkdownDescription: "Number of replicas. Conflicts with `autoscaling`, since the autoscaler owns the replica count.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					autoscaledReplicas{},
+				},
+			},
+			"image": schema.StringAttribute{
+				MarkdownDescription: "Container image",
+				Required:            true,
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "Kubernetes namespace. Changing it replaces the module.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"service_account": schema.StringAttribute{
+				MarkdownDescription: "Kubernetes service account the module runs as, for RBAC. Must be a DNS-1123 subdomain such as `api-reader`. When unset the server's default service account is used.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Platform the module runs on, `linux/amd64` or `linux/arm64`. The server schedules the module on matching nodes and pulls the matching variant of a multi-platform image; a digest-pinned `image` must be built for this platform. When unset the server picks the platform.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"termination_grace_period_seconds": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Seconds the module's containers are given to shut down, e.g. to drain connections, before they are killed. Between 0 and %d. When unset the server's default is used.", maxTerminationGracePeriodSeconds),
+				Optional:            true,
+				Computed:            true,
+			},
+			"wait_for_deletion": schema.BoolAttribute{
+				MarkdownDescription: "Wait until the module is gone before finishing the delete. The wait allows for `termination_grace_period_seconds` on top of the usual 10 minutes.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"environment": schema.StringAttribute{
+				MarkdownDescription: "Deployment environment (development, staging, production). Images from a local registry such as `localhost:5000` are rejected in production.",
+				Optional:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project the module belongs to. When `namespace` is not set the module uses the project's `default_namespace`. Modules cannot move between projects, so changing this replaces the module.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"volumes": schema.SetNestedAttribute{
+				MarkdownDescription: "Volumes available to the module",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Volume name (DNS label)",
+							Required:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Volume type (emptyDir, pvc, configMap, secret)",
+							Required:            true,
+						},
+						"size": schema.StringAttribute{
+							MarkdownDescription: "Requested size as a Kubernetes quantity, e.g. `10Gi`",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"volume_mounts": schema.SetNestedAttribute{
+				MarkdownDescription: "Mounts of the module's volumes into its containers",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Name of the volume to mount",
+							Required:            true,
+						},
+						"mount_path": schema.StringAttribute{
+							MarkdownDescription: "Absolute path inside the container",
+							Required:            true,
+						},
+						"read_only": schema.BoolAttribute{
+							MarkdownDescription: "Mount the volume read-only",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"autoscaling": schema.SingleNestedAttribute{
+				MarkdownDescription: "Horizontal pod autoscaling settings",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"min_replicas": schema.Int64Attribute{
+						MarkdownDescription: "Minimum number of replicas",
+						Required:            true,
+					},
+					"max_replicas": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of replicas",
+						Required:            true,
+					},
+					"target_cpu_utilization": schema.Int64Attribute{
+						MarkdownDescription: "Target average CPU utilization, as a percentage of requested CPU",
+						Optional:            true,
+					},
+				},
+			},
+			"network_policies": schema.SingleNestedAttribute{
+				MarkdownDescription: "Network policies of the module. Once a direction has rules, traffic in that direction that no rule allows is denied.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"ingress": schema.ListNestedAttribute{
+						MarkdownDescription: "Rules for traffic the module accepts",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"ports": schema.ListAttribute{
+									MarkdownDescription: "Ports the rule allows, from 1 to 65535. All ports when unset.",
+									ElementType:         types.Int64Type,
+									Optional:            true,
+								},
+								"protocol": schema.StringAttribute{
+									MarkdownDescription: "Protocol of `ports`: `TCP` (the default), `UDP` or `SCTP`.",
+									Optional:            true,
+								},
+								"cidrs": schema.ListAttribute{
+									MarkdownDescription: "IP ranges of the peers the rule allows, in CIDR notation such as `10.0.0.0/8`.",
+									ElementType:         types.StringType,
+									Optional:            true,
+								},
+								"pod_selector": schema.MapAttribute{
+									MarkdownDescription: "Labels selecting the pods the rule allows as peers, e.g. `{ app = \"web\" }`.",
+									ElementType:         types.StringType,
+									Optional:            true,
+								},
+							},
+						},
+					},
+					"egress": schema.ListNestedAttribute{
+						MarkdownDescription: "Rules for traffic the module sends",
+						Optional:            true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"ports": schema.ListAttribute{
+									MarkdownDescription: "Ports the rule allows, from 1 to 65535. All ports when unset.",
+									ElementType:         types.Int64Type,
+									Optional:            true,
+								},
+								"protocol": schema.StringAttribute{
+									MarkdownDescription: "Protocol of `ports`: `TCP` (the default), `UDP` or `SCTP`.",
+									Optional:            true,
+								},
+								"cidrs": schema.ListAttribute{
+									MarkdownDescription: "IP ranges of the peers the rule allows, in CIDR notation such as `10.0.0.0/8`.",
+									ElementType:         types.StringType,
+									Optional:            true,
+								},
+								"pod_selector": schema.MapAttribute{
+									MarkdownDescription: "Labels selecting the pods the rule allows as peers, e.g. `{ app = \"web\" }`.",
+									ElementType:         types.StringType,
+									Optional:            true,
+								},
+							},
+						},
+					},
+				},
+			},
+			"current_replicas": schema.Int64Attribute{
+				MarkdownDescription: "Number of replicas currently running",
+				Computed:            true,
+			},
+			"restart_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of times the module's containers have restarted, as reported by the module status. A growing count points to a crash loop. Null until the status is first read, or when the server does not report status.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_restart_reason": schema.StringAttribute{
+				MarkdownDescription: "Reason for the most recent restart, e.g. `OOMKilled` or `Error`. Null when the module has not restarted or the server does not report it.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ready_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait after creation for the module to become ready, as a duration such as `5m`. When unset the provider does not wait.",
+				Optional:            true,
+			},
+			"on_ready_timeout": schema.StringAttribute{
+				MarkdownDescription: "What to do when the module is not ready within `ready_timeout`: `fail` (default) errors and taints the module, `taint` succeeds with a warning and replaces the module on the next apply, `continue` succeeds with a warning and keeps the module.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(onReadyTimeoutFail),
+			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the module became ready within `ready_timeout`. Null when the provider did not wait.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"refresh_trigger": schema.StringAttribute{
+				MarkdownDescription: "Arbitrary value; changing it re-reads `current_replicas`, the restart counters and `ready` from the API without changing the module, e.g. `timestamp()` or a counter.",
+				Optional:            true,
+			},
+			"refresh_after_update": schema.BoolAttribute{
+				MarkdownDescription: "Re-read the module after each update, so state reflects what the server stored even when the update response leaves fields out. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the module should exist. When false the resource stays in configuration but nothing is created, and an existing module is deleted.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Creation timestamp",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
+	}
+}
+
+func (r *NixernetesModuleResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	r.client = client
+}
+
+func (r *NixernetesModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NixernetesModuleModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateModuleModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(moduleFieldPaths)...)
+		return
+	}
+
+	if !isEnabled(plan.Enabled) {
+		tflog.Debug(ctx, "Module disabled, skipping creation", map[string]any{"name": plan.Name.ValueString()})
+		plan.clearRemote()
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if err := r.createRemote(ctx, &plan, &resp.State); err != nil {
+		if isQuotaExceeded(err) {
+			httpErr := err.(*HTTPError)
+			resp.Diagnostics.AddError(
+				"Resource quota exceeded",
+				fmt.Sprintf("Module %q would exceed the resource quota of namespace %q: %s. Reduce the module's replicas or raise the namespace's nixernetes_resource_quota limits.",
+					plan.Name.ValueString(), plan.Namespace.ValueString(), httpErr.Message+httpErr.requestIDSuffix()),
+			)
+			return
+		}
+		if isPlatformMismatch(err) {
+			resp.Diagnostics.Append(platformMismatchDiagnostic(&plan, err.(*HTTPError)))
+			return
+		}
+		if diags := fieldErrorDiagnostics(err, moduleFieldPaths); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		resp.Diagnostics.AddError("Error creating module", "Could not create module: "+err.Error())
+		return
+	}
+
+	// The module exists from here on, so state is saved even if waiting
+	// fails; Terraform taints a resource whose create errors with state set.
+	resp.Diagnostics.Append(r.waitForReady(ctx, &plan)...)
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NixernetesModuleResource) createRemote(ctx context.Context, plan *NixernetesModuleModel, state *tfsdk.State) error {
+	if plan.Namespace.IsUnknown() && !plan.ProjectID.IsNull() && !plan.ProjectID.IsUnknown() {
+		namespace, err := r.projectDefaultNamespace(ctx, plan.ProjectID.ValueString())
+		if err != nil {
+			return err
+		}
+		if namespace != "" {
+			plan.Namespace = types.StringValue(namespace)
+		}
+	}
+
+	// The autoscaler owns the replica count of an autoscaled module.
+	if plan.Replicas.IsUnknown() && plan.Autoscaling == nil && r.client.DefaultReplicas != nil {
+		plan.Replicas = types.Int64Value(*r.client.DefaultReplicas)
+	}
+
+	body := moduleRequestBody(plan)
+
+	response, err := r.client.Post(ctx, r.client.modulesPath(), body)
+	if err != nil {
+		return err
+	}
+
+	id, ok := response["id"].(string)
+	if !ok {
+		return fmt.Errorf("create response did not include an id")
+	}
+	plan.ID = types.StringValue(id)
+	recordCreatedID(ctx, state, plan.ID)
+	fields, err := requireStrings(response, "created_at")
+	if err != nil {
+		return err
+	}
+	plan.EffectiveName = effectiveName(response, plan.Name)
+	plan.CreatedAt = types.StringValue(fields["created_at"])
+	if plan.Replicas.IsUnknown() {
+		plan.Replicas = types.Int64Null()
+		if replicas, ok := response["replicas"].(float64); ok {
+			plan.Replicas = types.Int64Value(int64(replicas))
+		}
+	}
+	plan.CurrentReplicas = currentReplicasFromResponse(response, plan.Replicas)
+	plan.ServiceAccount = serviceAccountFromResponse(response, plan.ServiceAccount)
+	plan.Platform = platformFromResponse(response, plan.Platform)
+	plan.TerminationGracePeriodSeconds = gracePeriodFromResponse(response, plan.TerminationGracePeriodSeconds)
+	plan.RestartCount = types.Int64Null()
+	plan.LastRestartReason = types.StringNull()
+
+	return nil
+}
+
+// projectDefaultNamespace looks up the default namespace of the project a
+// module belongs to, returning "" when the project does not set one.
+func (r *NixernetesModuleResource) projectDefaultNamespace(ctx context.Context, projectID string) (string, error) {
+	response, err := r.client.Get(ctx, r.client.projectsPath()+"/"+url.PathEscape(projectID))
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+		return "", fmt.Errorf("project %q does not exist", projectID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not look up project %q: %w", projectID, err)
+	}
+
+	namespace, _ := response["default_namespace"].(string)
+	tflog.Debug(ctx, "Resolved project default namespace", map[string]any{
+		"project_id": projectID,
+		"namespace":  namespace,
+	})
+	return namespace, nil
+}
+
+// on_ready_timeout values.
+const (
+	onReadyTimeoutFail     = "fail"
+	onReadyTimeoutTaint    = "taint"
+	onReadyTimeoutContinue = "continue"
+)
+
+// waitForReady waits up to ready_timeout for a newly created module to become
+// ready, recording the outcome in plan.Ready and reporting a failure as
+// on_ready_timeout directs.
+func (r *NixernetesModuleResource) waitForReady(ctx context.Context, plan *NixernetesModuleModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if plan.ReadyTimeout.IsNull() {
+		plan.Ready = types.BoolNull()
+		return diags
+	}
+
+	timeout, err := time.ParseDuration(plan.ReadyTimeout.ValueString())
+	if err != nil {
+		plan.Ready = types.BoolNull()
+		diags.AddAttributeError(path.Root("ready_timeout"), "Invalid ready timeout", err.Error())
+		return diags
+	}
+
+	err = r.client.WaitForModuleReady(ctx, plan.ID.ValueString(), timeout)
+	plan.Ready = types.BoolValue(err == nil)
+	if err == nil {
+		return diags
+	}
+
+	detail := fmt.Sprintf("Module %q was created but did not become ready: %s.", plan.Name.ValueString(), err)
+	switch plan.OnReadyTimeout.ValueString() {
+	case onReadyTimeoutContinue:
+		diags.AddWarning("Module not ready", detail+" Keeping it as on_ready_timeout is \"continue\".")
+	case onReadyTimeoutTaint:
+		diags.AddWarning("Module not ready", detail+" It will be replaced on the next apply as on_ready_timeout is \"taint\".")
+	default:
+		diags.AddError("Module not ready", detail+" It has been marked tainted and will be replaced on the next apply.")
+	}
+	return diags
+}
+
+// ModifyPlan warns about the impact of destroying a module, and replaces a
+// module that did not become ready when on_ready_timeout is "taint".
+func (r *NixernetesModuleResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		var state NixernetesModuleModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(r.destroyImpact(ctx, &state)...)
+		return
+	}
+
+	var state, plan NixernetesModuleModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A new refresh_trigger re-reads the computed status without changing the spec.
+	if !plan.RefreshTrigger.Equal(state.RefreshTrigger) {
+		tflog.Debug(ctx, "Refresh trigger changed, planning status refresh", map[string]any{"id": state.ID.ValueString()})
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("current_replicas"), types.Int64Unknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("restart_count"), types.Int64Unknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("last_restart_reason"), types.StringUnknown())...)
+		if !state.Ready.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ready"), types.BoolUnknown())...)
+		}
+	}
+
+	if state.Ready.IsNull() || state.Ready.ValueBool() || plan.OnReadyTimeout.ValueString() != onReadyTimeoutTaint {
+		return
+	}
+
+	tflog.Debug(ctx, "Module did not become ready, planning replacement", map[string]any{"id": state.ID.ValueString()})
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("ready"), types.BoolUnknown())...)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("ready"))
+}
+
+// destroyImpact returns a warning describing what destroying the module in
+// state takes down. It prefers the API's impact endpoint and falls back to
+// the module status; when neither is available no warning is returned.
+func (r *NixernetesModuleResource) destroyImpact(ctx context.Context, state *NixernetesModuleModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.client == nil || state.ID.IsNull() {
+		return diags
+	}
+
+	impact, err := r.client.GetModuleImpact(ctx, state.ID.ValueString())
+	if err != nil {
+		tflog.Debug(ctx, "Module impact unavailable, falling back to status", map[string]any{
+			"id":    state.ID.ValueString(),
+			"error": err.Error(),
+		})
+		status, statusErr := r.client.GetModuleStatus(ctx, state.ID.ValueString())
+		if statusErr != nil {
+			tflog.Debug(ctx, "Module status unavailable, skipping destroy impact", map[string]any{
+				"id":    state.ID.ValueString(),
+				"error": statusErr.Error(),
+			})
+			return diags
+		}
+		impact = &ModuleImpact{ReadyReplicas: status.ReadyReplicas}
+	}
+
+	if summary := impactSummary(impact); summary != "" {
+		diags.AddWarning(
+			"Module will be destroyed",
+			fmt.Sprintf("Destroying module %q (%s) takes down %s.", state.Name.ValueString(), state.ID.ValueString(), summary),
+		)
+	}
+	return diags
+}
+
+// impactSummary describes a module's impact in a sentence fragment such as
+// "3 ready replicas serving 120 active connections", or returns "" when
+// nothing is affected.
+func impactSummary(impact *ModuleImpact) string {
+	var parts []string
+	if impact.ReadyReplicas > 0 {
+		part := fmt.Sprintf("%d ready replica(s)", impact.ReadyReplicas)
+		if impact.ActiveConnections > 0 {
+			part += fmt.Sprintf(" serving %d active connection(s)", impact.ActiveConnections)
+		}
+		parts = append(parts, part)
+	} else if impact.ActiveConnections > 0 {
+		parts = append(parts, fmt.Sprintf("%d active connection(s)", impact.ActiveConnections))
+	}
+	if impact.RequestsPerMinute > 0 {
+		parts = append(parts, fmt.Sprintf("traffic of %d request(s) per minute", impact.RequestsPerMinute))
+	}
+	if len(impact.Dependents) > 0 {
+		parts = append(parts, "the dependents "+strings.Join(impact.Dependents, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ValidateConfig checks image and replicas, and rejects a module that sets
+// both a static replica count and autoscaling, which contradict each other.
+// It runs without a configured client, so `terraform validate` catches these
+// offline.
+func (r *NixernetesModuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var image types.String
+	var replicas types.Int64
+	var autoscaling types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("image"), &image)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("replicas"), &replicas)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("autoscaling"), &autoscaling)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !image.IsNull() && !image.IsUnknown() {
+		if c, ok := shellMetacharacter(image.ValueString()); ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("image"),
+				"Invalid image",
+				fmt.Sprintf("Image %q contains the shell metacharacter %q, which is never part of an image reference", image.ValueString(), c),
+			)
+		}
+	}
+
+	if replicas.IsNull() || replicas.IsUnknown() {
+		return
+	}
+
+	if n := replicas.ValueInt64(); n < 0 || n > maxModuleReplicas {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("replicas"),
+			"Invalid replicas",
+			fmt.Sprintf("replicas must be between 0 and %d, got: %d", maxModuleReplicas, n),
+		)
+	}
+
+	if autoscaling.IsNull() || autoscaling.IsUnknown() {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root("replicas"),
+		"Conflicting replica settings",
+		"replicas and autoscaling cannot both be set: the autoscaler owns the replica count. "+
+			"Remove replicas to autoscale between autoscaling.min_replicas and autoscaling.max_replicas, or remove autoscaling to run a fixed number of replicas.",
+	)
+}
+
+// moduleSpecUnchanged reports whether plan would send the same spec to the API
+// as state, as when only refresh_trigger or readiness settings changed.
+func moduleSpecUnchanged(plan, state NixernetesModuleModel) bool {
+	known := knownModuleSpec(plan, state)
+	return reflect.DeepEqual(moduleRequestBody(&known), moduleRequestBody(&state))
+}
+
+// knownModuleSpec returns plan with the computed values it leaves unknown
+// taken from state, which is the spec the module keeps after an update.
+func knownModuleSpec(plan, state NixernetesModuleModel) NixernetesModuleModel {
+	if plan.Replicas.IsUnknown() {
+		plan.Replicas = state.Replicas
+	}
+	if plan.Namespace.IsUnknown() {
+		plan.Namespace = state.Namespace
+	}
+	if plan.ServiceAccount.IsUnknown() {
+		plan.ServiceAccount = state.ServiceAccount
+	}
+	if plan.Platform.IsUnknown() {
+		plan.Platform = state.Platform
+	}
+	if plan.TerminationGracePeriodSeconds.IsUnknown() {
+		plan.TerminationGracePeriodSeconds = state.TerminationGracePeriodSeconds
+	}
+	return plan
+}
+
+// refreshStatus re-reads the live replica count, the restart counters and,
+// when the module was waited on, its readiness, keeping the rest of the plan
+// as it is.
+func (r *NixernetesModuleResource) refreshStatus(ctx context.Context, plan, state *NixernetesModuleModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	*plan = knownModuleSpec(*plan, *state)
+	plan.Ready = state.Ready
+
+	response, err := r.client.Get(ctx, r.client.modulesPath()+"/"+url.PathEscape(plan.ID.ValueString()))
+	if err != nil {
+		diags.AddError("Error refreshing module", "Could not read module: "+err.Error())
+		return diags
+	}
+	plan.CurrentReplicas = currentReplicasFromResponse(response, state.CurrentReplicas)
+
+	// Servers without a status endpoint only matter when readiness is tracked.
+	status, err := r.client.GetModuleStatus(ctx, plan.ID.ValueString())
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 && state.Ready.IsNull() {
+		err = nil
+	}
+	if err != nil {
+		diags.AddError("Error refreshing module", "Could not read module status: "+err.Error())
+		return diags
+	}
+	plan.RestartCount, plan.LastRestartReason = restartsFromStatus(status)
+	if !state.Ready.IsNull() {
+		plan.Ready = types.BoolValue(status.Ready())
+	}
+
+	tflog.Debug(ctx, "Refreshed module status", map[string]any{
+		"id":               plan.ID.ValueString(),
+		"current_replicas": plan.CurrentReplicas.ValueInt64(),
+	})
+	return diags
+}
+
+// serviceAccountFromResponse returns the service account reported by the API,
+// falling back to fallback for servers that do not report one.
+func serviceAccountFromResponse(response map[string]interface{}, fallback types.String) types.String {
+	if serviceAccount, ok := response["service_account"].(string); ok && serviceAccount != "" {
+		return types.StringValue(serviceAccount)
+	}
+	if fallback.IsUnknown() {
+		return types.StringNull()
+	}
+	return fallback
+}
+
+// platformFromResponse returns the platform reported by the API, falling
+// back to fallback for servers that do not report one.
+func platformFromResponse(response map[string]interface{}, fallback types.String) types.String {
+	if platform, ok := response["platform"].(string); ok && platform != "" {
+		return types.StringValue(platform)
+	}
+	if fallback.IsUnknown() {
+		return types.StringNull()
+	}
+	return fallback
+}
+
+// gracePeriodFromResponse returns the termination grace period reported by
+// the API, falling back to fallback for servers that do not report one.
+func gracePeriodFromResponse(response map[string]interface{}, fallback types.Int64) types.Int64 {
+	if seconds, ok := response["termination_grace_period_seconds"].(float64); ok {
+		return types.Int64Value(int64(seconds))
+	}
+	if fallback.IsUnknown() {
+		return types.Int64Null()
+	}
+	return fallback
+}
+
+// platformMismatchDiagnostic explains an API rejection of a digest-pinned
+// image that was not built for the module's platform.
+func platformMismatchDiagnostic(plan *NixernetesModuleModel, err *HTTPError) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		path.Root("platform"),
+		"Image digest does not match platform",
+		fmt.Sprintf("Image %q of module %q is pinned to a digest that is not built for platform %q: %s. "+
+			"Pin the digest of the %s variant from the image's index, or pin the multi-platform index digest itself.",
+			plan.Image.ValueString(), plan.Name.ValueString(), plan.Platform.ValueString(), err.Message+err.requestIDSuffix(), plan.Platform.ValueString()),
+	)
+}
+
+// restartsFromStatus returns the restart_count and last_restart_reason
+// attributes for a module status, both null when there is no status.
+func restartsFromStatus(status *ModuleStatus) (types.Int64, types.String) {
+	if status == nil {
+		return types.Int64Null(), types.StringNull()
+	}
+	reason := types.StringNull()
+	if status.LastRestartReason != "" {
+		reason = types.StringValue(status.LastRestartReason)
+	}
+	return types.Int64Value(status.RestartCount), reason
+}
+
+// currentReplicasFromResponse returns the live replica count reported by the
+// API, falling back to fallback when the response does not include it.
+func currentReplicasFromResponse(response map[string]interface{}, fallback types.Int64) types.Int64 {
+	if current, ok := response["current_replicas"].(float64); ok {
+		return types.Int64Value(int64(current))
+	}
+	if fallback.IsUnknown() {
+		return types.Int64Null()
+	}
+	return fallback
+}
+
+// autoscaledReplicas keeps replicas at its prior state value while the module
+// is autoscaled, so changes made by the autoscaler or to the configured
+// replica count do not produce a diff.
+type autoscaledReplicas struct{}
+
+func (m autoscaledReplicas) Description(_ context.Context) string {
+	return "Suppresses replica changes while autoscaling is configured."
+}
+
+func (m autoscaledReplicas) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m autoscaledReplicas) PlanModifyInt64(ctx context.Context, req planmodifier.Int64Request, resp *planmodifier.Int64Response) {
+	if req.StateValue.IsNull() {
+		return
+	}
+
+	var autoscaling types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("autoscaling"), &autoscaling)...)
+	if resp.Diagnostics.HasError() || autoscaling.IsNull() {
+		return
+	}
+
+	resp.PlanValue = req.StateValue
+}
+
+// moduleRequestBody builds the create/update request body for a module.
+func moduleRequestBody(plan *NixernetesModuleModel) map[string]interface{} {
+	body := map[string]interface{}{
+		"name":  plan.Name.ValueString(),
+		"image": plan.Image.ValueString(),
+	}
+
+	// Unset optional fields are left out so the server applies its defaults
+	if !plan.Replicas.IsNull() && !plan.Replicas.IsUnknown() {
+		body["replicas"] = plan.Replicas.ValueInt64()
+	}
+
+	if !plan.Namespace.IsNull() && !plan.Namespace.IsUnknown() {
+		body["namespace"] = plan.Namespace.ValueString()
+	}
+
+	if !plan.Environment.IsNull() && !plan.Environment.IsUnknown() {
+		body["environment"] = normalizeEnvironment(plan.Environment.ValueString())
+	}
+
+	if !plan.ProjectID.IsNull() && !plan.ProjectID.IsUnknown() {
+		body["project_id"] = plan.ProjectID.ValueString()
+	}
+
+	if !plan.ServiceAccount.IsNull() && !plan.ServiceAccount.IsUnknown() {
+		body["service_account"] = plan.ServiceAccount.ValueString()
+	}
+
+	if !plan.Platform.IsNull() && !plan.Platform.IsUnknown() {
+		body["platform"] = plan.Platform.ValueString()
+	}
+
+	if !plan.TerminationGracePeriodSeconds.IsNull() && !plan.TerminationGracePeriodSeconds.IsUnknown() {
+		body["termination_grace_period_seconds"] = plan.TerminationGracePeriodSeconds.ValueInt64()
+	}
+
+	if plan.Volumes != nil {
+		volumes := make([]map[string]interface{}, 0, len(plan.Volumes))
+		for _, v := range plan.Volumes {
+			volume := map[string]interface{}{
+				"name": v.Name.ValueString(),
+				"type": v.Type.ValueString(),
+			}
+			if !v.Size.IsNull() {
+				volume["size"] = v.Size.ValueString()
+			}
+			volumes = append(volumes, volume)
+		}
+		body["volumes"] = volumes
+	}
+
+	if plan.Autoscaling != nil {
+		autoscaling := map[string]interface{}{
+			"minReplicas": plan.Autoscaling.MinReplicas.ValueInt64(),
+			"maxReplicas": plan.Autoscaling.MaxReplicas.ValueInt64(),
+		}
+		if !plan.Autoscaling.TargetCPUUtilization.IsNull() {
+			autoscaling["targetCPUUtilizationPercentage"] = plan.Autoscaling.TargetCPUUtilization.ValueInt64()
+		}
+		body["autoscaling"] = autoscaling
+	}
+
+	if plan.VolumeMounts != nil {
+		mounts := make([]map[string]interface{}, 0, len(plan.VolumeMounts))
+		for _, m := range plan.VolumeMounts {
+			mounts = append(mounts, map[string]interface{}{
+				"name":      m.Name.ValueString(),
+				"mountPath": m.MountPath.ValueString(),
+				"readOnly":  m.ReadOnly.ValueBool(),
+			})
+		}
+		body["volumeMounts"] = mounts
+	}
+
+	if plan.NetworkPolicies != nil {
+		body["networkPolicies"] = map[string]interface{}{
+			"ingress": networkPolicyRulesBody(plan.NetworkPolicies.Ingress),
+			"egress":  networkPolicyRulesBody(plan.NetworkPolicies.Egress),
+		}
+	}
+
+	return body
+}
+
+// networkPolicyRulesBody converts network policy rules to their API form.
+// Unset fields are left out so the server applies its defaults.
+func networkPolicyRulesBody(rules []NixernetesNetworkPolicyRuleModel) []map[string]interface{} {
+	body := make([]map[string]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		r := map[string]interface{}{}
+		if rule.Ports != nil {
+			ports := make([]int64, 0, len(rule.Ports))
+			for _, port := range rule.Ports {
+				ports = append(ports, port.ValueInt64())
+			}
+			r["ports"] = ports
+		}
+		if !rule.Protocol.IsNull() {
+			r["protocol"] = rule.Protocol.ValueString()
+		}
+		if rule.CIDRs != nil {
+			cidrs := make([]string, 0, len(rule.CIDRs))
+			for _, cidr := range rule.CIDRs {
+				cidrs = append(cidrs, cidr.ValueString())
+			}
+			r["cidrs"] = cidrs
+		}
+		if !rule.PodSelector.IsNull() && !rule.PodSelector.IsUnknown() {
+			selector := make(map[string]string, len(rule.PodSelector.Elements()))
+			for key, value := range rule.PodSelector.Elements() {
+				if s, ok := value.(types.String); ok {
+					selector[key] = s.ValueString()
+				}
+			}
+			r["podSelector"] = selector
+		}
+		body = append(body, r)
+	}
+	return body
+}
+
+// networkPoliciesFromResponse converts the API's network policies into the
+// resource model. Rules are matched to the prior ones by position, so a
+// default the server fills in, such as the TCP protocol, is not reported as
+// drift where the configuration left it unset.
+func networkPoliciesFromResponse(raw interface{}, prior *NixernetesNetworkPoliciesModel) *NixernetesNetworkPoliciesModel {
+	p, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var priorIngress, priorEgress []NixernetesNetworkPolicyRuleModel
+	if prior != nil {
+		priorIngress, priorEgress = prior.Ingress, prior.Egress
+	}
+	return &NixernetesNetworkPoliciesModel{
+		Ingress: networkPolicyRulesFromResponse(p["ingress"], priorIngress),
+		Egress:  networkPolicyRulesFromResponse(p["egress"], priorEgress),
+	}
+}
+
+func networkPolicyRulesFromResponse(raw interface{}, prior []NixernetesNetworkPolicyRuleModel) []NixernetesNetworkPolicyRuleModel {
+	items, _ := raw.([]interface{})
+	if len(items) == 0 {
+		if prior != nil {
+			return []NixernetesNetworkPolicyRuleModel{}
+		}
+		return nil
+	}
+
+	rules := make([]NixernetesNetworkPolicyRuleModel, 0, len(items))
+	for i, item := range items {
+		r, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		priorRule := NixernetesNetworkPolicyRuleModel{Protocol: types.StringNull(), PodSelector: types.MapNull(types.StringType)}
+		if i < len(prior) {
+			priorRule = prior[i]
+		}
+
+		rule := NixernetesNetworkPolicyRuleModel{
+			Protocol:    types.StringNull(),
+			PodSelector: stringMapFromResponse(r["podSelector"], priorRule.PodSelector),
+		}
+		if ports, _ := r["ports"].([]interface{}); len(ports) > 0 || priorRule.Ports != nil {
+			rule.Ports = make([]types.Int64, 0, len(ports))
+			for _, port := range ports {
+				if v, ok := port.(float64); ok {
+					rule.Ports = append(rule.Ports, types.Int64Value(int64(v)))
+				}
+			}
+		}
+		if protocol, ok := r["protocol"].(string); ok && protocol != "" {
+			if !(priorRule.Protocol.IsNull() && protocol == defaultNetworkPolicyProtocol) {
+				rule.Protocol = types.StringValue(protocol)
+			}
+		}
+		if cidrs, _ := r["cidrs"].([]interface{}); len(cidrs) > 0 || priorRule.CIDRs != nil {
+			rule.CIDRs = make([]types.String, 0, len(cidrs))
+			for _, cidr := range cidrs {
+				rule.CIDRs = append(rule.CIDRs, types.StringValue(fmt.Sprint(cidr)))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// volumesFromResponse converts the API volume list into the resource model.
+// An empty list keeps the attribute null unless it was configured, so an
+// omitted attribute does not diff against the server's empty list.
+func volumesFromResponse(raw interface{}, prior []NixernetesVolumeModel) []NixernetesVolumeModel {
+	items, _ := raw.([]interface{})
+	if len(items) == 0 {
+		if prior != nil {
+			return []NixernetesVolumeModel{}
+		}
+		return nil
+	}
+
+	volumes := make([]NixernetesVolumeModel, 0, len(items))
+	for _, item := range items {
+		v, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		volume := NixernetesVolumeModel{
+			Name: types.StringValue(fmt.Sprint(v["name"])),
+			Type: types.StringValue(fmt.Sprint(v["type"])),
+			Size: types.StringNull(),
+		}
+		if size, ok := v["size"].(string); ok && size != "" {
+			volume.Size = types.StringValue(size)
+		}
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+// autoscalingFromResponse converts the API's autoscaling settings into the
+// model, keeping target_cpu_utilization null when it was not configured.
+func autoscalingFromResponse(raw interface{}, prior *NixernetesAutoscalingModel) *NixernetesAutoscalingModel {
+	a, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	autoscaling := &NixernetesAutoscalingModel{
+		MinReplicas:          types.Int64Null(),
+		MaxReplicas:          types.Int64Null(),
+		TargetCPUUtilization: types.Int64Null(),
+	}
+	if v, ok := a["minReplicas"].(float64); ok {
+		autoscaling.MinReplicas = types.Int64Value(int64(v))
+	}
+	if v, ok := a["maxReplicas"].(float64); ok {
+		autoscaling.MaxReplicas = types.Int64Value(int64(v))
+	}
+	if v, ok := a["targetCPUUtilizationPercentage"].(float64); ok {
+		if prior == nil || !prior.TargetCPUUtilization.IsNull() {
+			autoscaling.TargetCPUUtilization = types.Int64Value(int64(v))
+		}
+	}
+	return autoscaling
+}
+
+// volumeMountsFromResponse converts the API volume mount list into the resource model.
+func volumeMountsFromResponse(raw interface{}, prior []NixernetesVolumeMountModel) []NixernetesVolumeMountModel {
+	items, _ := raw.([]interface{})
+	if len(items) == 0 {
+		if prior != nil {
+			return []NixernetesVolumeMountModel{}
+		}
+		return nil
+	}
+
+	// read_only is optional, so keep it null when the configuration left it unset
+	// and the server reports the default.
+	priorReadOnly := map[string]types.Bool{}
+	for _, m := range prior {
+		priorReadOnly[m.MountPath.ValueString()] = m.ReadOnly
+	}
+
+	mounts := make([]NixernetesVolumeMountModel, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mount := NixernetesVolumeMountModel{
+			Name:      types.StringValue(fmt.Sprint(m["name"])),
+			MountPath: types.StringValue(fmt.Sprint(m["mountPath"])),
+		}
+		readOnly, _ := m["readOnly"].(bool)
+		if p, ok := priorReadOnly[mount.MountPath.ValueString()]; ok && p.IsNull() && !readOnly {
+			mount.ReadOnly = types.BoolNull()
+		} else {
+			mount.ReadOnly = types.BoolValue(readOnly)
+		}
+		mounts = append(mounts, mount)
+	}
+	return mounts
+}
+
+func (m *NixernetesModuleModel) clearRemote() {
+	m.ID = types.StringNull()
+	m.EffectiveName = types.StringNull()
+	m.CreatedAt = types.StringNull()
+	m.CurrentReplicas = types.Int64Null()
+	m.RestartCount = types.Int64Null()
+	m.LastRestartReason = types.StringNull()
+	m.Ready = types.BoolNull()
+	if m.Replicas.IsUnknown() {
+		m.Replicas = types.Int64Null()
+	}
+	if m.Namespace.IsUnknown() {
+		m.Namespace = types.StringNull()
+	}
+	if m.ServiceAccount.IsUnknown() {
+		m.ServiceAccount = types.StringNull()
+	}
+	if m.Platform.IsUnknown() {
+		m.Platform = types.StringNull()
+	}
+	if m.TerminationGracePeriodSeconds.IsUnknown() {
+		m.TerminationGracePeriodSeconds = types.Int64Null()
+	}
+}
+
+// ImportState imports a module by ID.
+func (r *NixernetesModuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, diags := resolveImportID(ctx, r.client, req.ID, r.client.modulesPath(), "modules", "module")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(setImportDefaults(ctx, &resp.State, map[string]interface{}{
+		"enabled":              true,
+		"wait_for_deletion":    false,
+		"on_ready_timeout":     onReadyTimeoutFail,
+		"refresh_after_update": true,
+	})...)
+}
+
+func (r *NixernetesModuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NixernetesModuleModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !isEnabled(state.Enabled) || state.ID.IsNull() {
+		return
+	}
+
+	response, err := r.readRemote(ctx, &state)
+	if err != nil {
+		// Deleted outside Terraform; removing it plans a recreate.
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading module", "Could not read module: "+err.Error())
+		return
+	}
+	resp.Diagnostics.Append(recordGeneration(ctx, resp.Private, response)...)
+
+	// Status is best effort: restart counters are diagnostics, not part of the module.
+	status, err := r.client.GetModuleStatus(ctx, state.ID.ValueString())
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+		status, err = nil, nil
+	}
+	if err == nil {
+		state.RestartCount, state.LastRestartReason = restartsFromStatus(status)
+	} else {
+		resp.Diagnostics.AddWarning(
+			"Could not read module status",
+			"restart_count and last_restart_reason keep their previous values until the next refresh: "+err.Error(),
+		)
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// readRemote refreshes the model from the module stored by the API and
+// returns the API's response.
+func (r *NixernetesModuleResource) readRemote(ctx context.Context, m *NixernetesModuleModel) (map[string]interface{}, error) {
+	module, err := doRequestInto[moduleResponse](ctx, r.client, "GET", r.client.modulesPath()+"/"+url.PathEscape(m.ID.ValueString()), nil)
+	if err != nil {
+		return nil, err
+	}
+	if missing := module.missing(); len(missing) > 0 {
+		return nil, fmt.Errorf("response is missing the field(s) %s", strings.Join(missing, ", "))
+	}
+	response := module.Raw
+
+	replicas := types.Int64Value(*module.Replicas)
+	// While its project is paused the module runs no replicas; the count it
+	// is restored to on resume is what the configuration manages.
+	if module.PausedReplicas != nil {
+		replicas = types.Int64Value(*module.PausedReplicas)
+	}
+
+	m.Name, m.EffectiveName = namesFromResponse(*module.Name, m.Name, m.EffectiveName)
+	m.Image = types.StringValue(*module.Image)
+	m.Namespace = types.StringValue(*module.Namespace)
+	m.Volumes = volumesFromResponse(response["volumes"], m.Volumes)
+	m.VolumeMounts = volumeMountsFromResponse(response["volumeMounts"], m.VolumeMounts)
+	m.Autoscaling = autoscalingFromResponse(response["autoscaling"], m.Autoscaling)
+	m.NetworkPolicies = networkPoliciesFromResponse(response["networkPolicies"], m.NetworkPolicies)
+	m.CurrentReplicas = currentReplicasFromResponse(response, replicas)
+	m.ServiceAccount = serviceAccountFromResponse(response, m.ServiceAccount)
+	m.Platform = platformFromResponse(response, m.Platform)
+	m.TerminationGracePeriodSeconds = gracePeriodFromResponse(response, m.TerminationGracePeriodSeconds)
+	// Needed after an import; created_at never changes otherwise.
+	if module.CreatedAt != nil {
+		m.CreatedAt = types.StringValue(*module.CreatedAt)
+	}
+
+	// Servers without project support omit project_id; keep the configured value.
+	if module.ProjectID != nil {
+		m.ProjectID = types.StringNull()
+		if *module.ProjectID != "" {
+			m.ProjectID = types.StringValue(*module.ProjectID)
+		}
+	}
+
+	// The autoscaler owns the live count; keep the configured value.
+	if m.Autoscaling == nil || m.Replicas.IsNull() || m.Replicas.IsUnknown() {
+		m.Replicas = replicas
+	}
+
+	return response, nil
+}
+
+func (r *NixernetesModuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state NixernetesModuleModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateModuleModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(moduleFieldPaths)...)
+		return
+	}
+
+	switch {
+	case !isEnabled(plan.Enabled):
+		if !state.ID.IsNull() {
+			err := r.client.Delete(ctx, r.client.modulesPath()+"/"+url.PathEscape(state.ID.ValueString()))
+			if err != nil {
+				resp.Diagnostics.AddError("Error disabling module", "Could not delete module: "+err.Error())
+				return
+			}
+		}
+		plan.clearRemote()
+
+	case state.ID.IsNull():
+		if err := r.createRemote(ctx, &plan, &resp.State); err != nil {
+			if diags := fieldErrorDiagnostics(err, moduleFieldPaths); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+			resp.Diagnostics.AddError("Error enabling module", "Could not create module: "+err.Error())
+			return
+		}
+		resp.Diagnostics.Append(r.waitForReady(ctx, &plan)...)
+
+	case moduleSpecUnchanged(plan, state):
+		// Nothing to send; re-read the status, e.g. for a new refresh_trigger.
+		plan.ID = state.ID
+		plan.EffectiveName = state.EffectiveName
+		plan.CreatedAt = state.CreatedAt
+		resp.Diagnostics.Append(r.refreshStatus(ctx, &plan, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+	default:
+		plan.ID = state.ID
+		plan.CreatedAt = state.CreatedAt
+		plan.Ready = state.Ready
+
+		known := knownModuleSpec(plan, state)
+		body := moduleRequestBody(&known)
+		resp.Diagnostics.Append(addGeneration(ctx, req.Private, body)...)
+
+		response, err := r.client.UpdateChanges(ctx, r.client.modulesPath()+"/"+url.PathEscape(plan.ID.ValueString()), moduleRequestBody(&state), body)
+		if isGenerationConflict(err) {
+			resp.Diagnostics.Append(generationConflictDiagnostic("module", plan.Name.ValueString()))
+			return
+		}
+		if isPlatformMismatch(err) {
+			resp.Diagnostics.Append(platformMismatchDiagnostic(&plan, err.(*HTTPError)))
+			return
+		}
+		if diags := fieldErrorDiagnostics(err, moduleFieldPaths); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Error updating module", "Could not update module: "+err.Error())
+			return
+		}
+		plan.EffectiveName = effectiveName(response, plan.Name)
+		resp.Diagnostics.Append(recordGeneration(ctx, resp.Private, response)...)
+
+		current := plan.Replicas
+		if plan.Autoscaling != nil {
+			current = state.CurrentReplicas
+		}
+		plan.CurrentReplicas = currentReplicasFromResponse(response, current)
+
+		serviceAccount := plan.ServiceAccount
+		if serviceAccount.IsUnknown() {
+			serviceAccount = state.ServiceAccount
+		}
+		plan.ServiceAccount = serviceAccountFromResponse(response, serviceAccount)
+
+		platform := plan.Platform
+		if platform.IsUnknown() {
+			platform = state.Platform
+		}
+		plan.Platform = platformFromResponse(response, platform)
+
+		gracePeriod := plan.TerminationGracePeriodSeconds
+		if gracePeriod.IsUnknown() {
+			gracePeriod = state.TerminationGracePeriodSeconds
+		}
+		plan.TerminationGracePeriodSeconds = gracePeriodFromResponse(response, gracePeriod)
+
+		// The update response may leave fields out; take them from what
+		// the server stored instead.
+		if plan.RefreshAfterUpdate.ValueBool() {
+			if response, err := r.readRemote(ctx, &plan); err != nil {
+				resp.Diagnostics.AddWarning(
+					"Could not refresh module after update",
+					"The module was updated, but reading it back failed, so its state is based on the update response until the next refresh: "+err.Error(),
+				)
+			} else {
+				resp.Diagnostics.Append(recordGeneration(ctx, resp.Private, response)...)
+			}
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NixernetesModuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NixernetesModuleModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.ID.IsNull() {
+		return
+	}
+
+	endpoint := r.client.modulesPath() + "/" + url.PathEscape(state.ID.ValueString())
+	err := r.client.Delete(ctx, endpoint)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting module", "Could not delete module: "+err.Error())
+		return
+	}
+
+	if state.WaitForDeletion.ValueBool() {
+		if err := r.client.WaitForDeletion(ctx, endpoint, moduleDeletionTimeout(state.TerminationGracePeriodSeconds)); err != nil {
+			resp.Diagnostics.AddError("Error deleting module", "Module deletion did not complete: "+err.Error())
+			return
+		}
+	}
+}
+
+// moduleDeletionTimeout extends defaultDeletionTimeout by the module's
+// termination grace period, during which its containers are still draining.
+func moduleDeletionTimeout(gracePeriod types.Int64) time.Duration {
+	return defaultDeletionTimeout + time.Duration(gracePeriod.ValueInt64())*time.Second
+}
+
+// ========== Project Resource ==========
+
+func NewNixernetesProjectResource() resource.Resource {
+	return &NixernetesProjectResource{}
+}
+
+type NixernetesProjectResource struct {
+	client *NixernetesClient
+}
+
+type NixernetesProjectModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	EffectiveName types.String `tfsdk:"effective_name"`
+	Description   types.String `tfsdk:"description"`
+	Status        types.String `tfsdk:"status"`
+	Enabled       types.Bool   `tfsdk:"enabled"`
+	CreatedAt     types.String `tfsdk:"created_at"`
+	UpdatedAt     types.String `tfsdk:"updated_at"`
+
+	AllowProductionDestroy types.Bool `tfsdk:"allow_production_destroy"`
+	CascadeDelete          types.Bool `tfsdk:"cascade_delete"`
+	WaitForDeletion        types.Bool `tfsdk:"wait_for_deletion"`
+
+	DefaultNamespace types.String `tfsdk:"default_namespace"`
+
+	Paused types.Bool `tfsdk:"paused"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *NixernetesProjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project"
+}
+
+func (r *NixernetesProjectResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a Nixernetes project.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Project ID",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Project name. Changing it replaces the project.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"effective_name": schema.StringAttribute{
+				MarkdownDescription: "Name the server assigned to the project. Usually equal to `name`, but servers that slugify or suffix names may return e.g. `api-7f3a` for `api`.",
+				Computed:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Project description",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Project status",
+				Computed:            true,
+			},
+			"default_namespace": schema.StringAttribute{
+				MarkdownDescription: "Namespace used by modules in this project that do not set their own `namespace`",
+				Optional:            true,
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the project should exist. When false the resource stays in configuration but nothing is created, and an existing project is deleted.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"allow_production_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Allow a production project to be destroyed or disabled. Must be applied as `true` before running a destroy.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"cascade_delete": schema.BoolAttribute{
+				MarkdownDescription: "Delete the project's modules along with it. Must be applied as `true` before running a destroy.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"wait_for_deletion": schema.BoolAttribute{
+				MarkdownDescription: "Wait until the project and its modules are gone before finishing the delete.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"paused": schema.BoolAttribute{
+				MarkdownDescription: "Scale all modules of the project to zero, e.g. for maintenance. The server remembers each module's replica count and restores it when the project is resumed by setting this back to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"created_at": schema.StringAttribute{
+				MarkdownDescription: "Creation timestamp",
+				Computed:            true,
+			},
+			"updated_at": schema.StringAttribute{
+				MarkdownDescription: "Last update timestamp",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
+	}
+}
+
+// ValidateConfig checks name, description and default_namespace without a
+// configured client, so `terraform validate` catches them offline.
+func (r *NixernetesProjectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var name, description, defaultNamespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("description"), &description)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("default_namespace"), &defaultNamespace)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !name.IsNull() && !name.IsUnknown() && (len(name.ValueString()) > 255 || !isValidName(name.ValueString())) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Invalid name",
+			"Name must be at most 255 characters and contain only alphanumeric characters, hyphens, and underscores, got: "+name.ValueString(),
+		)
+	}
+
+	if !description.IsNull() && !description.IsUnknown() && len(description.ValueString()) > 1000 {
+		resp.Diagnostics.AddAttributeError(path.Root("description"), "Invalid description", "Description cannot exceed 1000 characters")
+	}
+
+	if !defaultNamespace.IsNull() && !defaultNamespace.IsUnknown() && !isValidNamespace(defaultNamespace.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("default_namespace"),
+			"Invalid default namespace",
+			"Default namespace must be a valid Kubernetes namespace name, got: "+defaultNamespace.ValueString(),
+		)
+	}
+}
+
+func (r *NixernetesProjectResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan refuses to destroy or disable a production project unless
+// allow_production_destroy is set. A destroy plan has no configuration, so
+// for destroys the flag is taken from state and must be applied first.
+func (r *NixernetesProjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state NixernetesProjectModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.ID.IsNull() || !isProductionProject(&state) {
+		return
+	}
+
+	if req.Plan.Raw.IsNull() {
+		if !state.AllowProductionDestroy.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Cannot destroy production project",
+				fmt.Sprintf("Project %q is a production project. To destroy it, set allow_production_destroy = true and apply, then destroy.", state.Name.ValueString()),
+			)
+		}
+		return
+	}
+
+	var plan NixernetesProjectModel
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !isEnabled(plan.Enabled) && !plan.AllowProductionDestroy.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("enabled"),
+			"Cannot disable production project",
+			fmt.Sprintf("Disabling project %q would delete a production project. Set allow_production_destroy = true to allow it.", state.Name.ValueString()),
+		)
+	}
+}
+
+// isProductionProject reports whether the API considers the project production.
+func isProductionProject(m *NixernetesProjectModel) bool {
+	return strings.EqualFold(m.Status.ValueString(), "production")
+}
+
+func (r *NixernetesProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NixernetesProjectModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateProjectModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(projectFieldPaths)...)
+		return
+	}
+
+	if !isEnabled(plan.Enabled) {
+		tflog.Debug(ctx, "Project disabled, skipping creation", map[string]any{"name": plan.Name.ValueString()})
+		plan.clearRemote()
+		diags = resp.State.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if err := r.createRemote(ctx, &plan, &resp.State); err != nil {
+		if diags := fieldErrorDiagnostics(err, projectFieldPaths); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		resp.Diagnostics.AddError("Error creating project", "Could not create project: "+err.Error())
+		return
+	}
+	if plan.Paused.ValueBool() {
+		resp.Diagnostics.Append(r.setPaused(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NixernetesProjectResource) createRemote(ctx context.Context, plan *NixernetesProjectModel, state *tfsdk.State) error {
+	response, err := r.client.Post(ctx, r.client.projectsPath(), projectRequestBody(plan))
+	if err != nil {
+		return err
+	}
+
+	id, ok := response["id"].(string)
+	if !ok {
+		return fmt.Errorf("create response did not include an id")
+	}
+	plan.ID = types.StringValue(id)
+	recordCreatedID(ctx, state, plan.ID)
+	fields, err := requireStrings(response, "status", "created_at", "updated_at")
+	if err != nil {
+		return err
+	}
+	plan.EffectiveName = effectiveName(response, plan.Name)
+	plan.Status = types.StringValue(fields["status"])
+	plan.CreatedAt = types.StringValue(fields["created_at"])
+	plan.UpdatedAt = types.StringValue(fields["updated_at"])
+
+	return nil
+}
+
+// projectRequestBody builds the create and update request body for a project,
+// leaving out unset optional fields.
+func projectRequestBody(plan *NixernetesProjectModel) map[string]interface{} {
+	body := map[string]interface{}{
+		"name": plan.Name.ValueString(),
+	}
+	if !plan.Description.IsNull() && !plan.Description.IsUnknown() {
+		body["description"] = plan.Description.ValueString()
+	}
+	if !plan.DefaultNamespace.IsNull() && !plan.DefaultNamespace.IsUnknown() {
+		body["default_namespace"] = plan.DefaultNamespace.ValueString()
+	}
+	return body
+}
+
+// setPaused pauses or resumes the project through the API. Pausing scales
+// every module of the project to zero; the server records each module's
+// replica count and resuming restores it, so the counts are never sent from
+// here.
+func (r *NixernetesProjectResource) setPaused(ctx context.Context, plan *NixernetesProjectModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	action := "resume"
+	if plan.Paused.ValueBool() {
+		action = "pause"
+	}
+
+	tflog.Info(ctx, "Changing project pause state", map[string]any{"id": plan.ID.ValueString(), "paused": plan.Paused.ValueBool()})
+	response, err := r.client.Post(ctx, r.client.projectsPath()+"/"+url.PathEscape(plan.ID.ValueString())+"/"+action, nil)
+	if err != nil {
+		diags.AddError("Error changing project pause state", fmt.Sprintf("Could not %s project: %s", action, err))
+		return diags
+	}
+
+	plan.Paused = pausedFromResponse(response, plan.Paused)
+	return diags
+}
+
+// pausedFromResponse returns whether the API reports the project as paused,
+// falling back to fallback for servers that do not report it.
+func pausedFromResponse(response map[string]interface{}, fallback types.Bool) types.Bool {
+	if paused, ok := response["paused"].(bool); ok {
+		return types.BoolValue(paused)
+	}
+	return fallback
+}
+
+func (m *NixernetesProjectModel) clearRemote() {
+	m.ID = types.StringNull()
+	m.EffectiveName = types.StringNull()
+	m.Status = types.StringNull()
+	m.CreatedAt = types.StringNull()
+	m.UpdatedAt = types.StringNull()
+}
+
+func (r *NixernetesProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NixernetesProjectModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !isEnabled(state.Enabled) || state.ID.IsNull() {
+		return
+	}
+
+	response, err := r.client.Get(ctx, r.client.projectsPath()+"/"+url.PathEscape(state.ID.ValueString()))
+	if err != nil {
+		// Deleted outside Terraform; removing it plans a recreate.
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading project", "Could not read project: "+err.Error())
+		return
+	}
+
+	fields, err := requireStrings(response, "name", "status", "updated_at")
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading project", "Could not read project: "+err.Error())
+		return
+	}
+
+	state.Name, state.EffectiveName = namesFromResponse(fields["name"], state.Name, state.EffectiveName)
+	// An unset description is not sent, so the server may report it as empty or not at all.
+	if description, _ := response["description"].(string); description != "" || !state.Description.IsNull() {
+		state.Description = types.StringValue(description)
+	}
+	state.Status = types.StringValue(fields["status"])
+	state.UpdatedAt = types.StringValue(fields["updated_at"])
+	// Needed after an import; created_at never changes otherwise.
+	if createdAt, ok := response["created_at"].(string); ok {
+		state.CreatedAt = types.StringValue(createdAt)
+	}
+	resp.Diagnostics.Append(recordGeneration(ctx, resp.Private, response)...)
+	// A default namespace cleared on the server is reported as empty or not at all.
+	state.DefaultNamespace = types.StringNull()
+	if ns, _ := response["default_namespace"].(string); ns != "" {
+		state.DefaultNamespace = types.StringValue(ns)
+	}
+	state.Paused = pausedFromResponse(response, state.Paused)
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NixernetesProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state NixernetesProjectModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateProjectModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(projectFieldPaths)...)
+		return
+	}
+
+	switch {
+	case !isEnabled(plan.Enabled):
+		if !state.ID.IsNull() {
+			err := r.client.Delete(ctx, r.client.projectsPath()+"/"+url.PathEscape(state.ID.ValueString()))
+			if err != nil {
+				resp.Diagnostics.AddError("Error disabling project", "Could not delete project: "+err.Error())
+				return
+			}
+		}
+		plan.clearRemote()
+
+	case state.ID.IsNull():
+		if err := r.createRemote(ctx, &plan, &resp.State); err != nil {
+			if diags := fieldErrorDiagnostics(err, projectFieldPaths); diags.HasError() {
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+			resp.Diagnostics.AddError("Error enabling project", "Could not create project: "+err.Error())
+			return
+		}
+		if plan.Paused.ValueBool() {
+			resp.Diagnostics.Append(r.setPaused(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+
+	default:
+		plan.ID = state.ID
+		plan.Status = state.Status
+		plan.CreatedAt = state.CreatedAt
+
+		body := projectRequestBody(&plan)
+		resp.Diagnostics.Append(addGeneration(ctx, req.Private, body)...)
+		response, err := r.client.UpdateChanges(ctx, r.client.projectsPath()+"/"+url.PathEscape(plan.ID.ValueString()), projectRequestBody(&state), body)
+		if isGenerationConflict(err) {
+			resp.Diagnostics.Append(generationConflictDiagnostic("project", plan.Name.ValueString()))
+			return
+		}
+		if diags := fieldErrorDiagnostics(err, projectFieldPaths); diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Error updating project", "Could not update project: "+err.Error())
+			return
+		}
+
+		fields, err := requireStrings(response, "updated_at")
+		if err != nil {
+			resp.Diagnostics.AddError("Error updating project", "The project was updated, but its "+err.Error())
+			return
+		}
+		plan.EffectiveName = effectiveName(response, plan.Name)
+		plan.UpdatedAt = types.StringValue(fields["updated_at"])
+		resp.Diagnostics.Append(recordGeneration(ctx, resp.Private, response)...)
+
+		if plan.Paused.ValueBool() != state.Paused.ValueBool() {
+			resp.Diagnostics.Append(r.setPaused(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NixernetesProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NixernetesProjectModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.ID.IsNull() {
+		return
+	}
+
+	endpoint := r.client.projectsPath() + "/" + url.PathEscape(state.ID.ValueString())
+	deleteEndpoint := endpoint
+	if state.CascadeDelete.ValueBool() {
+		deleteEndpoint += "?cascade=true"
+		tflog.Info(ctx, "Deleting project and its modules", map[string]any{"id": state.ID.ValueString()})
+	}
+
+	err := r.client.Delete(ctx, deleteEndpoint)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting project", "Could not delete project: "+err.Error())
+		return
+	}
+
+	if state.WaitForDeletion.ValueBool() {
+		if err := r.client.WaitForDeletion(ctx, endpoint, defaultDeletionTimeout); err != nil {
+			resp.Diagnostics.AddError("Error deleting project", "Project deletion did not complete: "+err.Error())
+			return
+		}
+	}
+}
+
+// ImportState imports a project by ID. The project's modules and
+// configurations are not imported with it; they are listed in a warning,
+// with import blocks to copy, so the whole tree can be brought under
+// Terraform in one go.
+func (r *NixernetesProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, diags := resolveImportID(ctx, r.client, req.ID, r.client.projectsPath(), "projects", "project")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(setImportDefaults(ctx, &resp.State, map[string]interface{}{
+		"enabled":                  true,
+		"allow_production_destroy": false,
+		"cascade_delete":           false,
+		"wait_for_deletion":        false,
+		"paused":                   false,
+	})...)
+
+	children, err := r.client.GetProjectChildren(ctx, id)
+	if err != nil {
+		tflog.Warn(ctx, "Could not list the modules and configurations of the imported project", map[string]any{"id": id, "error": err.Error()})
+		return
+	}
+	if len(children.Modules) == 0 && len(children.Configs) == 0 {
+		return
+	}
+
+	resp.Diagnostics.AddWarning(
+		"Project has modules and configurations",
+		fmt.Sprintf("Project %q has %d modules and %d configurations, which were not imported. "+
+			"To manage them with Terraform, add these import blocks along with matching resources, "+
+			"or use the import_blocks attribute of the nixernetes_project data source:\n\n%s",
+			id, len(children.Modules), len(children.Configs), projectImportBlocks(children)),
+	)
+}
+
+// projectImportBlocks returns Terraform import blocks for the modules and
+// configurations of a project, named after each one.
+func projectImportBlocks(children *ProjectChildren) string {
+	var b strings.Builder
+	for _, group := range []struct {
+		resourceType string
+		children     []ProjectChild
+	}{
+		{"nixernetes_module", children.Modules},
+		{"nixernetes_config", children.Configs},
+	} {
+		used := map[string]bool{}
+		for _, child := range group.children {
+			label := importLabel(child.Name, child.ID)
+			for n := 2; used[label]; n++ {
+				label = fmt.Sprintf("%s_%d", importLabel(child.Name, child.ID), n)
+			}
+			used[label] = true
+			fmt.Fprintf(&b, "import {\n  to = %s.%s\n  id = %q\n}\n\n", group.resourceType, label, child.ID)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// importLabel turns a resource name into a valid Terraform resource label,
+// falling back to the ID when the name is empty.
+func importLabel(name, id string) string {
+	if name == "" {
+		name = id
+	}
+	label := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+	if label[0] >= '0' && label[0] <= '9' || label[0] == '-' {
+		label = "_" + label
+	}
+	return label
+}
+
+// defaultDeletionTimeout bounds how long Delete waits when wait_for_deletion is set.
+const defaultDeletionTimeout = 10 * time.Minute
+
+// ========== Resource Quota Resource ==========
+
+func NewNixernetesResourceQuotaResource() resource.Resource {
+	return &NixernetesResourceQuotaResource{}
+}
+
+type NixernetesResourceQuotaResource struct {
+	client *NixernetesClient
+}
+
+type NixernetesResourceQuotaModel struct {
+	ID        types.String `tfsdk:"id"`
+	Namespace types.String `tfsdk:"namespace"`
+	CPU       types.String `tfsdk:"cpu"`
+	Memory    types.String `tfsdk:"memory"`
+	Pods      types.Int64  `tfsdk:"pods"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *NixernetesResourceQuotaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_resource_quota"
+}
+
+func (r *NixernetesResourceQuotaResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the resource quota of a Nixernetes namespace. Modules that would exceed the quota fail to create.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Quota ID (the namespace name)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"namespace": schema.StringAttribute{
+				MarkdownDescription: "Kubernetes namespace the quota applies to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"cpu": schema.StringAttribute{
+				MarkdownDescription: "Total CPU limit as a Kubernetes quantity, e.g. `4` or `500m`",
+				Optional:            true,
+			},
+			"memory": schema.StringAttribute{
+				MarkdownDescription: "Total memory limit as a Kubernetes quantity, e.g. `8Gi`",
+				Optional:            true,
+			},
+			"pods": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of pods",
+				Optional:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
+	}
+}
+
+// ValidateConfig checks namespace and the limits without a configured
+// client, so `terraform validate` catches them offline.
+func (r *NixernetesResourceQuotaResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var namespace, cpu, memory types.String
+	var pods types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("namespace"), &namespace)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cpu"), &cpu)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("memory"), &memory)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("pods"), &pods)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !namespace.IsNull() && !namespace.IsUnknown() && !isValidNamespace(namespace.ValueString()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("namespace"),
+			"Invalid namespace",
+			"Namespace must be a valid Kubernetes namespace name, got: "+namespace.ValueString(),
+		)
+	}
+
+	for name, quantity := range map[string]types.String{"cpu": cpu, "memory": memory} {
+		if !quantity.IsNull() && !quantity.IsUnknown() && !isValidQuantity(quantity.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid "+name+" limit",
+				fmt.Sprintf("%q is not a valid Kubernetes quantity", quantity.ValueString()),
+			)
+		}
+	}
+
+	if !pods.IsNull() && !pods.IsUnknown() && pods.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("pods"), "Invalid pods limit", "Pod limit cannot be negative")
+	}
+
+	if cpu.IsNull() && memory.IsNull() && pods.IsNull() {
+		resp.Diagnostics.AddError("Missing quota limit", "At least one of cpu, memory or pods must be set")
+	}
+}
+
+func (r *NixernetesResourceQuotaResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	r.client = client
+}
+
+func (r *NixernetesResourceQuotaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NixernetesResourceQuotaModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateResourceQuotaModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(quotaFieldPaths)...)
+		return
+	}
+
+	_, err := r.client.Put(ctx, quotaPath(plan.Namespace.ValueString()), quotaRequestBody(&plan))
+	if diags := fieldErrorDiagnostics(err, quotaFieldPaths); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating resource quota", "Could not set resource quota: "+err.Error())
+		return
+	}
+
+	plan.ID = plan.Namespace
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// quotaPath returns the API path of the quota for namespace.
+func quotaPath(namespace string) string {
+	return "/namespaces/" + url.PathEscape(namespace) + "/quota"
+}
+
+// quotaRequestBody builds the create/update request body for a resource quota.
+// Unset limits are omitted so the server leaves them unbounded.
+func quotaRequestBody(plan *NixernetesResourceQuotaModel) map[string]interface{} {
+	body := map[string]interface{}{}
+
+	if !plan.CPU.IsNull() {
+		body["cpu"] = plan.CPU.ValueString()
+	}
+	if !plan.Memory.IsNull() {
+		body["memory"] = plan.Memory.ValueString()
+	}
+	if !plan.Pods.IsNull() {
+		body["pods"] = plan.Pods.ValueInt64()
+	}
+
+	return body
+}
+
+func (r *NixernetesResourceQuotaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NixernetesResourceQuotaModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	response, err := r.client.Get(ctx, quotaPath(state.Namespace.ValueString()))
+	if err != nil {
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading resource quota", "Could not read resource quota: "+err.Error())
+		return
+	}
+
+	state.ID = state.Namespace
+	state.CPU = types.StringNull()
+	if cpu, ok := response["cpu"].(string); ok {
+		state.CPU = types.StringValue(cpu)
+	}
+	state.Memory = types.StringNull()
+	if memory, ok := response["memory"].(string); ok {
+		state.Memory = types.StringValue(memory)
+	}
+	state.Pods = types.Int64Null()
+	if pods, ok := response["pods"].(float64); ok {
+		state.Pods = types.Int64Value(int64(pods))
+	}
+
+	diags = resp.State.Set(ctx, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NixernetesResourceQuotaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan NixernetesResourceQuotaModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateResourceQuotaModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(quotaFieldPaths)...)
+		return
+	}
+
+	_, err := r.client.Put(ctx, quotaPath(plan.Namespace.ValueString()), quotaRequestBody(&plan))
+	if diags := fieldErrorDiagnostics(err, quotaFieldPaths); diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating resource quota", "Could not update resource quota: "+err.Error())
+		return
+	}
+
+	plan.ID = plan.Namespace
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *NixernetesResourceQuotaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NixernetesResourceQuotaModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.Delete(ctx, quotaPath(state.Namespace.ValueString()))
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting resource quota", "Could not delete resource quota: "+err.Error())
+		return
+	}
+}
+
+// ImportState imports a quota by namespace name.
+func (r *NixernetesResourceQuotaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("namespace"), req, resp)
+}
+
+// ========== Module Rollout Resource ==========
+
+// Rollout actions accepted by nixernetes_module_rollout.
+const (
+	rolloutActionPause  = "pause"
+	rolloutActionResume = "resume"
+	rolloutActionAbort  = "abort"
+)
+
+func NewNixernetesModuleRolloutResource() resource.Resource {
+	return &NixernetesModuleRolloutResource{}
+}
+
+// NixernetesModuleRolloutResource controls the rollout of a module. Each
+// create or change of action sends the action to the API; the resource does
+// not own the module or the rollout.
+type NixernetesModuleRolloutResource struct {
+	client *NixernetesClient
+}
+
+type NixernetesModuleRolloutModel struct {
+	ID       types.String `tfsdk:"id"`
+	ModuleID types.String `tfsdk:"module_id"`
+	Action   types.String `tfsdk:"action"`
+	Status   types.String `tfsdk:"status"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *NixernetesModuleRolloutResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_rollout"
+}
+
+func (r *NixernetesModuleRolloutResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Pauses, resumes or aborts the in-progress rollout of a Nixernetes module. The action is sent when the resource is created and whenever `action` changes; destroying the resource does not undo it.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Rollout control ID (the module ID)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"module_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the module whose rollout is controlled",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"action": schema.StringAttribute{
+				MarkdownDescription: "Rollout action: `pause`, `resume` or `abort`",
+				Required:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Rollout status reported by the API after the action, e.g. `paused`",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true}),
+		},
+	}
+}
+
+func (r *NixernetesModuleRolloutResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*NixernetesClient)
+	if !ok {
+		return
+	}
+
+	r.client = client
+}
+
+// ValidateConfig rejects an unknown action before anything is planned.
+func (r *NixernetesModuleRolloutResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var action types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("action"), &action)...)
+	if resp.Diagnostics.HasError() || action.IsNull() || action.IsUnknown() {
+		return
+	}
+
+	switch action.ValueString() {
+	case rolloutActionPause, rolloutActionResume, rolloutActionAbort:
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("action"),
+			"Invalid rollout action",
+			fmt.Sprintf("action must be %q, %q or %q, got: %s", rolloutActionPause, rolloutActionResume, rolloutActionAbort, action.ValueString()),
+		)
+	}
+}
+
+func (r *NixernetesModuleRolloutResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NixernetesModuleRolloutModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateModuleRolloutModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(rolloutFieldPaths)...)
+		return
+	}
+
+	if err := r.applyAction(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error controlling module rollout", err.Error())
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// applyAction checks that the module exists and sends the rollout action,
+// recording the resulting status on the model.
+func (r *NixernetesModuleRolloutResource) applyAction(ctx context.Context, plan *NixernetesModuleRolloutModel) error {
+	moduleID := plan.ModuleID.ValueString()
+	modulePath := r.client.modulesPath() + "/" + url.PathEscape(moduleID)
+	_, err := r.client.Get(ctx, modulePath)
+	if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+		return fmt.Errorf("module %q does not exist", moduleID)
+	}
+	if err != nil {
+		return fmt.Errorf("could not look up module %q: %w", moduleID, err)
+	}
+
+	action := plan.Action.ValueString()
+	response, err := r.client.Post(ctx, modulePath+"/rollout/"+action, map[string]interface{}{})
+	if err != nil {
+		return fmt.Errorf("could not %s the rollout of module %q: %w", action, moduleID, err)
+	}
+
+	tflog.Trace(ctx, "Sent module rollout action", map[string]any{"id": moduleID, "action": action})
+
+	plan.ID = plan.ModuleID
+	plan.Status = types.StringNull()
+	if status, ok := response["status"].(string); ok && status != "" {
+		plan.Status = types.StringValue(status)
+	}
+	return nil
+}
+
+func (r *NixernetesModuleRolloutResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NixernetesModuleRolloutModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The action has no remote state of its own; it goes when the module does.
+	_, err := r.client.Get(ctx, r.client.modulesPath()+"/"+url.PathEscape(state.ModuleID.ValueString()))
+	if err != nil {
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading module rollout", "Could not read module: "+err.Error())
+		return
+	}
+}
+
+func (r *NixernetesModuleRolloutResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan NixernetesModuleRolloutModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	response, err := r.client.Put(ctx, "/configs/"+plan.ID.ValueString(), body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating configuration",
-			"Could not update configuration, unexpected error: "+err.Error(),
-		)
+	if v := ValidateModuleRolloutModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(rolloutFieldPaths)...)
 		return
 	}
 
-	plan.UpdatedAt = types.StringValue(response["updated_at"].(string))
+	if err := r.applyAction(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Error controlling module rollout", err.Error())
+		return
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
 
-// Delete deletes the configuration.
-func (r *NixernetesConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state NixernetesConfigModel
+// Delete only forgets the resource: a sent rollout action cannot be undone.
+func (r *NixernetesModuleRolloutResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NixernetesModuleRolloutModel
 
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -199,77 +3549,133 @@ func (r *NixernetesConfigResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
-	// API call to delete configuration
-	err := r.client.Delete(ctx, "/configs/"+state.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting configuration",
-			"Could not delete configuration, unexpected error: "+err.Error(),
-		)
+	tflog.Debug(ctx, "Removing module rollout control from state", map[string]any{"id": state.ModuleID.ValueString()})
+}
+
+// ========== Map Attributes ==========
+
+// nullEmptyMap treats an unset map attribute and an empty one as the same
+// value. The API returns {} for a map that was never sent, which would
+// otherwise show as a change from null to {} on every plan. Use it on
+// Optional+Computed map attributes, and read them with stringMapFromResponse.
+type nullEmptyMap struct{}
+
+func (m nullEmptyMap) Description(_ context.Context) string {
+	return "Treats an unset map and an empty map as equal."
+}
+
+func (m nullEmptyMap) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m nullEmptyMap) PlanModifyMap(_ context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	// A configured map, including {}, is planned as written.
+	if !req.ConfigValue.IsNull() {
 		return
 	}
 
-	tflog.Trace(ctx, "Deleted configuration", map[string]any{"id": state.ID.ValueString()})
+	// An unset map clears any entries; an empty map in state already matches.
+	if !req.StateValue.IsNull() && len(req.StateValue.Elements()) == 0 {
+		resp.PlanValue = req.StateValue
+		return
+	}
+	resp.PlanValue = types.MapNull(types.StringType)
 }
 
-// ========== Module Resource ==========
+// stringMapFromResponse converts a map of strings returned by the API to a
+// map attribute. An empty or missing map keeps prior when that is null or
+// empty too, so null and {} do not replace each other on refresh.
+func stringMapFromResponse(value interface{}, prior types.Map) types.Map {
+	remote, _ := value.(map[string]interface{})
+	if len(remote) == 0 {
+		if !prior.IsUnknown() && len(prior.Elements()) == 0 {
+			return prior
+		}
+		return types.MapNull(types.StringType)
+	}
 
-func NewNixernetesModuleResource() resource.Resource {
-	return &NixernetesModuleResource{}
+	elements := make(map[string]attr.Value, len(remote))
+	for key, v := range remote {
+		s, _ := v.(string)
+		elements[key] = types.StringValue(s)
+	}
+	return types.MapValueMust(types.StringType, elements)
 }
 
-type NixernetesModuleResource struct {
+// ========== Secret Resource ==========
+
+func NewNixernetesSecretResource() resource.Resource {
+	return &NixernetesSecretResource{}
+}
+
+type NixernetesSecretResource struct {
 	client *NixernetesClient
 }
 
-type NixernetesModuleModel struct {
+type NixernetesSecretModel struct {
 	ID        types.String `tfsdk:"id"`
 	Name      types.String `tfsdk:"name"`
-	Replicas  types.Int64  `tfsdk:"replicas"`
-	Image     types.String `tfsdk:"image"`
 	Namespace types.String `tfsdk:"namespace"`
-	CreatedAt types.String `tfsdk:"created_at"`
+	Data      types.Map    `tfsdk:"data"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
-func (r *NixernetesModuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_module"
+func (r *NixernetesSecretResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret"
 }
 
-func (r *NixernetesModuleResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *NixernetesSecretResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Manages a Nixernetes module instance.",
+		MarkdownDescription: "Manages a Nixernetes secret, such as credentials or environment variables for modules. Secret values are never logged.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				MarkdownDescription: "Module instance ID",
+				MarkdownDescription: "Secret ID",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Module instance name",
-				Required:            true,
-			},
-			"replicas": schema.Int64Attribute{
-				MarkdownDescription: "Number of replicas",
-				Optional:            true,
-				Computed:            true,
-			},
-			"image": schema.StringAttribute{
-				MarkdownDescription: "Container image",
+				MarkdownDescription: "Secret name. Changing it replaces the secret.",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"namespace": schema.StringAttribute{
-				MarkdownDescription: "Kubernetes namespace",
-				Optional:            true,
-				Computed:            true,
+				MarkdownDescription: "Kubernetes namespace of the secret. Changing it replaces the secret.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"created_at": schema.StringAttribute{
-				MarkdownDescription: "Creation timestamp",
-				Computed:            true,
+			"data": schema.MapAttribute{
+				MarkdownDescription: "Secret values by key. Keys may contain letters, digits, `-`, `_` and `.`.",
+				ElementType:         types.StringType,
+				Required:            true,
+				Sensitive:           true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
+		},
 	}
 }
 
-func (r *NixernetesModuleResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+// ValidateConfig checks name, namespace and the data keys without a
+// configured client, so `terraform validate` catches them offline.
+func (r *NixernetesSecretResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config NixernetesSecretModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(ValidateSecretModel(ctx, &config).ToDiagnosticsWithPath(secretFieldPaths)...)
+}
+
+func (r *NixernetesSecretResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
@@ -282,8 +3688,8 @@ func (r *NixernetesModuleResource) Configure(_ context.Context, req resource.Con
 	r.client = client
 }
 
-func (r *NixernetesModuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan NixernetesModuleModel
+func (r *NixernetesSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NixernetesSecretModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -291,28 +3697,122 @@ func (r *NixernetesModuleResource) Create(ctx context.Context, req resource.Crea
 		return
 	}
 
-	body := map[string]interface{}{
-		"name":      plan.Name.ValueString(),
-		"replicas":  plan.Replicas.ValueInt64(),
-		"image":     plan.Image.ValueString(),
-		"namespace": plan.Namespace.ValueString(),
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateSecretModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(secretFieldPaths)...)
+		return
 	}
 
-	response, err := r.client.Post(ctx, "/modules", body)
+	data := secretData(plan.Data)
+	ctx = maskSecretValues(ctx, data)
+
+	response, err := r.client.Post(ctx, secretsPath, secretRequestBody(&plan, data))
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating module", "Could not create module: "+err.Error())
+		resp.Diagnostics.AddError("Error creating secret", "Could not create secret: "+redactSecretValues(err.Error(), data))
 		return
 	}
 
-	plan.ID = types.StringValue(response["id"].(string))
-	plan.CreatedAt = types.StringValue(response["created_at"].(string))
+	fields, err := requireStrings(response, "id")
+	if err != nil {
+		resp.Diagnostics.AddError("Unexpected API response", "The secret was created, but its "+err.Error())
+		return
+	}
+	plan.ID = types.StringValue(fields["id"])
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
 
-func (r *NixernetesModuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state NixernetesModuleModel
+// secretsPath is the API path under which secrets live.
+const secretsPath = "/secrets"
+
+// secretData returns the known values of a secret's data map.
+func secretData(m types.Map) map[string]string {
+	data := make(map[string]string, len(m.Elements()))
+	for key, value := range m.Elements() {
+		if s, ok := value.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			data[key] = s.ValueString()
+		}
+	}
+	return data
+}
+
+// maskSecretValues returns ctx with the secret values masked in every log
+// entry written through it, including the client's request logging.
+func maskSecretValues(ctx context.Context, data map[string]string) context.Context {
+	values := make([]string, 0, len(data))
+	for _, value := range data {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, values...)
+	return tflog.MaskMessageStrings(ctx, values...)
+}
+
+// redactSecretValues replaces the secret values in text, such as an API
+// error that echoes the request body, with redactedPlaceholder.
+func redactSecretValues(text string, data map[string]string) string {
+	for _, value := range data {
+		if value != "" {
+			text = strings.ReplaceAll(text, value, redactedPlaceholder)
+		}
+	}
+	return text
+}
+
+// secretRequestBody builds the create and update request body for a secret.
+func secretRequestBody(plan *NixernetesSecretModel, data map[string]string) map[string]interface{} {
+	values := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		values[key] = value
+	}
+	return map[string]interface{}{
+		"name":      plan.Name.ValueString(),
+		"namespace": plan.Namespace.ValueString(),
+		"data":      values,
+	}
+}
+
+// isRedactedSecretValue reports whether a value read from the API is a
+// placeholder for a value the server does not return, such as "" or "****".
+func isRedactedSecretValue(value string) bool {
+	return value == redactedPlaceholder || strings.Trim(value, "*") == ""
+}
+
+// readSecretData merges the data of a read response into the data in
+// state. Keys the server no longer has are dropped and new keys added, so
+// changes made outside Terraform show up in the plan, but redacted values
+// keep the value in state rather than showing a perpetual diff. A response
+// without data leaves state as it is.
+func readSecretData(state types.Map, response map[string]interface{}) types.Map {
+	remote, ok := response["data"].(map[string]interface{})
+	if !ok {
+		return state
+	}
+
+	prior := state.Elements()
+	elements := make(map[string]attr.Value, len(remote))
+	for key, v := range remote {
+		value, _ := v.(string)
+		if isRedactedSecretValue(value) {
+			if known, ok := prior[key]; ok {
+				elements[key] = known
+				continue
+			}
+		}
+		elements[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+func (r *NixernetesSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NixernetesSecretModel
 
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -320,23 +3820,39 @@ func (r *NixernetesModuleResource) Read(ctx context.Context, req resource.ReadRe
 		return
 	}
 
-	response, err := r.client.Get(ctx, "/modules/"+state.ID.ValueString())
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = maskSecretValues(ctx, secretData(state.Data))
+
+	response, err := r.client.Get(ctx, secretsPath+"/"+url.PathEscape(state.ID.ValueString()))
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading module", "Could not read module: "+err.Error())
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			// Deleted outside Terraform; removing it plans a recreate.
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Error reading secret", "Could not read secret: "+redactSecretValues(err.Error(), secretData(state.Data)))
 		return
 	}
 
-	state.Name = types.StringValue(response["name"].(string))
-	state.Replicas = types.Int64Value(int64(response["replicas"].(float64)))
-	state.Image = types.StringValue(response["image"].(string))
-	state.Namespace = types.StringValue(response["namespace"].(string))
+	if name, ok := getString(response, "name"); ok {
+		state.Name = types.StringValue(name)
+	}
+	if namespace, ok := getString(response, "namespace"); ok {
+		state.Namespace = types.StringValue(namespace)
+	}
+	state.Data = readSecretData(state.Data, response)
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 }
 
-func (r *NixernetesModuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan NixernetesModuleModel
+func (r *NixernetesSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan NixernetesSecretModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -344,16 +3860,23 @@ func (r *NixernetesModuleResource) Update(ctx context.Context, req resource.Upda
 		return
 	}
 
-	body := map[string]interface{}{
-		"name":      plan.Name.ValueString(),
-		"replicas":  plan.Replicas.ValueInt64(),
-		"image":     plan.Image.ValueString(),
-		"namespace": plan.Namespace.ValueString(),
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateSecretModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(secretFieldPaths)...)
+		return
 	}
 
-	_, err := r.client.Put(ctx, "/modules/"+plan.ID.ValueString(), body)
+	data := secretData(plan.Data)
+	ctx = maskSecretValues(ctx, data)
+
+	_, err := r.client.Update(ctx, secretsPath+"/"+url.PathEscape(plan.ID.ValueString()), secretRequestBody(&plan, data))
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating module", "Could not update module: "+err.Error())
+		resp.Diagnostics.AddError("Error updating secret", "Could not update secret: "+redactSecretValues(err.Error(), data))
 		return
 	}
 
@@ -361,8 +3884,8 @@ func (r *NixernetesModuleResource) Update(ctx context.Context, req resource.Upda
 	resp.Diagnostics.Append(diags...)
 }
 
-func (r *NixernetesModuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state NixernetesModuleModel
+func (r *NixernetesSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NixernetesSecretModel
 
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -370,69 +3893,112 @@ func (r *NixernetesModuleResource) Delete(ctx context.Context, req resource.Dele
 		return
 	}
 
-	err := r.client.Delete(ctx, "/modules/"+state.ID.ValueString())
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.Delete(ctx, secretsPath+"/"+url.PathEscape(state.ID.ValueString()))
 	if err != nil {
-		resp.Diagnostics.AddError("Error deleting module", "Could not delete module: "+err.Error())
+		resp.Diagnostics.AddError("Error deleting secret", "Could not delete secret: "+err.Error())
 		return
 	}
 }
 
-// ========== Project Resource ==========
+// ========== Module Group Resource ==========
 
-func NewNixernetesProjectResource() resource.Resource {
-	return &NixernetesProjectResource{}
+func NewNixernetesModuleGroupResource() resource.Resource {
+	return &NixernetesModuleGroupResource{}
 }
 
-type NixernetesProjectResource struct {
+// NixernetesModuleGroupResource manages several modules created together in
+// one batch request, for configurations with many small modules.
+type NixernetesModuleGroupResource struct {
 	client *NixernetesClient
 }
 
-type NixernetesProjectModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Status      types.String `tfsdk:"status"`
-	CreatedAt   types.String `tfsdk:"created_at"`
-	UpdatedAt   types.String `tfsdk:"updated_at"`
+type NixernetesModuleGroupModel struct {
+	ID      types.String                      `tfsdk:"id"`
+	Modules []NixernetesModuleGroupEntryModel `tfsdk:"modules"`
+
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
 }
 
-func (r *NixernetesProjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_project"
+type NixernetesModuleGroupEntryModel struct {
+	ID        types.String `tfsdk:"id"`
+	Name      types.String `tfsdk:"name"`
+	Image     types.String `tfsdk:"image"`
+	Namespace types.String `tfsdk:"namespace"`
+	Replicas  types.Int64  `tfsdk:"replicas"`
 }
 
-func (r *NixernetesProjectResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+func (r *NixernetesModuleGroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_module_group"
+}
+
+func (r *NixernetesModuleGroupResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Manages a Nixernetes project.",
+		MarkdownDescription: "Manages a group of Nixernetes modules that are created in a single batch request instead of one request per module. " +
+			"Modules are matched by name when the group changes: new names are created, missing names deleted and the rest updated in place.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				MarkdownDescription: "Project ID",
+				MarkdownDescription: "Group ID, generated by the provider",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"name": schema.StringAttribute{
-				MarkdownDescription: "Project name",
+			"modules": schema.ListNestedAttribute{
+				MarkdownDescription: "Modules in the group. Names must be unique within the group.",
 				Required:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Module instance ID",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Module name",
+							Required:            true,
+						},
+						"image": schema.StringAttribute{
+							MarkdownDescription: "Container image",
+							Required:            true,
+						},
+						"namespace": schema.StringAttribute{
+							MarkdownDescription: "Kubernetes namespace. Changing it replaces the module. Defaults to the server's namespace.",
+							Optional:            true,
+							Computed:            true,
+						},
+						"replicas": schema.Int64Attribute{
+							MarkdownDescription: "Number of replicas. Defaults to the server's replica count.",
+							Optional:            true,
+							Computed:            true,
+						},
+					},
+				},
 			},
-			"description": schema.StringAttribute{
-				MarkdownDescription: "Project description",
-				Optional:            true,
-			},
-			"status": schema.StringAttribute{
-				MarkdownDescription: "Project status",
-				Computed:            true,
-			},
-			"created_at": schema.StringAttribute{
-				MarkdownDescription: "Creation timestamp",
-				Computed:            true,
-			},
-			"updated_at": schema.StringAttribute{
-				MarkdownDescription: "Last update timestamp",
-				Computed:            true,
-			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{Create: true, Read: true, Update: true, Delete: true}),
 		},
 	}
 }
 
-func (r *NixernetesProjectResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
+// ValidateConfig checks the modules of the group without a configured
+// client, so `terraform validate` catches mistakes offline.
+func (r *NixernetesModuleGroupResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config NixernetesModuleGroupModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(ValidateModuleGroupModel(ctx, &config).ToDiagnosticsWithPath(moduleGroupFieldPaths(len(config.Modules)))...)
+}
+
+func (r *NixernetesModuleGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, _ *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
@@ -445,8 +4011,8 @@ func (r *NixernetesProjectResource) Configure(_ context.Context, req resource.Co
 	r.client = client
 }
 
-func (r *NixernetesProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan NixernetesProjectModel
+func (r *NixernetesModuleGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NixernetesModuleGroupModel
 
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
@@ -454,28 +4020,128 @@ func (r *NixernetesProjectResource) Create(ctx context.Context, req resource.Cre
 		return
 	}
 
-	body := map[string]interface{}{
-		"name":        plan.Name.ValueString(),
-		"description": plan.Description.ValueString(),
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Create, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if v := ValidateModuleGroupModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(moduleGroupFieldPaths(len(plan.Modules)))...)
+		return
 	}
 
-	response, err := r.client.Post(ctx, "/projects", body)
+	indices := make([]int, len(plan.Modules))
+	for i := range plan.Modules {
+		indices[i] = i
+	}
+	created, err := r.createModules(ctx, plan.Modules, indices, &resp.Diagnostics)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating project", "Could not create project: "+err.Error())
+		resp.Diagnostics.AddError("Error creating module group", "Could not create modules: "+err.Error())
 		return
 	}
 
-	plan.ID = types.StringValue(response["id"].(string))
-	plan.Status = types.StringValue(response["status"].(string))
-	plan.CreatedAt = types.StringValue(response["created_at"].(string))
-	plan.UpdatedAt = types.StringValue(response["updated_at"].(string))
+	// State only lists the modules that were created. If some failed, the
+	// error taints the group and the next apply replaces it.
+	plan.ID = types.StringValue(newIdempotencyKey())
+	plan.Modules = []NixernetesModuleGroupEntryModel{}
+	for _, i := range indices {
+		if module, ok := created[i]; ok {
+			plan.Modules = append(plan.Modules, module)
+		}
+	}
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 }
 
-func (r *NixernetesProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var state NixernetesProjectModel
+// moduleBatchPath is the batch endpoint under the modules path.
+const moduleBatchPath = "/batch"
+
+// createModules creates modules in a single batch request. indices are the
+// positions of modules in the group's list, used to attach errors to the
+// right entry. It returns the created modules by position; modules the
+// server rejected are reported in diags and left out.
+func (r *NixernetesModuleGroupResource) createModules(ctx context.Context, modules []NixernetesModuleGroupEntryModel, indices []int, diags *diag.Diagnostics) (map[int]NixernetesModuleGroupEntryModel, error) {
+	created := make(map[int]NixernetesModuleGroupEntryModel, len(modules))
+	if len(modules) == 0 {
+		return created, nil
+	}
+
+	bodies := make([]map[string]interface{}, len(modules))
+	for i := range modules {
+		bodies[i] = moduleGroupEntryBody(&modules[i])
+	}
+
+	results, err := r.client.PostBatch(ctx, r.client.modulesPath()+moduleBatchPath, bodies)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, result := range results {
+		module := modules[k]
+		modulePath := path.Root("modules").AtListIndex(indices[k])
+		if result.Err != nil {
+			diags.AddAttributeError(modulePath, "Error creating module", fmt.Sprintf("Could not create module %q: %s", module.Name.ValueString(), result.Err))
+			continue
+		}
+		id, ok := getString(result.Response, "id")
+		if !ok {
+			diags.AddAttributeError(modulePath, "Unexpected API response", fmt.Sprintf("Module %q was created, but the response did not include an id", module.Name.ValueString()))
+			continue
+		}
+		module.ID = types.StringValue(id)
+		module.readResponse(result.Response)
+		created[indices[k]] = module
+	}
+
+	tflog.Debug(ctx, "Created module batch", map[string]any{
+		"requested": len(modules),
+		"created":   len(created),
+	})
+	return created, nil
+}
+
+// moduleGroupEntryBody builds the create and update request body for a
+// module of a group. Unset optional fields are left out so the server
+// applies its defaults.
+func moduleGroupEntryBody(module *NixernetesModuleGroupEntryModel) map[string]interface{} {
+	body := map[string]interface{}{
+		"name":  module.Name.ValueString(),
+		"image": module.Image.ValueString(),
+	}
+	if !module.Namespace.IsNull() && !module.Namespace.IsUnknown() {
+		body["namespace"] = module.Namespace.ValueString()
+	}
+	if !module.Replicas.IsNull() && !module.Replicas.IsUnknown() {
+		body["replicas"] = module.Replicas.ValueInt64()
+	}
+	return body
+}
+
+// readResponse records the fields of a module response. Fields the server
+// leaves out keep their value, or become null if still unknown.
+func (m *NixernetesModuleGroupEntryModel) readResponse(response map[string]interface{}) {
+	if name, ok := getString(response, "name"); ok {
+		m.Name = types.StringValue(name)
+	}
+	if image, ok := getString(response, "image"); ok {
+		m.Image = types.StringValue(image)
+	}
+	if namespace, ok := getString(response, "namespace"); ok {
+		m.Namespace = types.StringValue(namespace)
+	} else if m.Namespace.IsUnknown() {
+		m.Namespace = types.StringNull()
+	}
+	if replicas, ok := response["replicas"].(float64); ok {
+		m.Replicas = types.Int64Value(int64(replicas))
+	} else if m.Replicas.IsUnknown() {
+		m.Replicas = types.Int64Null()
+	}
+}
+
+func (r *NixernetesModuleGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NixernetesModuleGroupModel
 
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -483,49 +4149,136 @@ func (r *NixernetesProjectResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
-	response, err := r.client.Get(ctx, "/projects/"+state.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading project", "Could not read project: "+err.Error())
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Read, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	state.Name = types.StringValue(response["name"].(string))
-	state.Description = types.StringValue(response["description"].(string))
-	state.Status = types.StringValue(response["status"].(string))
-	state.UpdatedAt = types.StringValue(response["updated_at"].(string))
+	modules := []NixernetesModuleGroupEntryModel{}
+	for _, module := range state.Modules {
+		response, err := r.client.Get(ctx, r.client.modulesPath()+"/"+url.PathEscape(module.ID.ValueString()))
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == 404 {
+			// Deleted outside Terraform; dropping it plans a recreate.
+			tflog.Debug(ctx, "Module of group no longer exists", map[string]any{"id": module.ID.ValueString()})
+			continue
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading module group", fmt.Sprintf("Could not read module %q: %s", module.Name.ValueString(), err))
+			return
+		}
+		module.readResponse(response)
+		modules = append(modules, module)
+	}
+	state.Modules = modules
 
 	diags = resp.State.Set(ctx, state)
 	resp.Diagnostics.Append(diags...)
 }
 
-func (r *NixernetesProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan NixernetesProjectModel
+func (r *NixernetesModuleGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state NixernetesModuleGroupModel
 
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	body := map[string]interface{}{
-		"name":        plan.Name.ValueString(),
-		"description": plan.Description.ValueString(),
+	ctx, cancel := withOperationTimeout(ctx, r.client, plan.Timeouts.Update, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	response, err := r.client.Put(ctx, "/projects/"+plan.ID.ValueString(), body)
-	if err != nil {
-		resp.Diagnostics.AddError("Error updating project", "Could not update project: "+err.Error())
+	if v := ValidateModuleGroupModel(ctx, &plan); v.HasErrors() {
+		resp.Diagnostics.Append(v.ToDiagnosticsWithPath(moduleGroupFieldPaths(len(plan.Modules)))...)
 		return
 	}
 
-	plan.UpdatedAt = types.StringValue(response["updated_at"].(string))
+	existing := make(map[string]NixernetesModuleGroupEntryModel, len(state.Modules))
+	for _, module := range state.Modules {
+		existing[module.Name.ValueString()] = module
+	}
 
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
+	// Match planned modules to existing ones by name. A module cannot move
+	// between namespaces, so one that does is deleted and created anew.
+	results := make(map[int]NixernetesModuleGroupEntryModel, len(plan.Modules))
+	var updates, creates []int
+	for i, module := range plan.Modules {
+		prior, ok := existing[module.Name.ValueString()]
+		if ok && (module.Namespace.IsUnknown() || module.Namespace.Equal(prior.Namespace)) {
+			updates = append(updates, i)
+			results[i] = prior
+			delete(existing, module.Name.ValueString())
+			continue
+		}
+		creates = append(creates, i)
+	}
+
+	// Modules that remain are no longer in the group. One that cannot be
+	// deleted stays in state so the next apply retries.
+	var kept []NixernetesModuleGroupEntryModel
+	for _, module := range state.Modules {
+		if _, ok := existing[module.Name.ValueString()]; !ok {
+			continue
+		}
+		if err := r.client.Delete(ctx, r.client.modulesPath()+"/"+url.PathEscape(module.ID.ValueString())); err != nil {
+			resp.Diagnostics.AddError("Error updating module group", fmt.Sprintf("Could not delete module %q: %s", module.Name.ValueString(), err))
+			kept = append(kept, module)
+		}
+	}
+
+	for _, i := range updates {
+		prior, module := results[i], plan.Modules[i]
+		module.ID = prior.ID
+		if module.Namespace.IsUnknown() {
+			module.Namespace = prior.Namespace
+		}
+		if module.Replicas.IsUnknown() {
+			module.Replicas = prior.Replicas
+		}
+		if module.Image.Equal(prior.Image) && module.Replicas.Equal(prior.Replicas) {
+			results[i] = module
+			continue
+		}
+		response, err := r.client.Update(ctx, r.client.modulesPath()+"/"+url.PathEscape(module.ID.ValueString()), moduleGroupEntryBody(&module))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("modules").AtListIndex(i), "Error updating module", fmt.Sprintf("Could not update module %q: %s", module.Name.ValueString(), err))
+			continue
+		}
+		module.readResponse(response)
+		results[i] = module
+	}
+
+	toCreate := make([]NixernetesModuleGroupEntryModel, len(creates))
+	for k, i := range creates {
+		toCreate[k] = plan.Modules[i]
+	}
+	created, err := r.createModules(ctx, toCreate, creates, &resp.Diagnostics)
+	if err != nil {
+		resp.Diagnostics.AddError("Error updating module group", "Could not create modules: "+err.Error())
+	}
+	for i, module := range created {
+		results[i] = module
+	}
+
+	// State lists the modules that exist: those planned, in order, followed
+	// by any that could not be deleted. A module that failed to update keeps
+	// its prior state.
+	modules := []NixernetesModuleGroupEntryModel{}
+	for i := range plan.Modules {
+		if module, ok := results[i]; ok {
+			modules = append(modules, module)
+		}
+	}
+	plan.Modules = append(modules, kept...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func (r *NixernetesProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
-	var state NixernetesProjectModel
+func (r *NixernetesModuleGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state NixernetesModuleGroupModel
 
 	diags := req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -533,9 +4286,40 @@ func (r *NixernetesProjectResource) Delete(ctx context.Context, req resource.Del
 		return
 	}
 
-	err := r.client.Delete(ctx, "/projects/"+state.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Error deleting project", "Could not delete project: "+err.Error())
+	ctx, cancel := withOperationTimeout(ctx, r.client, state.Timeouts.Delete, &resp.Diagnostics)
+	defer cancel()
+	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	for _, module := range state.Modules {
+		if err := r.client.Delete(ctx, r.client.modulesPath()+"/"+url.PathEscape(module.ID.ValueString())); err != nil {
+			resp.Diagnostics.AddError("Error deleting module group", fmt.Sprintf("Could not delete module %q: %s", module.Name.ValueString(), err))
+		}
+	}
+}
+
+// ========== Operation Timeouts ==========
+
+// defaultOperationTimeout bounds a resource operation when neither its
+// timeouts block nor the provider's operation_timeout sets a limit. It leaves
+// room for the default build_timeout and deletion wait.
+const defaultOperationTimeout = time.Hour
+
+// operationTimeout returns the provider's limit for resource operations
+// whose timeouts block does not set one.
+func (c *NixernetesClient) operationTimeout() time.Duration {
+	if c.OperationTimeout > 0 {
+		return c.OperationTimeout
+	}
+	return defaultOperationTimeout
+}
+
+// withOperationTimeout bounds ctx by the timeout read from a timeouts block,
+// e.g. plan.Timeouts.Create, which falls back to the provider's
+// operation_timeout and then defaultOperationTimeout.
+func withOperationTimeout(ctx context.Context, client *NixernetesClient, timeout func(context.Context, time.Duration) (time.Duration, diag.Diagnostics), diags *diag.Diagnostics) (context.Context, context.CancelFunc) {
+	d, timeoutDiags := timeout(ctx, client.operationTimeout())
+	diags.Append(timeoutDiags...)
+	return context.WithTimeout(ctx, d)
 }
@@ -0,0 +1,3995 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// testResourceSchema returns the schema a resource reports to the framework.
+func testResourceSchema(t *testing.T, r resource.Resource) schema.Schema {
+	t.Helper()
+
+	var resp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected schema diagnostics: %v", resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+// testPlan builds a plan for the resource from a model value, or a destroy
+// plan when model is nil.
+func testPlan(t *testing.T, r resource.Resource, model interface{}) tfsdk.Plan {
+	t.Helper()
+
+	s := testResourceSchema(t, r)
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	if model == nil {
+		return plan
+	}
+	if diags := plan.Set(context.Background(), withNullTimeouts(s, model)); diags.HasError() {
+		t.Fatalf("Unexpected plan diagnostics: %v", diags)
+	}
+	return plan
+}
+
+// testState builds a state for the resource from a model value, or an empty
+// state when model is nil.
+func testState(t *testing.T, r resource.Resource, model interface{}) tfsdk.State {
+	t.Helper()
+
+	s := testResourceSchema(t, r)
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	if model == nil {
+		return state
+	}
+	if diags := state.Set(context.Background(), withNullTimeouts(s, model)); diags.HasError() {
+		t.Fatalf("Unexpected state diagnostics: %v", diags)
+	}
+	return state
+}
+
+// withNullTimeouts returns model with an unset timeouts block replaced by a
+// null object of the schema's type, as Terraform sends it when the block is
+// not configured. Other models are returned unchanged.
+func withNullTimeouts(s schema.Schema, model interface{}) interface{} {
+	block, ok := s.Blocks["timeouts"]
+	v := reflect.ValueOf(model)
+	if !ok || v.Kind() != reflect.Struct {
+		return model
+	}
+	field := v.FieldByName("Timeouts")
+	if !field.IsValid() || !field.IsZero() {
+		return model
+	}
+
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	attrTypes := block.Type().(timeouts.Type).AttrTypes
+	copied.FieldByName("Timeouts").Set(reflect.ValueOf(timeouts.Value{Object: types.ObjectNull(attrTypes)}))
+	return copied.Interface()
+}
+
+// testTimeouts builds a timeouts block for the resource with the given
+// operation timeouts set, e.g. {"read": "30s"}.
+func testTimeouts(t *testing.T, r resource.Resource, values map[string]string) timeouts.Value {
+	t.Helper()
+
+	attrTypes := testResourceSchema(t, r).Blocks["timeouts"].Type().(timeouts.Type).AttrTypes
+	attrs := make(map[string]attr.Value, len(attrTypes))
+	for name := range attrTypes {
+		attrs[name] = types.StringNull()
+		if value, ok := values[name]; ok {
+			attrs[name] = types.StringValue(value)
+		}
+	}
+	return timeouts.Value{Object: types.ObjectValueMust(attrTypes, attrs)}
+}
+
+// newUnreachableServer returns a server that fails the test if it receives any request.
+func newUnreachableServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestModuleResourceCreateDisabled(t *testing.T) {
+	server := newUnreachableServer(t)
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Unknown(),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringUnknown(),
+		Enabled:   types.BoolValue(false),
+		CreatedAt: types.StringUnknown(),
+	}
+
+	req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got NixernetesModuleModel
+	resp.State.Get(context.Background(), &got)
+	if !got.ID.IsNull() {
+		t.Errorf("Expected null id for disabled module, got %v", got.ID)
+	}
+	if got.Enabled.ValueBool() {
+		t.Error("Expected enabled to remain false")
+	}
+}
+
+func TestModuleResourceRejectsInvalidPlan(t *testing.T) {
+	server := newUnreachableServer(t)
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(200),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringUnknown(),
+		Enabled:   types.BoolValue(true),
+		CreatedAt: types.StringUnknown(),
+	}
+	state := plan
+	state.ID = types.StringValue("module-1")
+	state.Replicas = types.Int64Value(3)
+	state.Namespace = types.StringValue("default")
+	state.CreatedAt = types.StringValue("2024-02-04T00:00:00Z")
+
+	checkReplicasError := func(t *testing.T, diags diag.Diagnostics) {
+		t.Helper()
+		if !diags.HasError() {
+			t.Fatal("Expected 200 replicas to be rejected")
+		}
+		if summary := diags[0].Summary(); summary != "Invalid replicas" {
+			t.Errorf("Expected an invalid replicas error, got %q", summary)
+		}
+	}
+
+	t.Run("create", func(t *testing.T) {
+		req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+		resp := resource.CreateResponse{State: testState(t, r, nil)}
+		r.Create(context.Background(), req, &resp)
+		checkReplicasError(t, resp.Diagnostics)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req := resource.UpdateRequest{Plan: testPlan(t, r, plan), State: testState(t, r, state)}
+		resp := resource.UpdateResponse{State: testState(t, r, state)}
+		r.Update(context.Background(), req, &resp)
+		checkReplicasError(t, resp.Diagnostics)
+	})
+}
+
+func TestConfigResourceCreateRecordsIDEarly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "config-1",
+			"created_at": "2024-02-04T00:00:00Z",
+			"updated_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesConfigModel{
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+	}
+	state := testState(t, r, nil)
+
+	if err := r.createRemote(context.Background(), &plan, &state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Only the ID is in state until Create sets the full model.
+	var id, createdAt types.String
+	state.GetAttribute(context.Background(), path.Root("id"), &id)
+	state.GetAttribute(context.Background(), path.Root("created_at"), &createdAt)
+	if id.ValueString() != "config-1" {
+		t.Errorf("Expected id config-1 in state, got %v", id)
+	}
+	if !createdAt.IsNull() {
+		t.Errorf("Expected created_at to be unset, got %v", createdAt)
+	}
+}
+
+func TestConfigResourceServerAssignedName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":            "config-1",
+			"name":          "web-7f3a",
+			"configuration": "{ }",
+			"environment":   "staging",
+			"created_at":    "2024-02-04T00:00:00Z",
+			"updated_at":    "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesConfigModel{
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+		Environment:   types.StringValue("staging"),
+		Enabled:       types.BoolValue(true),
+	}
+	state := testState(t, r, nil)
+
+	if err := r.createRemote(context.Background(), &plan, &state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if plan.Name.ValueString() != "web" {
+		t.Errorf("Expected name to stay as planned, got %v", plan.Name)
+	}
+	if plan.EffectiveName.ValueString() != "web-7f3a" {
+		t.Errorf("Expected effective_name web-7f3a, got %v", plan.EffectiveName)
+	}
+
+	// Refreshing must not report the server-assigned name as drift.
+	req := resource.ReadRequest{State: testState(t, r, plan)}
+	resp := resource.ReadResponse{State: testState(t, r, plan)}
+	r.Read(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got NixernetesConfigModel
+	resp.State.Get(context.Background(), &got)
+	if got.Name.ValueString() != "web" || got.EffectiveName.ValueString() != "web-7f3a" {
+		t.Errorf("Expected name web and effective_name web-7f3a, got %v and %v", got.Name, got.EffectiveName)
+	}
+}
+
+func TestConfigResourceConfigurationBase64(t *testing.T) {
+	remote := "{ services.nginx.enable = true; }"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["configuration"] != "{ services.nginx.enable = true; }" {
+				t.Errorf("Expected decoded configuration in request body, got %v", body["configuration"])
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":            "config-1",
+			"name":          "web",
+			"configuration": remote,
+			"environment":   "staging",
+			"created_at":    "2024-02-04T00:00:00Z",
+			"updated_at":    "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesConfigModel{
+		Name:                 types.StringValue("web"),
+		ConfigurationBase64:  types.StringValue("eyBzZXJ2aWNlcy5uZ2lueC5lbmFibGUgPSB0cnVlOyB9"),
+		Environment:          types.StringValue("staging"),
+		Enabled:              types.BoolValue(true),
+		ConfigurationSummary: types.StringUnknown(),
+	}
+
+	createResp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: testPlan(t, r, plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", createResp.Diagnostics)
+	}
+	var created NixernetesConfigModel
+	createResp.State.Get(context.Background(), &created)
+	if created.ConfigurationSummary.ValueString() != "services.nginx" {
+		t.Errorf("Expected summary of the decoded configuration, got %v", created.ConfigurationSummary)
+	}
+
+	read := func() NixernetesConfigModel {
+		t.Helper()
+		resp := resource.ReadResponse{State: createResp.State}
+		r.Read(context.Background(), resource.ReadRequest{State: createResp.State}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		var got NixernetesConfigModel
+		resp.State.Get(context.Background(), &got)
+		return got
+	}
+
+	// Unchanged remote content keeps the encoded value and leaves configuration unset.
+	got := read()
+	if !got.Configuration.IsNull() || !got.ConfigurationBase64.Equal(plan.ConfigurationBase64) {
+		t.Errorf("Expected no drift, got configuration %v and configuration_base64 %v", got.Configuration, got.ConfigurationBase64)
+	}
+
+	// Remote changes show up as drift in configuration_base64.
+	remote = "{ }"
+	got = read()
+	if !got.Configuration.IsNull() || got.ConfigurationBase64.ValueString() != "eyB9" {
+		t.Errorf("Expected configuration_base64 eyB9, got configuration %v and configuration_base64 %v", got.Configuration, got.ConfigurationBase64)
+	}
+}
+
+func TestConfigResourceUpdateActive(t *testing.T) {
+	tests := []struct {
+		name        string
+		prior       bool
+		active      bool
+		force       bool
+		wantRequest string
+		wantError   string
+	}{
+		{"activate", false, true, false, "/configs/config-1/activate", ""},
+		{"deactivate", true, false, false, "/configs/config-1/deactivate", ""},
+		{"deactivate in use", true, false, false, "/configs/config-1/deactivate", "Configuration in use"},
+		{"force deactivate in use", true, false, true, "/configs/config-1/deactivate?force=true", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if strings.HasSuffix(r.URL.Path, "activate") {
+					requested = r.URL.RequestURI()
+					if tt.wantError != "" {
+						w.WriteHeader(http.StatusConflict)
+						json.NewEncoder(w).Encode(map[string]interface{}{"code": configInUseCode, "message": "used by modules api, worker"})
+						return
+					}
+					json.NewEncoder(w).Encode(map[string]interface{}{"active": tt.active})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"id": "config-1", "updated_at": "2024-02-05T00:00:00Z"})
+			}))
+			defer server.Close()
+
+			r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+			state := NixernetesConfigModel{
+				ID:                   types.StringValue("config-1"),
+				Name:                 types.StringValue("web"),
+				EffectiveName:        types.StringValue("web"),
+				Configuration:        types.StringValue("{ }"),
+				Environment:          types.StringValue("staging"),
+				Enabled:              types.BoolValue(true),
+				CreatedAt:            types.StringValue("2024-02-04T00:00:00Z"),
+				UpdatedAt:            types.StringValue("2024-02-04T00:00:00Z"),
+				VerifyBuild:          types.BoolValue(false),
+				Active:               types.BoolValue(tt.prior),
+				ForceDeactivate:      types.BoolValue(false),
+				ConfigurationSummary: types.StringNull(),
+			}
+			plan := state
+			plan.Active = types.BoolValue(tt.active)
+			plan.ForceDeactivate = types.BoolValue(tt.force)
+			plan.UpdatedAt = types.StringUnknown()
+
+			req := resource.UpdateRequest{Plan: testPlan(t, r, plan), State: testState(t, r, state)}
+			resp := resource.UpdateResponse{State: req.State}
+			r.Update(context.Background(), req, &resp)
+
+			if requested != tt.wantRequest {
+				t.Errorf("Expected request to %s, got %q", tt.wantRequest, requested)
+			}
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("Expected %q diagnostic, got %v", tt.wantError, resp.Diagnostics)
+				}
+				if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "force_deactivate") {
+					t.Errorf("Expected diagnostic to mention force_deactivate, got %q", detail)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			var got NixernetesConfigModel
+			resp.State.Get(context.Background(), &got)
+			if got.Active.ValueBool() != tt.active {
+				t.Errorf("Expected active %v, got %v", tt.active, got.Active)
+			}
+		})
+	}
+}
+
+func TestConfigResourceCreateStaged(t *testing.T) {
+	var sent interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent = body["active"]
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "config-1",
+			"active":     false,
+			"created_at": "2024-02-04T00:00:00Z",
+			"updated_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesConfigModel{
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+		Active:        types.BoolValue(false),
+	}
+	state := testState(t, r, nil)
+	if err := r.createRemote(context.Background(), &plan, &state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent != false {
+		t.Errorf("Expected active false in create body, got %v", sent)
+	}
+	if plan.Active.ValueBool() {
+		t.Errorf("Expected active false, got %v", plan.Active)
+	}
+}
+
+func TestConfigRequestBodyPriority(t *testing.T) {
+	plan := &NixernetesConfigModel{Name: types.StringValue("web"), Configuration: types.StringValue("{ }")}
+	if _, ok := configRequestBody(plan)["priority"]; ok {
+		t.Error("Expected unset priority to be omitted")
+	}
+
+	plan.Priority = types.Int64Value(50)
+	if got := configRequestBody(plan)["priority"]; got != int64(50) {
+		t.Errorf("Expected priority 50 in body, got %v", got)
+	}
+}
+
+func TestPriorityFromResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		prior    types.Int64
+		want     types.Int64
+	}{
+		{"reported", map[string]interface{}{"priority": float64(50)}, types.Int64Value(10), types.Int64Value(50)},
+		{"default keeps unset", map[string]interface{}{"priority": float64(0)}, types.Int64Null(), types.Int64Null()},
+		{"changed from unset", map[string]interface{}{"priority": float64(20)}, types.Int64Null(), types.Int64Value(20)},
+		{"reset to default", map[string]interface{}{"priority": float64(0)}, types.Int64Value(10), types.Int64Value(0)},
+		{"not reported", map[string]interface{}{}, types.Int64Value(10), types.Int64Value(10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := priorityFromResponse(tt.response, tt.prior); !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfigResourceValidateConfigurationBase64(t *testing.T) {
+	tests := []struct {
+		name          string
+		configuration types.String
+		encoded       types.String
+		wantErr       bool
+	}{
+		{"configuration only", types.StringValue("{ }"), types.StringNull(), false},
+		{"base64 only", types.StringNull(), types.StringValue("eyB9"), false},
+		{"both set", types.StringValue("{ }"), types.StringValue("eyB9"), true},
+		{"neither set", types.StringNull(), types.StringNull(), true},
+		{"not yet known", types.StringUnknown(), types.StringNull(), false},
+		{"invalid base64", types.StringNull(), types.StringValue("not base64!"), true},
+		{"empty after decoding", types.StringNull(), types.StringValue(""), true},
+		{"decoded syntax error", types.StringNull(), types.StringValue("eyBzZXJ2aWNlcy5uZ2lueC5lbmFibGUgPSB0cnVlOw=="), true},
+	}
+
+	r := &NixernetesConfigResource{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan(t, r, NixernetesConfigModel{
+				Name:                types.StringValue("web"),
+				Configuration:       tt.configuration,
+				ConfigurationBase64: tt.encoded,
+			})
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+			var resp resource.ValidateConfigResponse
+			for _, v := range r.ConfigValidators(context.Background()) {
+				v.ValidateResource(context.Background(), req, &resp)
+			}
+			r.ValidateConfig(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestConfigResourceValidateConfigSize(t *testing.T) {
+	r := &NixernetesConfigResource{client: &NixernetesClient{MaxConfigSize: 16}}
+
+	for _, model := range []NixernetesConfigModel{
+		{Name: types.StringValue("web"), Configuration: types.StringValue("{ services.nginx.enable = true; }"), ConfigurationBase64: types.StringNull()},
+		{Name: types.StringValue("web"), Configuration: types.StringNull(), ConfigurationBase64: types.StringValue("eyBzZXJ2aWNlcy5uZ2lueC5lbmFibGUgPSB0cnVlOyB9")},
+	} {
+		plan := testPlan(t, r, model)
+		req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+		var resp resource.ValidateConfigResponse
+		r.ValidateConfig(context.Background(), req, &resp)
+
+		if !resp.Diagnostics.HasError() {
+			t.Fatal("Expected size diagnostic")
+		}
+		if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "16 bytes") {
+			t.Errorf("Expected diagnostic to state the advertised limit, got %q", detail)
+		}
+	}
+}
+
+func TestConfigResourceModifyPlanDefaultEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultEnv  string
+		environment types.String
+		want        types.String
+	}{
+		{"default applied", "staging", types.StringNull(), types.StringValue("staging")},
+		{"configured environment kept", "staging", types.StringValue("production"), types.StringValue("production")},
+		{"no default", "", types.StringNull(), types.StringUnknown()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &NixernetesConfigResource{client: &NixernetesClient{DefaultEnvironment: tt.defaultEnv}}
+			config := NixernetesConfigModel{
+				Name:          types.StringValue("web"),
+				Configuration: types.StringValue("{ }"),
+				Environment:   tt.environment,
+			}
+			plan := config
+			if plan.Environment.IsNull() {
+				plan.Environment = types.StringUnknown()
+			}
+			configPlan := testPlan(t, r, config)
+			req := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: configPlan.Schema, Raw: configPlan.Raw},
+				Plan:   testPlan(t, r, plan),
+				State:  testState(t, r, nil),
+			}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var got types.String
+			resp.Plan.GetAttribute(context.Background(), path.Root("environment"), &got)
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected planned environment %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfigResourceCreateEnvironment(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultEnv  string
+		environment types.String
+		wantSent    interface{}
+		want        types.String
+	}{
+		{"explicit", "staging", types.StringValue("production"), "production", types.StringValue("production")},
+		{"defaulted", "staging", types.StringNull(), "staging", types.StringValue("staging")},
+		{"unset without default", "", types.StringNull(), nil, types.StringValue("development")},
+		{"configured casing differs", "", types.StringValue("Production"), "production", types.StringValue("Production")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&sent)
+				environment := "development"
+				if env, ok := sent["environment"].(string); ok {
+					environment = strings.ToLower(env)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":          "config-1",
+					"environment": environment,
+					"created_at":  "2024-02-04T00:00:00Z",
+					"updated_at":  "2024-02-04T00:00:00Z",
+				})
+			}))
+			defer server.Close()
+
+			r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL, DefaultEnvironment: tt.defaultEnv}}
+			config := NixernetesConfigModel{
+				Name:          types.StringValue("web"),
+				Configuration: types.StringValue("{ }"),
+				Environment:   tt.environment,
+				Enabled:       types.BoolValue(true),
+			}
+			plan := config
+			plan.ConfigurationSummary = types.StringUnknown()
+			if plan.Environment.IsNull() {
+				plan.Environment = types.StringUnknown()
+			}
+			configPlan := testPlan(t, r, config)
+			modifyReq := resource.ModifyPlanRequest{
+				Config: tfsdk.Config{Schema: configPlan.Schema, Raw: configPlan.Raw},
+				Plan:   testPlan(t, r, plan),
+				State:  testState(t, r, nil),
+			}
+			modifyResp := resource.ModifyPlanResponse{Plan: modifyReq.Plan}
+			r.ModifyPlan(context.Background(), modifyReq, &modifyResp)
+			if modifyResp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", modifyResp.Diagnostics)
+			}
+
+			resp := resource.CreateResponse{State: testState(t, r, nil)}
+			r.Create(context.Background(), resource.CreateRequest{Plan: modifyResp.Plan}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if sent["environment"] != tt.wantSent {
+				t.Errorf("Expected environment %v in the request, got %v", tt.wantSent, sent["environment"])
+			}
+
+			var got types.String
+			resp.State.GetAttribute(context.Background(), path.Root("environment"), &got)
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected environment %v in state, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEnvironmentFromResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		prior    types.String
+		want     types.String
+	}{
+		{"same casing", map[string]interface{}{"environment": "staging"}, types.StringValue("staging"), types.StringValue("staging")},
+		{"different casing", map[string]interface{}{"environment": "production"}, types.StringValue("Production"), types.StringValue("Production")},
+		{"changed outside terraform", map[string]interface{}{"environment": "staging"}, types.StringValue("Production"), types.StringValue("staging")},
+		{"picked by server", map[string]interface{}{"environment": "development"}, types.StringUnknown(), types.StringValue("development")},
+		{"imported", map[string]interface{}{"environment": "development"}, types.StringNull(), types.StringValue("development")},
+		{"remote casing normalized", map[string]interface{}{"environment": "Staging"}, types.StringNull(), types.StringValue("staging")},
+		{"not reported", map[string]interface{}{}, types.StringUnknown(), types.StringNull()},
+		{"not reported keeps prior", map[string]interface{}{}, types.StringValue("staging"), types.StringValue("staging")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := environmentFromResponse(tt.response, tt.prior); !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfigResourceReadEnvironmentCasing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":            "config-1",
+			"name":          "web",
+			"configuration": "{ }",
+			"environment":   "production",
+			"updated_at":    "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	prior := NixernetesConfigModel{
+		ID:            types.StringValue("config-1"),
+		Name:          types.StringValue("web"),
+		EffectiveName: types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+		Environment:   types.StringValue("Production"),
+		Enabled:       types.BoolValue(true),
+		UpdatedAt:     types.StringValue("2024-02-04T00:00:00Z"),
+	}
+
+	resp := resource.ReadResponse{State: testState(t, r, prior)}
+	r.Read(context.Background(), resource.ReadRequest{State: testState(t, r, prior)}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	// The refreshed state still matches the configuration, so the next plan
+	// has nothing to change.
+	if !resp.State.Raw.Equal(testState(t, r, prior).Raw) {
+		t.Errorf("Expected no diff after refresh, got state %v", resp.State.Raw)
+	}
+}
+
+func TestConfigResourceCreateVerifyBuildFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/build") {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":     "op-1",
+				"status": "failed",
+				"log":    "building...\nerror: attribute 'nginx' missing\n",
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "config-1",
+			"created_at": "2024-02-04T00:00:00Z",
+			"updated_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesConfigModel{
+		Name:                 types.StringValue("web"),
+		Configuration:        types.StringValue("{ services.nginx.enable = true; }"),
+		Environment:          types.StringValue("staging"),
+		Enabled:              types.BoolValue(true),
+		VerifyBuild:          types.BoolValue(true),
+		ConfigurationSummary: types.StringUnknown(),
+	}
+
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: testPlan(t, r, plan)}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected the failed build to fail the apply")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "attribute 'nginx' missing") {
+		t.Errorf("Expected the build log tail in the error, got %q", detail)
+	}
+
+	// The configuration was created, so it must be in state to be tainted.
+	var id types.String
+	resp.State.GetAttribute(context.Background(), path.Root("id"), &id)
+	if id.ValueString() != "config-1" {
+		t.Errorf("Expected id config-1 in state, got %v", id)
+	}
+}
+
+func TestNamesFromResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		remote         string
+		stateName      types.String
+		stateEffective types.String
+		wantName       string
+	}{
+		{"unchanged", "api", types.StringValue("api"), types.StringValue("api"), "api"},
+		{"assigned by server", "api-7f3a", types.StringValue("api"), types.StringValue("api-7f3a"), "api"},
+		{"renamed outside terraform", "other", types.StringValue("api"), types.StringValue("api-7f3a"), "other"},
+		{"imported", "api-7f3a", types.StringNull(), types.StringNull(), "api-7f3a"},
+		{"state before effective_name", "api", types.StringValue("web"), types.StringNull(), "api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, effective := namesFromResponse(tt.remote, tt.stateName, tt.stateEffective)
+			if name.ValueString() != tt.wantName {
+				t.Errorf("Expected name %q, got %v", tt.wantName, name)
+			}
+			if effective.ValueString() != tt.remote {
+				t.Errorf("Expected effective name %q, got %v", tt.remote, effective)
+			}
+		})
+	}
+}
+
+func TestConfigResourceCreateServerSchema(t *testing.T) {
+	schema, err := compileConfigSchema(map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"services"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+
+	server := newUnreachableServer(t)
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL, ConfigSchema: schema}}
+	plan := NixernetesConfigModel{
+		ID:            types.StringUnknown(),
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue(`{"networking": {}}`),
+		Environment:   types.StringValue("staging"),
+		Enabled:       types.BoolValue(true),
+		CreatedAt:     types.StringUnknown(),
+		UpdatedAt:     types.StringUnknown(),
+	}
+
+	req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected the configuration to fail server schema validation")
+	}
+	if detail := resp.Diagnostics[0].Detail(); !strings.Contains(detail, "/: ") {
+		t.Errorf("Expected error to point at the document root, got %q", detail)
+	}
+}
+
+func TestConfigResourceCustomPath(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "config-1",
+			"created_at": "2024-02-04T00:00:00Z",
+			"updated_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL, ConfigsPath: "/v2/configurations"}}
+	plan := NixernetesConfigModel{
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+	}
+	state := testState(t, r, nil)
+
+	if err := r.createRemote(context.Background(), &plan, &state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	deleteReq := resource.DeleteRequest{State: testState(t, r, plan)}
+	r.Delete(context.Background(), deleteReq, &resource.DeleteResponse{})
+
+	if want := "POST /v2/configurations,DELETE /v2/configurations/config-1"; strings.Join(requests, ",") != want {
+		t.Errorf("Expected requests %s, got %v", want, requests)
+	}
+}
+
+func TestConfigResourceCreateMissingID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"created_at":"2024-02-04T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesConfigModel{
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+	}
+	state := testState(t, r, nil)
+
+	err := r.createRemote(context.Background(), &plan, &state)
+	if err == nil || !strings.Contains(err.Error(), "id") {
+		t.Errorf("Expected missing id error, got %v", err)
+	}
+}
+
+func TestResourcesPartialResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/configs/cfg-1":
+			w.Write([]byte(`{"id":"cfg-1","name":"web","configuration":"{ }","environment":null}`))
+		case r.URL.Path == "/projects/proj-1":
+			w.Write([]byte(`{"id":"proj-1","name":"shop"}`))
+		case r.URL.Path == "/modules/mod-1":
+			w.Write([]byte(`{"id":"mod-1","name":"api","replicas":2}`))
+		default:
+			w.Write([]byte(`{"id":"new-1"}`))
+		}
+	}))
+	defer server.Close()
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	t.Run("config read", func(t *testing.T) {
+		r := &NixernetesConfigResource{client: client}
+		prior := NixernetesConfigModel{
+			ID:            types.StringValue("cfg-1"),
+			Name:          types.StringValue("web"),
+			Configuration: types.StringValue("{ }"),
+		}
+		resp := resource.ReadResponse{State: testState(t, r, prior)}
+		r.Read(context.Background(), resource.ReadRequest{State: testState(t, r, prior)}, &resp)
+		if !resp.Diagnostics.HasError() || !strings.HasSuffix(resp.Diagnostics[0].Detail(), "field(s) updated_at") {
+			t.Errorf("Expected an error naming only updated_at, got %v", resp.Diagnostics)
+		}
+	})
+
+	t.Run("project read", func(t *testing.T) {
+		r := &NixernetesProjectResource{client: client}
+		prior := NixernetesProjectModel{
+			ID:      types.StringValue("proj-1"),
+			Name:    types.StringValue("shop"),
+			Enabled: types.BoolValue(true),
+		}
+		resp := resource.ReadResponse{State: testState(t, r, prior)}
+		r.Read(context.Background(), resource.ReadRequest{State: testState(t, r, prior)}, &resp)
+		if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "status, updated_at") {
+			t.Errorf("Expected an error naming status and updated_at, got %v", resp.Diagnostics)
+		}
+	})
+
+	t.Run("module read", func(t *testing.T) {
+		r := &NixernetesModuleResource{client: client}
+		prior := NixernetesModuleModel{
+			ID:        types.StringValue("mod-1"),
+			Name:      types.StringValue("api"),
+			Replicas:  types.Int64Value(2),
+			Image:     types.StringValue("nginx:latest"),
+			Namespace: types.StringValue("default"),
+			Enabled:   types.BoolValue(true),
+		}
+		resp := resource.ReadResponse{State: testState(t, r, prior)}
+		r.Read(context.Background(), resource.ReadRequest{State: testState(t, r, prior)}, &resp)
+		if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "image, namespace") {
+			t.Errorf("Expected an error naming image and namespace, got %v", resp.Diagnostics)
+		}
+	})
+
+	t.Run("config create", func(t *testing.T) {
+		r := &NixernetesConfigResource{client: client}
+		plan := NixernetesConfigModel{Name: types.StringValue("web"), Configuration: types.StringValue("{ }")}
+		state := testState(t, r, nil)
+		err := r.createRemote(context.Background(), &plan, &state)
+		if err == nil || !strings.Contains(err.Error(), "created_at, updated_at") {
+			t.Errorf("Expected an error naming created_at and updated_at, got %v", err)
+		}
+	})
+
+	t.Run("project create", func(t *testing.T) {
+		r := &NixernetesProjectResource{client: client}
+		plan := NixernetesProjectModel{Name: types.StringValue("shop")}
+		state := testState(t, r, nil)
+		err := r.createRemote(context.Background(), &plan, &state)
+		if err == nil || !strings.Contains(err.Error(), "status, created_at, updated_at") {
+			t.Errorf("Expected an error naming status, created_at and updated_at, got %v", err)
+		}
+	})
+}
+
+func TestModuleResourceToggleEnabled(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == "POST" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":         "mod-2",
+				"created_at": "2024-02-04T00:00:00Z",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	enabled := NixernetesModuleModel{
+		ID:        types.StringValue("mod-1"),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(2),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringValue("default"),
+		Enabled:   types.BoolValue(true),
+		CreatedAt: types.StringValue("2024-02-03T00:00:00Z"),
+	}
+	disabled := enabled
+	disabled.ID = types.StringUnknown()
+	disabled.CreatedAt = types.StringUnknown()
+	disabled.Enabled = types.BoolValue(false)
+
+	// Flipping to false deletes the module and clears its remote attributes.
+	req := resource.UpdateRequest{Plan: testPlan(t, r, disabled), State: testState(t, r, enabled)}
+	resp := resource.UpdateResponse{State: testState(t, r, nil)}
+	r.Update(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got NixernetesModuleModel
+	resp.State.Get(context.Background(), &got)
+	if !got.ID.IsNull() || !got.CreatedAt.IsNull() {
+		t.Errorf("Expected remote attributes to be cleared, got id=%v created_at=%v", got.ID, got.CreatedAt)
+	}
+
+	// Flipping back to true creates it again.
+	reenabled := enabled
+	reenabled.ID = types.StringUnknown()
+	reenabled.CreatedAt = types.StringUnknown()
+
+	req = resource.UpdateRequest{Plan: testPlan(t, r, reenabled), State: resp.State}
+	resp = resource.UpdateResponse{State: testState(t, r, nil)}
+	r.Update(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	resp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "mod-2" {
+		t.Errorf("Expected id 'mod-2' after re-enabling, got %v", got.ID)
+	}
+
+	want := []string{"DELETE /modules/mod-1", "POST /modules"}
+	if len(requests) != len(want) {
+		t.Fatalf("Expected requests %v, got %v", want, requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("Expected request %d to be %q, got %q", i, want[i], requests[i])
+		}
+	}
+}
+
+func TestModuleResourceDeleteDisabled(t *testing.T) {
+	server := newUnreachableServer(t)
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	state := NixernetesModuleModel{
+		ID:        types.StringNull(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Null(),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringNull(),
+		Enabled:   types.BoolValue(false),
+		CreatedAt: types.StringNull(),
+	}
+
+	req := resource.DeleteRequest{State: testState(t, r, state)}
+	resp := resource.DeleteResponse{State: testState(t, r, state)}
+	r.Delete(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestModuleResourceReadVolumes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server reports volumes in a different order than configured
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":        "mod-1",
+			"name":      "db",
+			"replicas":  1,
+			"image":     "postgres:16",
+			"namespace": "default",
+			"volumes": []map[string]interface{}{
+				{"name": "scratch", "type": "emptyDir"},
+				{"name": "data", "type": "pvc", "size": "10Gi"},
+			},
+			"volumeMounts": []map[string]interface{}{
+				{"name": "data", "mountPath": "/var/lib/postgresql", "readOnly": false},
+			},
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	prior := NixernetesModuleModel{
+		ID:            types.StringValue("mod-1"),
+		Name:          types.StringValue("db"),
+		EffectiveName: types.StringValue("db"),
+		Replicas:      types.Int64Value(1),
+		Image:         types.StringValue("postgres:16"),
+		Namespace:     types.StringValue("default"),
+		Volumes: []NixernetesVolumeModel{
+			{Name: types.StringValue("data"), Type: types.StringValue("pvc"), Size: types.StringValue("10Gi")},
+			{Name: types.StringValue("scratch"), Type: types.StringValue("emptyDir"), Size: types.StringNull()},
+		},
+		VolumeMounts: []NixernetesVolumeMountModel{
+			{Name: types.StringValue("data"), MountPath: types.StringValue("/var/lib/postgresql"), ReadOnly: types.BoolNull()},
+		},
+		Enabled:         types.BoolValue(true),
+		CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas: types.Int64Value(1),
+		RestartCount:    types.Int64Value(0),
+	}
+
+	req := resource.ReadRequest{State: testState(t, r, prior)}
+	resp := resource.ReadResponse{State: testState(t, r, prior)}
+	r.Read(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	// Volumes are a set, so the refreshed state must equal the prior state
+	// regardless of the order the server returned them in.
+	if !resp.State.Raw.Equal(req.State.Raw) {
+		t.Errorf("Expected refreshed state to match prior state, got %v", resp.State.Raw)
+	}
+}
+
+func TestModuleResourceServiceAccount(t *testing.T) {
+	var sent interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			sent = body["service_account"]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":              "mod-1",
+			"name":            "api",
+			"replicas":        1,
+			"image":           "nginx:latest",
+			"namespace":       "default",
+			"service_account": "default",
+			"created_at":      "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	// Unset, the server's default service account is recorded.
+	plan := NixernetesModuleModel{
+		Name:           types.StringValue("api"),
+		Image:          types.StringValue("nginx:latest"),
+		Replicas:       types.Int64Value(1),
+		Namespace:      types.StringValue("default"),
+		ServiceAccount: types.StringUnknown(),
+	}
+	created := testState(t, r, nil)
+	if err := r.createRemote(context.Background(), &plan, &created); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent != nil {
+		t.Errorf("Expected no service_account in request body, got %v", sent)
+	}
+	if plan.ServiceAccount.ValueString() != "default" {
+		t.Errorf("Expected service_account default, got %v", plan.ServiceAccount)
+	}
+
+	// Reading back reports a service account changed outside Terraform.
+	state := plan
+	state.ServiceAccount = types.StringValue("api-reader")
+	if _, err := r.readRemote(context.Background(), &state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state.ServiceAccount.ValueString() != "default" {
+		t.Errorf("Expected service_account default after read, got %v", state.ServiceAccount)
+	}
+}
+
+func TestModuleResourceReadRestarts(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      map[string]interface{}
+		statusCode  int
+		wantCount   types.Int64
+		wantReason  types.String
+		wantWarning bool
+	}{
+		{
+			name:       "crash looping",
+			status:     map[string]interface{}{"phase": "running", "restart_count": 7, "last_restart_reason": "OOMKilled"},
+			statusCode: http.StatusOK,
+			wantCount:  types.Int64Value(7),
+			wantReason: types.StringValue("OOMKilled"),
+		},
+		{
+			name:       "never restarted",
+			status:     map[string]interface{}{"phase": "ready"},
+			statusCode: http.StatusOK,
+			wantCount:  types.Int64Value(0),
+			wantReason: types.StringNull(),
+		},
+		{
+			name:       "no status endpoint",
+			statusCode: http.StatusNotFound,
+			wantCount:  types.Int64Null(),
+			wantReason: types.StringNull(),
+		},
+		{
+			name:        "status unavailable",
+			statusCode:  http.StatusBadRequest,
+			wantCount:   types.Int64Value(2),
+			wantReason:  types.StringValue("Error"),
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if strings.HasSuffix(r.URL.Path, "/status") {
+					w.WriteHeader(tt.statusCode)
+					json.NewEncoder(w).Encode(tt.status)
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":        "mod-1",
+					"name":      "api",
+					"replicas":  1,
+					"image":     "nginx:latest",
+					"namespace": "default",
+				})
+			}))
+			defer server.Close()
+
+			r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+			prior := NixernetesModuleModel{
+				ID:                types.StringValue("mod-1"),
+				Name:              types.StringValue("api"),
+				Image:             types.StringValue("nginx:latest"),
+				Enabled:           types.BoolValue(true),
+				RestartCount:      types.Int64Value(2),
+				LastRestartReason: types.StringValue("Error"),
+			}
+
+			req := resource.ReadRequest{State: testState(t, r, prior)}
+			resp := resource.ReadResponse{State: testState(t, r, prior)}
+			r.Read(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if got := resp.Diagnostics.WarningsCount() > 0; got != tt.wantWarning {
+				t.Errorf("Expected warning %v, got %v", tt.wantWarning, resp.Diagnostics)
+			}
+
+			var got NixernetesModuleModel
+			resp.State.Get(context.Background(), &got)
+			if !got.RestartCount.Equal(tt.wantCount) || !got.LastRestartReason.Equal(tt.wantReason) {
+				t.Errorf("Expected restart_count %v and last_restart_reason %v, got %v and %v",
+					tt.wantCount, tt.wantReason, got.RestartCount, got.LastRestartReason)
+			}
+		})
+	}
+}
+
+func TestModuleResourceReadProjectID(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		want     types.String
+	}{
+		{"moved by server", map[string]interface{}{"project_id": "proj-2"}, types.StringValue("proj-2")},
+		{"removed from project", map[string]interface{}{"project_id": ""}, types.StringNull()},
+		{"not reported", map[string]interface{}{}, types.StringValue("proj-1")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := map[string]interface{}{
+					"id":        "mod-1",
+					"name":      "api",
+					"replicas":  1,
+					"image":     "nginx:latest",
+					"namespace": "default",
+				}
+				for k, v := range tt.response {
+					response[k] = v
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+			prior := NixernetesModuleModel{
+				ID:        types.StringValue("mod-1"),
+				Name:      types.StringValue("api"),
+				Replicas:  types.Int64Value(1),
+				Image:     types.StringValue("nginx:latest"),
+				Namespace: types.StringValue("default"),
+				ProjectID: types.StringValue("proj-1"),
+				Enabled:   types.BoolValue(true),
+				CreatedAt: types.StringValue("2024-02-04T00:00:00Z"),
+			}
+
+			req := resource.ReadRequest{State: testState(t, r, prior)}
+			resp := resource.ReadResponse{State: testState(t, r, prior)}
+			r.Read(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var got NixernetesModuleModel
+			resp.State.Get(context.Background(), &got)
+			if !got.ProjectID.Equal(tt.want) {
+				t.Errorf("Expected project_id %v, got %v", tt.want, got.ProjectID)
+			}
+		})
+	}
+}
+
+func TestModuleResourceReadUnexpectedType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"mod-1","name":"api","replicas":"two","image":"nginx:latest","namespace":"default"}`))
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	prior := NixernetesModuleModel{
+		ID:        types.StringValue("mod-1"),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(1),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringValue("default"),
+		Enabled:   types.BoolValue(true),
+	}
+
+	// A replica count of the wrong type is an error, not a panic
+	req := resource.ReadRequest{State: testState(t, r, prior)}
+	resp := resource.ReadResponse{State: testState(t, r, prior)}
+	r.Read(context.Background(), req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected error for a non-numeric replica count")
+	}
+}
+
+func TestModuleRequestBodyVolumes(t *testing.T) {
+	plan := &NixernetesModuleModel{
+		Name:      types.StringValue("db"),
+		Replicas:  types.Int64Value(1),
+		Image:     types.StringValue("postgres:16"),
+		Namespace: types.StringValue("default"),
+		Volumes: []NixernetesVolumeModel{
+			{Name: types.StringValue("data"), Type: types.StringValue("pvc"), Size: types.StringValue("10Gi")},
+		},
+		VolumeMounts: []NixernetesVolumeMountModel{
+			{Name: types.StringValue("data"), MountPath: types.StringValue("/data"), ReadOnly: types.BoolValue(true)},
+		},
+	}
+
+	body := moduleRequestBody(plan)
+
+	volumes, ok := body["volumes"].([]map[string]interface{})
+	if !ok || len(volumes) != 1 || volumes[0]["size"] != "10Gi" {
+		t.Errorf("Unexpected volumes in body: %v", body["volumes"])
+	}
+	mounts, ok := body["volumeMounts"].([]map[string]interface{})
+	if !ok || len(mounts) != 1 || mounts[0]["mountPath"] != "/data" || mounts[0]["readOnly"] != true {
+		t.Errorf("Unexpected volumeMounts in body: %v", body["volumeMounts"])
+	}
+
+	// Unset volume attributes are left out of the body entirely
+	body = moduleRequestBody(&NixernetesModuleModel{Name: types.StringValue("api")})
+	if _, ok := body["volumes"]; ok {
+		t.Error("Expected no volumes key when volumes are unset")
+	}
+	if _, ok := body["autoscaling"]; ok {
+		t.Error("Expected no autoscaling key when autoscaling is unset")
+	}
+}
+
+func TestRequestBodiesOmitUnsetFields(t *testing.T) {
+	config := configRequestBody(&NixernetesConfigModel{
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+		Environment:   types.StringUnknown(),
+	})
+	if _, ok := config["environment"]; ok {
+		t.Errorf("Expected no environment key in config body, got %v", config)
+	}
+
+	module := moduleRequestBody(&NixernetesModuleModel{
+		Name:      types.StringValue("api"),
+		Image:     types.StringValue("nginx:latest"),
+		Replicas:  types.Int64Unknown(),
+		Namespace: types.StringUnknown(),
+	})
+	for _, key := range []string{"replicas", "namespace", "environment", "project_id", "service_account", "platform", "termination_grace_period_seconds"} {
+		if _, ok := module[key]; ok {
+			t.Errorf("Expected no %s key in module body, got %v", key, module)
+		}
+	}
+
+	project := projectRequestBody(&NixernetesProjectModel{Name: types.StringValue("shop")})
+	if len(project) != 1 || project["name"] != "shop" {
+		t.Errorf("Expected only name in project body, got %v", project)
+	}
+
+	// Set fields are sent, including explicit empty strings
+	project = projectRequestBody(&NixernetesProjectModel{
+		Name:        types.StringValue("shop"),
+		Description: types.StringValue(""),
+	})
+	if description, ok := project["description"]; !ok || description != "" {
+		t.Errorf("Expected empty description in project body, got %v", project)
+	}
+}
+
+func TestModuleRequestBodyAutoscaling(t *testing.T) {
+	plan := &NixernetesModuleModel{
+		Name: types.StringValue("api"),
+		Autoscaling: &NixernetesAutoscalingModel{
+			MinReplicas:          types.Int64Value(2),
+			MaxReplicas:          types.Int64Value(10),
+			TargetCPUUtilization: types.Int64Null(),
+		},
+	}
+
+	autoscaling, ok := moduleRequestBody(plan)["autoscaling"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected autoscaling in body")
+	}
+	if autoscaling["minReplicas"] != int64(2) || autoscaling["maxReplicas"] != int64(10) {
+		t.Errorf("Unexpected autoscaling bounds: %v", autoscaling)
+	}
+	if _, ok := autoscaling["targetCPUUtilizationPercentage"]; ok {
+		t.Error("Expected unset target to be omitted")
+	}
+}
+
+func TestModuleRequestBodyNetworkPolicies(t *testing.T) {
+	plan := &NixernetesModuleModel{
+		Name: types.StringValue("api"),
+		NetworkPolicies: &NixernetesNetworkPoliciesModel{
+			Ingress: []NixernetesNetworkPolicyRuleModel{{
+				Ports:       []types.Int64{types.Int64Value(8080)},
+				Protocol:    types.StringNull(),
+				PodSelector: types.MapValueMust(types.StringType, map[string]attr.Value{"app": types.StringValue("web")}),
+			}},
+		},
+	}
+
+	policies, ok := moduleRequestBody(plan)["networkPolicies"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected networkPolicies in body")
+	}
+	ingress := policies["ingress"].([]map[string]interface{})
+	if len(ingress) != 1 {
+		t.Fatalf("Expected 1 ingress rule, got %v", ingress)
+	}
+	if ports := ingress[0]["ports"].([]int64); len(ports) != 1 || ports[0] != 8080 {
+		t.Errorf("Unexpected ports: %v", ingress[0]["ports"])
+	}
+	if selector := ingress[0]["podSelector"].(map[string]string); selector["app"] != "web" {
+		t.Errorf("Unexpected pod selector: %v", selector)
+	}
+	if _, ok := ingress[0]["protocol"]; ok {
+		t.Error("Expected unset protocol to be omitted")
+	}
+	if egress := policies["egress"].([]map[string]interface{}); len(egress) != 0 {
+		t.Errorf("Expected no egress rules, got %v", egress)
+	}
+}
+
+func TestModuleResourceReadNetworkPolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server fills in the default protocol for rules without one
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":        "mod-1",
+			"name":      "api",
+			"replicas":  1,
+			"image":     "nginx:latest",
+			"namespace": "default",
+			"networkPolicies": map[string]interface{}{
+				"ingress": []map[string]interface{}{
+					{"ports": []int{8080}, "protocol": "TCP", "podSelector": map[string]string{"app": "web"}},
+				},
+				"egress": []map[string]interface{}{
+					{"ports": []int{53}, "protocol": "UDP", "cidrs": []string{"10.0.0.0/8"}},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	prior := NixernetesModuleModel{
+		ID:            types.StringValue("mod-1"),
+		Name:          types.StringValue("api"),
+		EffectiveName: types.StringValue("api"),
+		Replicas:      types.Int64Value(1),
+		Image:         types.StringValue("nginx:latest"),
+		Namespace:     types.StringValue("default"),
+		NetworkPolicies: &NixernetesNetworkPoliciesModel{
+			Ingress: []NixernetesNetworkPolicyRuleModel{{
+				Ports:       []types.Int64{types.Int64Value(8080)},
+				Protocol:    types.StringNull(),
+				PodSelector: types.MapValueMust(types.StringType, map[string]attr.Value{"app": types.StringValue("web")}),
+			}},
+			Egress: []NixernetesNetworkPolicyRuleModel{{
+				Ports:       []types.Int64{types.Int64Value(53)},
+				Protocol:    types.StringValue("UDP"),
+				CIDRs:       []types.String{types.StringValue("10.0.0.0/8")},
+				PodSelector: types.MapNull(types.StringType),
+			}},
+		},
+		Enabled:         types.BoolValue(true),
+		CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas: types.Int64Value(1),
+		RestartCount:    types.Int64Value(0),
+	}
+
+	req := resource.ReadRequest{State: testState(t, r, prior)}
+	resp := resource.ReadResponse{State: testState(t, r, prior)}
+	r.Read(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if !resp.State.Raw.Equal(req.State.Raw) {
+		t.Errorf("Expected refreshed state to match prior state, got %v", resp.State.Raw)
+	}
+}
+
+func TestModuleResourceCreateQuotaExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusPaymentRequired)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "requested cpu 6 exceeds quota 4"})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(6),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringValue("team-payments"),
+		Enabled:   types.BoolValue(true),
+		CreatedAt: types.StringUnknown(),
+	}
+
+	req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected quota diagnostic")
+	}
+	if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Resource quota exceeded" {
+		t.Errorf("Expected 'Resource quota exceeded' diagnostic, got %q", summary)
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "team-payments") {
+		t.Errorf("Expected diagnostic to name the namespace, got %q", detail)
+	}
+}
+
+func TestModuleResourcePlatform(t *testing.T) {
+	var sent interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			sent = body["platform"]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "mod-1",
+			"name":       "api",
+			"replicas":   1,
+			"image":      "nginx:latest",
+			"namespace":  "default",
+			"platform":   "linux/arm64",
+			"created_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		Name:      types.StringValue("api"),
+		Image:     types.StringValue("nginx:latest"),
+		Replicas:  types.Int64Value(1),
+		Namespace: types.StringValue("default"),
+		Platform:  types.StringValue("linux/arm64"),
+	}
+	created := testState(t, r, nil)
+	if err := r.createRemote(context.Background(), &plan, &created); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent != "linux/arm64" {
+		t.Errorf("Expected platform linux/arm64 in request body, got %v", sent)
+	}
+
+	// Reading back reports the platform the server stored.
+	state := plan
+	state.Platform = types.StringNull()
+	if _, err := r.readRemote(context.Background(), &state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state.Platform.ValueString() != "linux/arm64" {
+		t.Errorf("Expected platform linux/arm64 after read, got %v", state.Platform)
+	}
+}
+
+func TestModuleResourceTerminationGracePeriod(t *testing.T) {
+	var sent interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			sent = body["termination_grace_period_seconds"]
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":                               "mod-1",
+			"name":                             "db",
+			"replicas":                         1,
+			"image":                            "postgres:16",
+			"namespace":                        "default",
+			"termination_grace_period_seconds": 300,
+			"created_at":                       "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		Name:                          types.StringValue("db"),
+		Image:                         types.StringValue("postgres:16"),
+		Replicas:                      types.Int64Value(1),
+		Namespace:                     types.StringValue("default"),
+		TerminationGracePeriodSeconds: types.Int64Value(300),
+	}
+	created := testState(t, r, nil)
+	if err := r.createRemote(context.Background(), &plan, &created); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent != float64(300) {
+		t.Errorf("Expected termination_grace_period_seconds 300 in request body, got %v", sent)
+	}
+
+	state := plan
+	state.TerminationGracePeriodSeconds = types.Int64Null()
+	if _, err := r.readRemote(context.Background(), &state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state.TerminationGracePeriodSeconds.ValueInt64() != 300 {
+		t.Errorf("Expected termination_grace_period_seconds 300 after read, got %v", state.TerminationGracePeriodSeconds)
+	}
+}
+
+func TestModuleDeletionTimeout(t *testing.T) {
+	if got := moduleDeletionTimeout(types.Int64Null()); got != defaultDeletionTimeout {
+		t.Errorf("Expected %s without a grace period, got %s", defaultDeletionTimeout, got)
+	}
+	if got := moduleDeletionTimeout(types.Int64Value(600)); got != defaultDeletionTimeout+10*time.Minute {
+		t.Errorf("Expected the grace period added to the timeout, got %s", got)
+	}
+}
+
+func TestModuleResourceCreatePlatformMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"code":    platformMismatchCode,
+			"message": "digest sha256:3c1f is linux/amd64",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(1),
+		Image:     types.StringValue("nginx@sha256:3c1f0a3b8e6d4f2c9a7b5e1d0c8f6a4b2e9d7c5a3f1b8e6d4c2a0f9e7d5b3c1a"),
+		Namespace: types.StringValue("default"),
+		Platform:  types.StringValue("linux/arm64"),
+		Enabled:   types.BoolValue(true),
+		CreatedAt: types.StringUnknown(),
+	}
+
+	req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected platform mismatch diagnostic")
+	}
+	if summary := resp.Diagnostics.Errors()[0].Summary(); summary != "Image digest does not match platform" {
+		t.Errorf("Expected 'Image digest does not match platform' diagnostic, got %q", summary)
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, "linux/arm64") {
+		t.Errorf("Expected diagnostic to name the platform, got %q", detail)
+	}
+}
+
+func TestModuleResourceCreateFieldErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": []map[string]string{
+				{"field": "replicas", "message": "must not exceed 50"},
+				{"field": "image", "message": "repository not found"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(60),
+		Image:     types.StringValue("registry.example.com/api:1.0"),
+		Namespace: types.StringValue("default"),
+		Enabled:   types.BoolValue(true),
+		CreatedAt: types.StringUnknown(),
+	}
+
+	req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), req, &resp)
+
+	errs := resp.Diagnostics.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("Expected one diagnostic per field error, got %v", resp.Diagnostics)
+	}
+	for i, want := range []struct {
+		path    path.Path
+		message string
+	}{
+		{path.Root("replicas"), "must not exceed 50"},
+		{path.Root("image"), "repository not found"},
+	} {
+		withPath, ok := errs[i].(diag.DiagnosticWithPath)
+		if !ok || !withPath.Path().Equal(want.path) {
+			t.Errorf("Expected diagnostic %d at %s, got %v", i, want.path, errs[i])
+		}
+		if !strings.Contains(errs[i].Detail(), want.message) {
+			t.Errorf("Expected diagnostic %d to contain %q, got %q", i, want.message, errs[i].Detail())
+		}
+	}
+}
+
+func TestModuleResourceCreateInheritsProjectNamespace(t *testing.T) {
+	var createBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /projects/proj-1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "proj-1", "default_namespace": "team-shop"})
+		case "POST /modules":
+			json.NewDecoder(r.Body).Decode(&createBody)
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "mod-1", "created_at": "2024-02-04T00:00:00Z"})
+		default:
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(1),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringUnknown(),
+		ProjectID: types.StringValue("proj-1"),
+		Enabled:   types.BoolValue(true),
+		CreatedAt: types.StringUnknown(),
+	}
+
+	req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if createBody["namespace"] != "team-shop" {
+		t.Errorf("Expected module to be created in team-shop, got %v", createBody["namespace"])
+	}
+	if createBody["project_id"] != "proj-1" {
+		t.Errorf("Expected project_id proj-1 in create body, got %v", createBody["project_id"])
+	}
+
+	var got NixernetesModuleModel
+	resp.State.Get(context.Background(), &got)
+	if got.Namespace.ValueString() != "team-shop" {
+		t.Errorf("Expected namespace team-shop in state, got %v", got.Namespace)
+	}
+}
+
+func TestModuleResourceCreateDefaultReplicas(t *testing.T) {
+	var createBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		createBody = nil
+		json.NewDecoder(r.Body).Decode(&createBody)
+		replicas, ok := createBody["replicas"]
+		if !ok {
+			replicas = 1 // the server's default
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "mod-1", "replicas": replicas, "created_at": "2024-02-04T00:00:00Z"})
+	}))
+	defer server.Close()
+
+	three := int64(3)
+	tests := []struct {
+		name            string
+		replicas        types.Int64
+		defaultReplicas *int64
+		wantSent        interface{}
+		want            int64
+	}{
+		{"replicas set", types.Int64Value(5), &three, float64(5), 5},
+		{"replicas omitted", types.Int64Unknown(), &three, float64(3), 3},
+		{"no default", types.Int64Unknown(), nil, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL, DefaultReplicas: tt.defaultReplicas}}
+			plan := NixernetesModuleModel{
+				ID:        types.StringUnknown(),
+				Name:      types.StringValue("api"),
+				Replicas:  tt.replicas,
+				Image:     types.StringValue("nginx:latest"),
+				Namespace: types.StringValue("default"),
+				Enabled:   types.BoolValue(true),
+				CreatedAt: types.StringUnknown(),
+			}
+
+			req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+			resp := resource.CreateResponse{State: testState(t, r, nil)}
+			r.Create(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if createBody["replicas"] != tt.wantSent {
+				t.Errorf("Expected replicas %v in create body, got %v", tt.wantSent, createBody["replicas"])
+			}
+			var got NixernetesModuleModel
+			resp.State.Get(context.Background(), &got)
+			if !got.Replicas.Equal(types.Int64Value(tt.want)) {
+				t.Errorf("Expected replicas %d in state, got %v", tt.want, got.Replicas)
+			}
+		})
+	}
+}
+
+func TestModuleResourceCreateMissingProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":"project not found"}`))
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(1),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringUnknown(),
+		ProjectID: types.StringValue("proj-missing"),
+		Enabled:   types.BoolValue(true),
+		CreatedAt: types.StringUnknown(),
+	}
+
+	req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), req, &resp)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected error for missing project")
+	}
+	if detail := resp.Diagnostics.Errors()[0].Detail(); !strings.Contains(detail, `project "proj-missing" does not exist`) {
+		t.Errorf("Expected diagnostic to name the missing project, got %q", detail)
+	}
+}
+
+func TestResourceQuotaResourceLifecycle(t *testing.T) {
+	var requests []string
+	var lastBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "PUT":
+			json.NewDecoder(r.Body).Decode(&lastBody)
+			json.NewEncoder(w).Encode(lastBody)
+		case "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"cpu": "4", "memory": "8Gi", "pods": 20})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	r := &NixernetesResourceQuotaResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesResourceQuotaModel{
+		ID:        types.StringUnknown(),
+		Namespace: types.StringValue("team-payments"),
+		CPU:       types.StringValue("4"),
+		Memory:    types.StringNull(),
+		Pods:      types.Int64Value(20),
+	}
+
+	createResp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: testPlan(t, r, plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", createResp.Diagnostics)
+	}
+	if _, ok := lastBody["memory"]; ok {
+		t.Errorf("Expected unset memory to be omitted, got body %v", lastBody)
+	}
+
+	var got NixernetesResourceQuotaModel
+	createResp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "team-payments" {
+		t.Errorf("Expected id 'team-payments', got %v", got.ID)
+	}
+
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(context.Background(), resource.ReadRequest{State: createResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected read diagnostics: %v", readResp.Diagnostics)
+	}
+	readResp.State.Get(context.Background(), &got)
+	if got.Memory.ValueString() != "8Gi" || got.Pods.ValueInt64() != 20 {
+		t.Errorf("Expected read to refresh limits, got memory=%v pods=%v", got.Memory, got.Pods)
+	}
+
+	deleteResp := resource.DeleteResponse{State: readResp.State}
+	r.Delete(context.Background(), resource.DeleteRequest{State: readResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected delete diagnostics: %v", deleteResp.Diagnostics)
+	}
+
+	want := []string{"PUT /namespaces/team-payments/quota", "GET /namespaces/team-payments/quota", "DELETE /namespaces/team-payments/quota"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestModuleRolloutResourceLifecycle(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "mod-1"})
+		case strings.HasSuffix(r.URL.Path, "/pause"):
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "paused"})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "progressing"})
+		}
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleRolloutResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	plan := NixernetesModuleRolloutModel{
+		ID:       types.StringUnknown(),
+		ModuleID: types.StringValue("mod-1"),
+		Action:   types.StringValue("pause"),
+		Status:   types.StringUnknown(),
+	}
+	createResp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: testPlan(t, r, plan)}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected create diagnostics: %v", createResp.Diagnostics)
+	}
+	var got NixernetesModuleRolloutModel
+	createResp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "mod-1" || got.Status.ValueString() != "paused" {
+		t.Errorf("Expected id mod-1 and status paused, got %v and %v", got.ID, got.Status)
+	}
+
+	plan = got
+	plan.Action = types.StringValue("resume")
+	plan.Status = types.StringUnknown()
+	updateResp := resource.UpdateResponse{State: createResp.State}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: testPlan(t, r, plan), State: createResp.State}, &updateResp)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected update diagnostics: %v", updateResp.Diagnostics)
+	}
+	updateResp.State.Get(context.Background(), &got)
+	if got.Status.ValueString() != "progressing" {
+		t.Errorf("Expected status progressing, got %v", got.Status)
+	}
+
+	deleteResp := resource.DeleteResponse{State: updateResp.State}
+	r.Delete(context.Background(), resource.DeleteRequest{State: updateResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected delete diagnostics: %v", deleteResp.Diagnostics)
+	}
+
+	// Deleting only forgets the resource.
+	want := []string{"GET /modules/mod-1", "POST /modules/mod-1/rollout/pause", "GET /modules/mod-1", "POST /modules/mod-1/rollout/resume"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestModuleRolloutResourceMissingModule(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Unexpected %s request to %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleRolloutResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesModuleRolloutModel{
+		ID:       types.StringUnknown(),
+		ModuleID: types.StringValue("mod-404"),
+		Action:   types.StringValue("abort"),
+		Status:   types.StringUnknown(),
+	}
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: testPlan(t, r, plan)}, &resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), `module "mod-404" does not exist`) {
+		t.Errorf("Expected missing module error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestModuleRolloutResourceValidateConfig(t *testing.T) {
+	r := &NixernetesModuleRolloutResource{}
+	for _, tt := range []struct {
+		action  types.String
+		wantErr bool
+	}{
+		{types.StringValue("pause"), false},
+		{types.StringValue("resume"), false},
+		{types.StringValue("abort"), false},
+		{types.StringValue("restart"), true},
+		{types.StringUnknown(), false},
+	} {
+		plan := testPlan(t, r, NixernetesModuleRolloutModel{ModuleID: types.StringValue("mod-1"), Action: tt.action})
+		req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+		var resp resource.ValidateConfigResponse
+		r.ValidateConfig(context.Background(), req, &resp)
+
+		if resp.Diagnostics.HasError() != tt.wantErr {
+			t.Errorf("action %v: expected error %v, got %v", tt.action, tt.wantErr, resp.Diagnostics)
+		}
+	}
+}
+
+func TestModuleResourceValidateConfig(t *testing.T) {
+	r := &NixernetesModuleResource{}
+	for _, tt := range []struct {
+		name     string
+		image    types.String
+		replicas types.Int64
+		wantErr  string
+	}{
+		{"valid", types.StringValue("nginx:1.25"), types.Int64Value(3), ""},
+		{"zero replicas", types.StringValue("nginx:1.25"), types.Int64Value(0), ""},
+		{"unknown values", types.StringUnknown(), types.Int64Unknown(), ""},
+		{"too many replicas", types.StringValue("nginx:1.25"), types.Int64Value(200), "Invalid replicas"},
+		{"negative replicas", types.StringValue("nginx:1.25"), types.Int64Value(-1), "Invalid replicas"},
+		{"command substitution", types.StringValue("nginx:$(whoami)"), types.Int64Null(), "Invalid image"},
+		{"chained command", types.StringValue("nginx; rm -rf /"), types.Int64Null(), "Invalid image"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan(t, r, NixernetesModuleModel{Name: types.StringValue("api"), Image: tt.image, Replicas: tt.replicas})
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), req, &resp)
+
+			if tt.wantErr == "" {
+				if resp.Diagnostics.HasError() {
+					t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantErr {
+				t.Errorf("Expected %q, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestProjectResourceValidateConfig(t *testing.T) {
+	r := &NixernetesProjectResource{}
+	for _, tt := range []struct {
+		name    string
+		model   NixernetesProjectModel
+		wantErr bool
+	}{
+		{"valid", NixernetesProjectModel{Name: types.StringValue("shop"), DefaultNamespace: types.StringValue("shop")}, false},
+		{"unknown name", NixernetesProjectModel{Name: types.StringUnknown()}, false},
+		{"invalid name", NixernetesProjectModel{Name: types.StringValue("my shop")}, true},
+		{"long description", NixernetesProjectModel{Name: types.StringValue("shop"), Description: types.StringValue(strings.Repeat("a", 1001))}, true},
+		{"invalid default namespace", NixernetesProjectModel{Name: types.StringValue("shop"), DefaultNamespace: types.StringValue("Shop_NS")}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan(t, r, tt.model)
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestQuotaPath(t *testing.T) {
+	if got := quotaPath("team-payments"); got != "/namespaces/team-payments/quota" {
+		t.Errorf("Unexpected quota path: %s", got)
+	}
+	if got := quotaPath("a/../b"); got != "/namespaces/a%2F..%2Fb/quota" {
+		t.Errorf("Expected the namespace to be escaped, got %s", got)
+	}
+}
+
+func TestResourceQuotaResourceValidateConfig(t *testing.T) {
+	r := &NixernetesResourceQuotaResource{}
+	for _, tt := range []struct {
+		name    string
+		model   NixernetesResourceQuotaModel
+		wantErr bool
+	}{
+		{"valid", NixernetesResourceQuotaModel{Namespace: types.StringValue("shop"), CPU: types.StringValue("500m"), Memory: types.StringValue("8Gi")}, false},
+		{"unknown limit", NixernetesResourceQuotaModel{Namespace: types.StringValue("shop"), CPU: types.StringUnknown()}, false},
+		{"no limits", NixernetesResourceQuotaModel{Namespace: types.StringValue("shop")}, true},
+		{"invalid namespace", NixernetesResourceQuotaModel{Namespace: types.StringValue("-shop"), Pods: types.Int64Value(10)}, true},
+		{"invalid cpu", NixernetesResourceQuotaModel{Namespace: types.StringValue("shop"), CPU: types.StringValue("four")}, true},
+		{"negative pods", NixernetesResourceQuotaModel{Namespace: types.StringValue("shop"), Pods: types.Int64Value(-1)}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan(t, r, tt.model)
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestResourceQuotaResourceReadNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	r := &NixernetesResourceQuotaResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	state := testState(t, r, NixernetesResourceQuotaModel{
+		ID:        types.StringValue("team-payments"),
+		Namespace: types.StringValue("team-payments"),
+		CPU:       types.StringValue("4"),
+		Memory:    types.StringNull(),
+		Pods:      types.Int64Null(),
+	})
+	resp := resource.ReadResponse{State: state}
+	r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Error("Expected quota to be removed from state")
+	}
+}
+
+func TestResourcesReadNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	client := &NixernetesClient{Endpoint: server.URL}
+
+	tests := []struct {
+		name  string
+		r     resource.Resource
+		prior interface{}
+	}{
+		{"config", &NixernetesConfigResource{client: client}, NixernetesConfigModel{
+			ID:            types.StringValue("cfg-1"),
+			Name:          types.StringValue("web"),
+			Configuration: types.StringValue("{ }"),
+		}},
+		{"module", &NixernetesModuleResource{client: client}, NixernetesModuleModel{
+			ID:        types.StringValue("mod-1"),
+			Name:      types.StringValue("api"),
+			Replicas:  types.Int64Value(1),
+			Image:     types.StringValue("nginx:latest"),
+			Namespace: types.StringValue("default"),
+			Enabled:   types.BoolValue(true),
+		}},
+		{"project", &NixernetesProjectResource{client: client}, NixernetesProjectModel{
+			ID:      types.StringValue("proj-1"),
+			Name:    types.StringValue("shop"),
+			Enabled: types.BoolValue(true),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := testState(t, tt.r, tt.prior)
+			resp := resource.ReadResponse{State: state}
+			tt.r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if !resp.State.Raw.IsNull() {
+				t.Errorf("Expected the %s to be removed from state", tt.name)
+			}
+		})
+	}
+}
+
+func TestProjectResourceModifyPlanProductionDestroy(t *testing.T) {
+	r := &NixernetesProjectResource{}
+
+	project := NixernetesProjectModel{
+		ID:                     types.StringValue("proj-1"),
+		Name:                   types.StringValue("payments"),
+		Description:            types.StringValue("Payments"),
+		Status:                 types.StringValue("production"),
+		Enabled:                types.BoolValue(true),
+		CreatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		UpdatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		AllowProductionDestroy: types.BoolValue(false),
+	}
+	staging := project
+	staging.Status = types.StringValue("staging")
+	allowed := project
+	allowed.AllowProductionDestroy = types.BoolValue(true)
+
+	tests := []struct {
+		name      string
+		state     NixernetesProjectModel
+		wantError bool
+	}{
+		{"production without override", project, true},
+		{"production with override", allowed, false},
+		{"non-production", staging, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := testState(t, r, tt.state)
+			req := resource.ModifyPlanRequest{State: state, Plan: testPlan(t, r, nil)}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("Expected error %v, got diagnostics %v", tt.wantError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestProjectResourceModifyPlanProductionDisable(t *testing.T) {
+	r := &NixernetesProjectResource{}
+
+	state := NixernetesProjectModel{
+		ID:                     types.StringValue("proj-1"),
+		Name:                   types.StringValue("payments"),
+		Description:            types.StringValue("Payments"),
+		Status:                 types.StringValue("production"),
+		Enabled:                types.BoolValue(true),
+		CreatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		UpdatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		AllowProductionDestroy: types.BoolValue(false),
+	}
+	plan := state
+	plan.Enabled = types.BoolValue(false)
+
+	req := resource.ModifyPlanRequest{State: testState(t, r, state), Plan: testPlan(t, r, plan)}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(context.Background(), req, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("Expected disabling a production project to be refused")
+	}
+
+	plan.AllowProductionDestroy = types.BoolValue(true)
+	req = resource.ModifyPlanRequest{State: testState(t, r, state), Plan: testPlan(t, r, plan)}
+	resp = resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("Unexpected diagnostics with override: %v", resp.Diagnostics)
+	}
+}
+
+func TestModuleResourceReadAutoscaled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":               "mod-1",
+			"name":             "api",
+			"replicas":         7,
+			"current_replicas": 7,
+			"image":            "nginx:latest",
+			"namespace":        "default",
+			"autoscaling": map[string]interface{}{
+				"minReplicas":                    2,
+				"maxReplicas":                    10,
+				"targetCPUUtilizationPercentage": 70,
+			},
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	prior := NixernetesModuleModel{
+		ID:        types.StringValue("mod-1"),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(2),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringValue("default"),
+		Autoscaling: &NixernetesAutoscalingModel{
+			MinReplicas:          types.Int64Value(2),
+			MaxReplicas:          types.Int64Value(10),
+			TargetCPUUtilization: types.Int64Value(70),
+		},
+		Enabled:         types.BoolValue(true),
+		CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas: types.Int64Value(2),
+	}
+
+	req := resource.ReadRequest{State: testState(t, r, prior)}
+	resp := resource.ReadResponse{State: req.State}
+	r.Read(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got NixernetesModuleModel
+	resp.State.Get(context.Background(), &got)
+	if got.Replicas.ValueInt64() != 2 {
+		t.Errorf("Expected configured replicas to be kept, got %v", got.Replicas)
+	}
+	if got.CurrentReplicas.ValueInt64() != 7 {
+		t.Errorf("Expected current_replicas 7, got %v", got.CurrentReplicas)
+	}
+}
+
+func TestModuleResourceValidateConfigReplicasAutoscaling(t *testing.T) {
+	autoscaling := &NixernetesAutoscalingModel{
+		MinReplicas: types.Int64Value(2),
+		MaxReplicas: types.Int64Value(10),
+	}
+	tests := []struct {
+		name        string
+		replicas    types.Int64
+		autoscaling *NixernetesAutoscalingModel
+		wantErr     bool
+	}{
+		{"both set", types.Int64Value(3), autoscaling, true},
+		{"replicas only", types.Int64Value(3), nil, false},
+		{"autoscaling only", types.Int64Null(), autoscaling, false},
+		{"neither", types.Int64Null(), nil, false},
+		{"replicas not yet known", types.Int64Unknown(), autoscaling, false},
+	}
+
+	r := &NixernetesModuleResource{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan(t, r, NixernetesModuleModel{
+				Name:        types.StringValue("api"),
+				Image:       types.StringValue("nginx:latest"),
+				Replicas:    tt.replicas,
+				Autoscaling: tt.autoscaling,
+			})
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestAutoscaledReplicasPlanModifier(t *testing.T) {
+	r := &NixernetesModuleResource{}
+	s := testResourceSchema(t, r)
+
+	config := NixernetesModuleModel{
+		ID:        types.StringNull(),
+		Name:      types.StringValue("api"),
+		Replicas:  types.Int64Value(5),
+		Image:     types.StringValue("nginx:latest"),
+		Namespace: types.StringNull(),
+		Autoscaling: &NixernetesAutoscalingModel{
+			MinReplicas:          types.Int64Value(2),
+			MaxReplicas:          types.Int64Value(10),
+			TargetCPUUtilization: types.Int64Null(),
+		},
+		Enabled:         types.BoolNull(),
+		CreatedAt:       types.StringNull(),
+		CurrentReplicas: types.Int64Null(),
+	}
+
+	tests := []struct {
+		name        string
+		autoscaling *NixernetesAutoscalingModel
+		want        int64
+	}{
+		{"autoscaled keeps state", config.Autoscaling, 3},
+		{"static uses plan", nil, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := config
+			c.Autoscaling = tt.autoscaling
+			req := planmodifier.Int64Request{
+				Path:        path.Root("replicas"),
+				Config:      tfsdk.Config{Schema: s, Raw: testPlan(t, r, c).Raw},
+				ConfigValue: types.Int64Value(5),
+				PlanValue:   types.Int64Value(5),
+				StateValue:  types.Int64Value(3),
+			}
+			resp := planmodifier.Int64Response{PlanValue: req.PlanValue}
+			autoscaledReplicas{}.PlanModifyInt64(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if resp.PlanValue.ValueInt64() != tt.want {
+				t.Errorf("Expected planned replicas %d, got %v", tt.want, resp.PlanValue)
+			}
+		})
+	}
+}
+
+func TestModuleResourceCreateReadyTimeout(t *testing.T) {
+	moduleReadyPollInterval = time.Millisecond
+	defer func() { moduleReadyPollInterval = 5 * time.Second }()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "mod-1", "created_at": "2024-02-04T00:00:00Z"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"phase": "Pending"})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	tests := []struct {
+		onReadyTimeout string
+		wantError      bool
+		wantWarning    bool
+	}{
+		{"fail", true, false},
+		{"taint", false, true},
+		{"continue", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.onReadyTimeout, func(t *testing.T) {
+			plan := NixernetesModuleModel{
+				ID:              types.StringUnknown(),
+				Name:            types.StringValue("api"),
+				Replicas:        types.Int64Value(1),
+				Image:           types.StringValue("nginx:latest"),
+				Namespace:       types.StringValue("default"),
+				Enabled:         types.BoolValue(true),
+				CreatedAt:       types.StringUnknown(),
+				CurrentReplicas: types.Int64Unknown(),
+				ReadyTimeout:    types.StringValue("10ms"),
+				OnReadyTimeout:  types.StringValue(tt.onReadyTimeout),
+				Ready:           types.BoolUnknown(),
+			}
+
+			req := resource.CreateRequest{Plan: testPlan(t, r, plan)}
+			resp := resource.CreateResponse{State: testState(t, r, nil)}
+			r.Create(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("Expected error %v, got %v", tt.wantError, resp.Diagnostics)
+			}
+			if (resp.Diagnostics.WarningsCount() > 0) != tt.wantWarning {
+				t.Errorf("Expected warning %v, got %v", tt.wantWarning, resp.Diagnostics)
+			}
+
+			// The module exists either way, so it must be tracked in state
+			var got NixernetesModuleModel
+			resp.State.Get(context.Background(), &got)
+			if got.ID.ValueString() != "mod-1" {
+				t.Errorf("Expected created module to be saved in state, got id %v", got.ID)
+			}
+			if !got.Ready.Equal(types.BoolValue(false)) {
+				t.Errorf("Expected ready false, got %v", got.Ready)
+			}
+		})
+	}
+}
+
+func TestModuleResourceModifyPlanTaintsUnready(t *testing.T) {
+	r := &NixernetesModuleResource{}
+
+	state := NixernetesModuleModel{
+		ID:              types.StringValue("mod-1"),
+		Name:            types.StringValue("api"),
+		Replicas:        types.Int64Value(1),
+		Image:           types.StringValue("nginx:latest"),
+		Namespace:       types.StringValue("default"),
+		Enabled:         types.BoolValue(true),
+		CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas: types.Int64Value(0),
+		ReadyTimeout:    types.StringValue("5m"),
+		OnReadyTimeout:  types.StringValue("taint"),
+		Ready:           types.BoolValue(false),
+	}
+
+	tests := []struct {
+		name        string
+		ready       types.Bool
+		onTimeout   string
+		wantReplace bool
+	}{
+		{"unready with taint", types.BoolValue(false), "taint", true},
+		{"unready with continue", types.BoolValue(false), "continue", false},
+		{"ready", types.BoolValue(true), "taint", false},
+		{"not waited", types.BoolNull(), "taint", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prior := state
+			prior.Ready = tt.ready
+			plan := prior
+			plan.OnReadyTimeout = types.StringValue(tt.onTimeout)
+
+			req := resource.ModifyPlanRequest{State: testState(t, r, prior), Plan: testPlan(t, r, plan)}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if (len(resp.RequiresReplace) > 0) != tt.wantReplace {
+				t.Errorf("Expected replace %v, got RequiresReplace %v", tt.wantReplace, resp.RequiresReplace)
+			}
+		})
+	}
+}
+
+func TestModuleResourceModifyPlanRefreshTrigger(t *testing.T) {
+	r := &NixernetesModuleResource{}
+
+	state := NixernetesModuleModel{
+		ID:              types.StringValue("mod-1"),
+		Name:            types.StringValue("api"),
+		Replicas:        types.Int64Value(2),
+		Image:           types.StringValue("nginx:latest"),
+		Namespace:       types.StringValue("default"),
+		Enabled:         types.BoolValue(true),
+		CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas: types.Int64Value(1),
+		ReadyTimeout:    types.StringValue("5m"),
+		OnReadyTimeout:  types.StringValue("fail"),
+		Ready:           types.BoolValue(true),
+		RefreshTrigger:  types.StringValue("1"),
+	}
+	plan := state
+	plan.RefreshTrigger = types.StringValue("2")
+
+	req := resource.ModifyPlanRequest{State: testState(t, r, state), Plan: testPlan(t, r, plan)}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got NixernetesModuleModel
+	resp.Plan.Get(context.Background(), &got)
+	if !got.CurrentReplicas.IsUnknown() || !got.Ready.IsUnknown() {
+		t.Errorf("Expected current_replicas and ready to be unknown, got %v and %v", got.CurrentReplicas, got.Ready)
+	}
+	if len(resp.RequiresReplace) > 0 {
+		t.Errorf("Expected no replacement, got RequiresReplace %v", resp.RequiresReplace)
+	}
+}
+
+// testPlanReplaces runs the plan modifiers of the string attribute name
+// as Terraform would when planning a change from state to plan, and reports
+// whether they require replacing the resource. An unknown planned value
+// stands for an attribute left out of the configuration.
+func testPlanReplaces(t *testing.T, r resource.Resource, name string, state, plan interface{}) bool {
+	t.Helper()
+
+	attribute, ok := testResourceSchema(t, r).Attributes[name].(schema.StringAttribute)
+	if !ok {
+		t.Fatalf("Expected %s to be a string attribute", name)
+	}
+
+	req := planmodifier.StringRequest{
+		Path:  path.Root(name),
+		State: testState(t, r, state),
+		Plan:  testPlan(t, r, plan),
+	}
+	req.Config = tfsdk.Config{Schema: req.Plan.Schema, Raw: req.Plan.Raw}
+	req.State.GetAttribute(context.Background(), req.Path, &req.StateValue)
+	req.Plan.GetAttribute(context.Background(), req.Path, &req.PlanValue)
+	req.ConfigValue = req.PlanValue
+	if req.ConfigValue.IsUnknown() {
+		req.ConfigValue = types.StringNull()
+	}
+
+	for _, modifier := range attribute.PlanModifiers {
+		var resp planmodifier.StringResponse
+		resp.PlanValue = req.PlanValue
+		modifier.PlanModifyString(context.Background(), req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		if resp.RequiresReplace {
+			return true
+		}
+		req.PlanValue = resp.PlanValue
+	}
+	return false
+}
+
+func TestResourcesNameChangeReplaces(t *testing.T) {
+	t.Run("config", func(t *testing.T) {
+		state := NixernetesConfigModel{ID: types.StringValue("config-1"), Name: types.StringValue("web"), Configuration: types.StringValue("{ }")}
+		plan := state
+		plan.Name = types.StringValue("web-2")
+		if !testPlanReplaces(t, &NixernetesConfigResource{}, "name", state, plan) {
+			t.Error("Expected a name change to replace the configuration")
+		}
+		if testPlanReplaces(t, &NixernetesConfigResource{}, "name", state, state) {
+			t.Error("Expected an unchanged name not to replace the configuration")
+		}
+	})
+
+	t.Run("module", func(t *testing.T) {
+		state := NixernetesModuleModel{ID: types.StringValue("mod-1"), Name: types.StringValue("api"), Image: types.StringValue("nginx:latest"), Namespace: types.StringValue("default")}
+		plan := state
+		plan.Name = types.StringValue("api-2")
+		if !testPlanReplaces(t, &NixernetesModuleResource{}, "name", state, plan) {
+			t.Error("Expected a name change to replace the module")
+		}
+	})
+
+	t.Run("project", func(t *testing.T) {
+		state := NixernetesProjectModel{ID: types.StringValue("proj-1"), Name: types.StringValue("shop")}
+		plan := state
+		plan.Name = types.StringValue("store")
+		if !testPlanReplaces(t, &NixernetesProjectResource{}, "name", state, plan) {
+			t.Error("Expected a name change to replace the project")
+		}
+	})
+}
+
+func TestModuleResourceNamespaceChangeReplaces(t *testing.T) {
+	r := &NixernetesModuleResource{}
+	state := NixernetesModuleModel{ID: types.StringValue("mod-1"), Name: types.StringValue("api"), Image: types.StringValue("nginx:latest"), Namespace: types.StringValue("default")}
+
+	tests := []struct {
+		name        string
+		namespace   types.String
+		wantReplace bool
+	}{
+		{"changed", types.StringValue("apps"), true},
+		{"unchanged", types.StringValue("default"), false},
+		{"left unset", types.StringUnknown(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := state
+			plan.Namespace = tt.namespace
+			if got := testPlanReplaces(t, r, "namespace", state, plan); got != tt.wantReplace {
+				t.Errorf("Expected replace %v, got %v", tt.wantReplace, got)
+			}
+		})
+	}
+}
+
+func TestModuleResourceModifyPlanDestroyImpact(t *testing.T) {
+	tests := []struct {
+		name        string
+		impact      map[string]interface{}
+		status      map[string]interface{}
+		wantWarning string
+	}{
+		{
+			name:        "impact endpoint",
+			impact:      map[string]interface{}{"ready_replicas": 3, "active_connections": 120, "dependents": []string{"checkout", "search"}},
+			wantWarning: "3 ready replica(s) serving 120 active connection(s), the dependents checkout, search",
+		},
+		{
+			name:        "status fallback",
+			status:      map[string]interface{}{"phase": "Ready", "ready_replicas": 2},
+			wantWarning: "2 ready replica(s)",
+		},
+		{
+			name:   "nothing running",
+			status: map[string]interface{}{"phase": "Pending", "ready_replicas": 0},
+		},
+		{
+			name: "no impact or status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("Expected only read-only requests, got %s %s", r.Method, r.URL.Path)
+				}
+				var body map[string]interface{}
+				switch r.URL.Path {
+				case "/modules/mod-1/impact":
+					body = tt.impact
+				case "/modules/mod-1/status":
+					body = tt.status
+				}
+				if body == nil {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(body)
+			}))
+			defer server.Close()
+
+			r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+			state := NixernetesModuleModel{
+				ID:        types.StringValue("mod-1"),
+				Name:      types.StringValue("api"),
+				Replicas:  types.Int64Value(3),
+				Image:     types.StringValue("nginx:latest"),
+				Namespace: types.StringValue("default"),
+				Enabled:   types.BoolValue(true),
+				CreatedAt: types.StringValue("2024-02-04T00:00:00Z"),
+			}
+
+			req := resource.ModifyPlanRequest{State: testState(t, r, state), Plan: testPlan(t, r, nil)}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			warnings := resp.Diagnostics.Warnings()
+			if tt.wantWarning == "" {
+				if len(warnings) != 0 {
+					t.Errorf("Expected no warning, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 {
+				t.Fatalf("Expected one warning, got %v", warnings)
+			}
+			if detail := warnings[0].Detail(); !strings.Contains(detail, tt.wantWarning) {
+				t.Errorf("Expected warning to contain %q, got %q", tt.wantWarning, detail)
+			}
+		})
+	}
+}
+
+func TestModuleResourceUpdateRefreshOnly(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/modules/mod-1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "mod-1", "current_replicas": 2})
+		case "/modules/mod-1/status":
+			json.NewEncoder(w).Encode(map[string]interface{}{"phase": "Ready", "ready_replicas": 2})
+		}
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	state := NixernetesModuleModel{
+		ID:              types.StringValue("mod-1"),
+		Name:            types.StringValue("api"),
+		Replicas:        types.Int64Value(2),
+		Image:           types.StringValue("nginx:latest"),
+		Namespace:       types.StringValue("default"),
+		Enabled:         types.BoolValue(true),
+		CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas: types.Int64Value(1),
+		ReadyTimeout:    types.StringValue("5m"),
+		OnReadyTimeout:  types.StringValue("continue"),
+		Ready:           types.BoolValue(false),
+		RefreshTrigger:  types.StringValue("1"),
+	}
+	plan := state
+	plan.Namespace = types.StringUnknown()
+	plan.CurrentReplicas = types.Int64Unknown()
+	plan.Ready = types.BoolUnknown()
+	plan.RefreshTrigger = types.StringValue("2")
+
+	req := resource.UpdateRequest{State: testState(t, r, state), Plan: testPlan(t, r, plan)}
+	resp := resource.UpdateResponse{State: testState(t, r, state)}
+	r.Update(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if want := "GET /modules/mod-1,GET /modules/mod-1/status"; strings.Join(requests, ",") != want {
+		t.Errorf("Expected requests %s, got %v", want, requests)
+	}
+
+	var got NixernetesModuleModel
+	resp.State.Get(context.Background(), &got)
+	if got.CurrentReplicas.ValueInt64() != 2 {
+		t.Errorf("Expected current_replicas 2, got %v", got.CurrentReplicas)
+	}
+	if !got.Ready.ValueBool() {
+		t.Errorf("Expected ready true, got %v", got.Ready)
+	}
+	if got.Namespace.ValueString() != "default" || got.RefreshTrigger.ValueString() != "2" {
+		t.Errorf("Expected namespace default and refresh_trigger 2, got %v and %v", got.Namespace, got.RefreshTrigger)
+	}
+}
+
+func TestModuleResourceUpdateConfiguredProviderPatches(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			method = r.Method
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":        "mod-1",
+			"name":      "api",
+			"replicas":  3,
+			"image":     "nginx:latest",
+			"namespace": "default",
+		})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := &NixernetesProvider{version: "test"}
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	// Only the credentials are configured, so update_method is left unset.
+	config := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	for name, value := range map[string]string{"endpoint": server.URL, "username": "admin", "password": "secret"} {
+		if diags := config.SetAttribute(ctx, path.Root(name), value); diags.HasError() {
+			t.Fatalf("Unexpected config diagnostics: %v", diags)
+		}
+	}
+	var configureResp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config.Raw}}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected configure diagnostics: %v", configureResp.Diagnostics)
+	}
+
+	r := &NixernetesModuleResource{}
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: configureResp.ResourceData}, &resource.ConfigureResponse{})
+
+	state := NixernetesModuleModel{
+		ID:              types.StringValue("mod-1"),
+		Name:            types.StringValue("api"),
+		EffectiveName:   types.StringValue("api"),
+		Replicas:        types.Int64Value(2),
+		Image:           types.StringValue("nginx:latest"),
+		Namespace:       types.StringValue("default"),
+		Enabled:         types.BoolValue(true),
+		CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas: types.Int64Value(2),
+	}
+	plan := state
+	plan.Replicas = types.Int64Value(3)
+
+	req := resource.UpdateRequest{State: testState(t, r, state), Plan: testPlan(t, r, plan)}
+	resp := resource.UpdateResponse{State: testState(t, r, state)}
+	r.Update(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if method != http.MethodPatch {
+		t.Errorf("Expected the update to be sent as PATCH, got %s", method)
+	}
+}
+
+func TestModuleResourceUpdateSendsChanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		updateMethod string
+		wantMethod   string
+		wantFields   []string
+	}{
+		{"merge patch", "", "PATCH", []string{"replicas"}},
+		{"update_method", "PUT", "PUT", []string{"image", "name", "namespace", "replicas"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var method string
+			var sent map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method != http.MethodGet {
+					method = r.Method
+					json.NewDecoder(r.Body).Decode(&sent)
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":        "mod-1",
+					"name":      "api",
+					"replicas":  3,
+					"image":     "nginx:latest",
+					"namespace": "default",
+				})
+			}))
+			defer server.Close()
+
+			r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL, UpdateMethod: tt.updateMethod}}
+
+			state := NixernetesModuleModel{
+				ID:              types.StringValue("mod-1"),
+				Name:            types.StringValue("api"),
+				EffectiveName:   types.StringValue("api"),
+				Replicas:        types.Int64Value(2),
+				Image:           types.StringValue("nginx:latest"),
+				Namespace:       types.StringValue("default"),
+				Enabled:         types.BoolValue(true),
+				CreatedAt:       types.StringValue("2024-02-04T00:00:00Z"),
+				CurrentReplicas: types.Int64Value(2),
+			}
+			plan := state
+			plan.Replicas = types.Int64Value(3)
+			plan.Namespace = types.StringUnknown()
+			plan.CurrentReplicas = types.Int64Unknown()
+
+			req := resource.UpdateRequest{State: testState(t, r, state), Plan: testPlan(t, r, plan)}
+			resp := resource.UpdateResponse{State: testState(t, r, state)}
+			r.Update(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if method != tt.wantMethod {
+				t.Errorf("Expected a %s request, got %s", tt.wantMethod, method)
+			}
+			var fields []string
+			for field := range sent {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Errorf("Expected fields %v in the update body, got %v", tt.wantFields, sent)
+			}
+			if sent["replicas"] != float64(3) {
+				t.Errorf("Expected replicas 3 in the update body, got %v", sent["replicas"])
+			}
+		})
+	}
+}
+
+func TestModuleResourceUpdateRefreshAfterUpdate(t *testing.T) {
+	tests := []struct {
+		name         string
+		refresh      bool
+		wantRequests string
+		wantReplicas int64
+	}{
+		{"enabled", true, "PATCH /modules/mod-1,GET /modules/mod-1", 3},
+		{"disabled", false, "PATCH /modules/mod-1", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == "PATCH" {
+					// A partial response that leaves out most fields
+					json.NewEncoder(w).Encode(map[string]interface{}{})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"id":               "mod-1",
+					"name":             "api",
+					"replicas":         2,
+					"current_replicas": 3,
+					"image":            "nginx:1.27",
+					"namespace":        "default",
+				})
+			}))
+			defer server.Close()
+
+			r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+			state := NixernetesModuleModel{
+				ID:                 types.StringValue("mod-1"),
+				Name:               types.StringValue("api"),
+				EffectiveName:      types.StringValue("api"),
+				Replicas:           types.Int64Value(2),
+				Image:              types.StringValue("nginx:latest"),
+				Namespace:          types.StringValue("default"),
+				Enabled:            types.BoolValue(true),
+				CreatedAt:          types.StringValue("2024-02-04T00:00:00Z"),
+				CurrentReplicas:    types.Int64Value(2),
+				OnReadyTimeout:     types.StringValue("fail"),
+				RefreshAfterUpdate: types.BoolValue(tt.refresh),
+			}
+			plan := state
+			plan.Image = types.StringValue("nginx:1.27")
+			plan.EffectiveName = types.StringUnknown()
+			plan.CurrentReplicas = types.Int64Unknown()
+
+			req := resource.UpdateRequest{State: testState(t, r, state), Plan: testPlan(t, r, plan)}
+			resp := resource.UpdateResponse{State: testState(t, r, state)}
+			r.Update(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if got := strings.Join(requests, ","); got != tt.wantRequests {
+				t.Errorf("Expected requests %s, got %s", tt.wantRequests, got)
+			}
+
+			var got NixernetesModuleModel
+			resp.State.Get(context.Background(), &got)
+			if got.CurrentReplicas.ValueInt64() != tt.wantReplicas {
+				t.Errorf("Expected current_replicas %d, got %v", tt.wantReplicas, got.CurrentReplicas)
+			}
+			if got.Image.ValueString() != "nginx:1.27" {
+				t.Errorf("Expected image nginx:1.27, got %v", got.Image)
+			}
+		})
+	}
+}
+
+func TestProjectResourceCascadeDelete(t *testing.T) {
+	deletionPollInterval = time.Millisecond
+	defer func() { deletionPollInterval = 5 * time.Second }()
+
+	var requests []string
+	gets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		if r.Method == "GET" {
+			gets++
+			if gets >= 2 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "proj-1", "module_count": 2})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	r := &NixernetesProjectResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	state := testState(t, r, NixernetesProjectModel{
+		ID:                     types.StringValue("proj-1"),
+		Name:                   types.StringValue("payments"),
+		Description:            types.StringValue("Payments"),
+		Status:                 types.StringValue("active"),
+		Enabled:                types.BoolValue(true),
+		CreatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		UpdatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		AllowProductionDestroy: types.BoolValue(false),
+		CascadeDelete:          types.BoolValue(true),
+		WaitForDeletion:        types.BoolValue(true),
+	})
+	resp := resource.DeleteResponse{State: state}
+	r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	want := []string{"DELETE /projects/proj-1?cascade=true", "GET /projects/proj-1", "GET /projects/proj-1"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestProjectResourcePauseResume(t *testing.T) {
+	var requests []string
+	paused := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/projects/proj-1/pause":
+			paused = true
+		case "/projects/proj-1/resume":
+			paused = false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "proj-1",
+			"name":       "payments",
+			"status":     "active",
+			"paused":     paused,
+			"updated_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesProjectResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	state := NixernetesProjectModel{
+		ID:                     types.StringValue("proj-1"),
+		Name:                   types.StringValue("payments"),
+		EffectiveName:          types.StringValue("payments"),
+		Status:                 types.StringValue("active"),
+		Enabled:                types.BoolValue(true),
+		CreatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		UpdatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		AllowProductionDestroy: types.BoolValue(false),
+		CascadeDelete:          types.BoolValue(false),
+		WaitForDeletion:        types.BoolValue(false),
+		Paused:                 types.BoolValue(false),
+	}
+
+	for _, want := range []bool{true, false} {
+		plan := state
+		plan.Paused = types.BoolValue(want)
+
+		req := resource.UpdateRequest{Plan: testPlan(t, r, plan), State: testState(t, r, state)}
+		resp := resource.UpdateResponse{State: testState(t, r, state)}
+		r.Update(context.Background(), req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		resp.State.Get(context.Background(), &state)
+		if state.Paused.ValueBool() != want {
+			t.Errorf("Expected paused %v, got %v", want, state.Paused)
+		}
+	}
+
+	want := []string{"PATCH /projects/proj-1", "POST /projects/proj-1/pause", "PATCH /projects/proj-1", "POST /projects/proj-1/resume"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestProjectResourceUpdateClearsDescription(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			json.NewDecoder(r.Body).Decode(&sent)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "proj-1",
+			"name":       "payments",
+			"status":     "active",
+			"updated_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesProjectResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	state := NixernetesProjectModel{
+		ID:                     types.StringValue("proj-1"),
+		Name:                   types.StringValue("payments"),
+		EffectiveName:          types.StringValue("payments"),
+		Description:            types.StringValue("Payment services"),
+		Status:                 types.StringValue("active"),
+		Enabled:                types.BoolValue(true),
+		CreatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		UpdatedAt:              types.StringValue("2024-02-03T00:00:00Z"),
+		AllowProductionDestroy: types.BoolValue(false),
+		CascadeDelete:          types.BoolValue(false),
+		WaitForDeletion:        types.BoolValue(false),
+		Paused:                 types.BoolValue(false),
+	}
+	plan := state
+	plan.Description = types.StringNull()
+
+	req := resource.UpdateRequest{Plan: testPlan(t, r, plan), State: testState(t, r, state)}
+	resp := resource.UpdateResponse{State: testState(t, r, state)}
+	r.Update(context.Background(), req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	description, ok := sent["description"]
+	if len(sent) != 1 || !ok || description != nil {
+		t.Errorf("Expected a patch of {description: null}, got %v", sent)
+	}
+}
+
+func TestModuleResourceReadPausedProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":               "mod-1",
+			"name":             "api",
+			"replicas":         0,
+			"paused_replicas":  3,
+			"current_replicas": 0,
+			"image":            "nginx:latest",
+			"namespace":        "default",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	m := NixernetesModuleModel{ID: types.StringValue("mod-1"), Replicas: types.Int64Value(3)}
+	if _, err := r.readRemote(context.Background(), &m); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.Replicas.ValueInt64() != 3 {
+		t.Errorf("Expected the replicas restored on resume, got %v", m.Replicas)
+	}
+	if m.CurrentReplicas.ValueInt64() != 0 {
+		t.Errorf("Expected current_replicas 0, got %v", m.CurrentReplicas)
+	}
+}
+
+func TestProjectResourceReadDefaultNamespaceCleared(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":                "proj-1",
+			"name":              "payments",
+			"status":            "active",
+			"default_namespace": "",
+			"updated_at":        "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesProjectResource{client: &NixernetesClient{Endpoint: server.URL}}
+	state := NixernetesProjectModel{
+		ID:               types.StringValue("proj-1"),
+		Name:             types.StringValue("payments"),
+		DefaultNamespace: types.StringValue("payments"),
+		Enabled:          types.BoolValue(true),
+	}
+
+	resp := resource.ReadResponse{State: testState(t, r, state)}
+	r.Read(context.Background(), resource.ReadRequest{State: testState(t, r, state)}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got NixernetesProjectModel
+	resp.State.Get(context.Background(), &got)
+	if !got.DefaultNamespace.IsNull() {
+		t.Errorf("Expected a cleared default_namespace to be null, got %v", got.DefaultNamespace)
+	}
+}
+
+func TestProjectResourceImportState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/modules":
+			json.NewEncoder(w).Encode(map[string]interface{}{"modules": []map[string]interface{}{
+				{"id": "mod-1", "name": "api", "project_id": "proj-1"},
+			}})
+		case "/configs":
+			json.NewEncoder(w).Encode(map[string]interface{}{"configs": []map[string]interface{}{}})
+		}
+	}))
+	defer server.Close()
+
+	r := &NixernetesProjectResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	resp := resource.ImportStateResponse{State: testState(t, r, nil)}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "proj-1"}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got NixernetesProjectModel
+	resp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "proj-1" || !got.Enabled.ValueBool() || got.CascadeDelete.ValueBool() {
+		t.Errorf("Expected imported ID with default arguments, got %+v", got)
+	}
+
+	warnings := resp.Diagnostics.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), `id = "mod-1"`) {
+		t.Errorf("Expected a warning with an import block for mod-1, got %v", resp.Diagnostics)
+	}
+}
+
+func TestProjectResourceImportStateByName(t *testing.T) {
+	tests := []struct {
+		name        string
+		importID    string
+		wantID      string
+		wantSummary string
+	}{
+		{"id", "proj-1", "proj-1", ""},
+		{"name", "name:production", "proj-2", ""},
+		{"name not found", "name:qa", "", "Cannot import project"},
+		{"ambiguous name", "name:staging", "", "Cannot import project"},
+		{"invalid id", "../configs/cfg-1", "", "Cannot import project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var listed bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/projects":
+					listed = true
+					// Lists every project, like servers that ignore the name filter
+					json.NewEncoder(w).Encode(map[string]interface{}{"projects": []map[string]interface{}{
+						{"id": "proj-1", "name": "development"},
+						{"id": "proj-2", "name": "production"},
+						{"id": "proj-3", "name": "staging"},
+						{"id": "proj-4", "name": "staging"},
+					}})
+				case "/modules":
+					json.NewEncoder(w).Encode(map[string]interface{}{"modules": []map[string]interface{}{}})
+				case "/configs":
+					json.NewEncoder(w).Encode(map[string]interface{}{"configs": []map[string]interface{}{}})
+				}
+			}))
+			defer server.Close()
+
+			r := &NixernetesProjectResource{client: &NixernetesClient{Endpoint: server.URL}}
+			resp := resource.ImportStateResponse{State: testState(t, r, nil)}
+			r.ImportState(context.Background(), resource.ImportStateRequest{ID: tt.importID}, &resp)
+
+			if tt.wantSummary != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantSummary {
+					t.Errorf("Expected %q error, got %v", tt.wantSummary, resp.Diagnostics)
+				}
+				return
+			}
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+			}
+			if listed != strings.HasPrefix(tt.importID, importNamePrefix) {
+				t.Errorf("Expected projects to be listed only for a name import, listed: %v", listed)
+			}
+
+			var id types.String
+			resp.State.GetAttribute(context.Background(), path.Root("id"), &id)
+			if id.ValueString() != tt.wantID {
+				t.Errorf("Expected id %q, got %v", tt.wantID, id)
+			}
+		})
+	}
+}
+
+func TestModuleResourceImportState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/modules/mod-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "mod-1",
+			"name":       "api",
+			"replicas":   3,
+			"image":      "nginx:latest",
+			"namespace":  "default",
+			"created_at": "2024-02-04T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	importResp := resource.ImportStateResponse{State: testState(t, r, nil)}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "mod-1"}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", importResp.Diagnostics)
+	}
+
+	var imported NixernetesModuleModel
+	importResp.State.Get(context.Background(), &imported)
+	if imported.ID.ValueString() != "mod-1" {
+		t.Fatalf("Expected imported ID mod-1, got %v", imported.ID)
+	}
+
+	readResp := resource.ReadResponse{State: importResp.State}
+	r.Read(context.Background(), resource.ReadRequest{State: importResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", readResp.Diagnostics)
+	}
+
+	var got NixernetesModuleModel
+	readResp.State.Get(context.Background(), &got)
+	if got.Name.ValueString() != "api" || got.Replicas.ValueInt64() != 3 || got.CreatedAt.ValueString() != "2024-02-04T00:00:00Z" {
+		t.Errorf("Expected the module to be read after import, got %+v", got)
+	}
+	if !got.Enabled.ValueBool() || got.OnReadyTimeout.ValueString() != onReadyTimeoutFail || !got.RefreshAfterUpdate.ValueBool() {
+		t.Errorf("Expected argument defaults after import, got %+v", got)
+	}
+}
+
+func TestConfigResourceImportState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":            "config-1",
+			"name":          "web",
+			"configuration": "{ }",
+			"environment":   "production",
+			"created_at":    "2024-02-04T00:00:00Z",
+			"updated_at":    "2024-02-05T00:00:00Z",
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	importResp := resource.ImportStateResponse{State: testState(t, r, nil)}
+	r.ImportState(context.Background(), resource.ImportStateRequest{ID: "config-1"}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", importResp.Diagnostics)
+	}
+
+	readResp := resource.ReadResponse{State: importResp.State}
+	r.Read(context.Background(), resource.ReadRequest{State: importResp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", readResp.Diagnostics)
+	}
+
+	var got NixernetesConfigModel
+	readResp.State.Get(context.Background(), &got)
+	if got.ID.ValueString() != "config-1" || got.Configuration.ValueString() != "{ }" || got.CreatedAt.ValueString() != "2024-02-04T00:00:00Z" {
+		t.Errorf("Expected the configuration to be read after import, got %+v", got)
+	}
+}
+
+// setTestPrivate gives the Private field of a framework request or response
+// an empty private state, as Terraform would, and returns it.
+func setTestPrivate(t *testing.T, target interface{}) privateState {
+	t.Helper()
+	field := reflect.ValueOf(target).Elem().FieldByName("Private")
+	field.Set(reflect.New(field.Type().Elem()))
+	return field.Interface().(privateState)
+}
+
+func TestModuleResourceGenerationPrecondition(t *testing.T) {
+	var sent interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPatch {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			sent = body["generation"]
+			// Someone else updated the module since it was read
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": generationConflictCode, "message": "generation is 8"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         "mod-1",
+			"name":       "api",
+			"replicas":   2,
+			"image":      "nginx:latest",
+			"namespace":  "default",
+			"generation": 7,
+		})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleResource{client: &NixernetesClient{Endpoint: server.URL}}
+
+	state := NixernetesModuleModel{
+		ID:                 types.StringValue("mod-1"),
+		Name:               types.StringValue("api"),
+		EffectiveName:      types.StringValue("api"),
+		Replicas:           types.Int64Value(2),
+		Image:              types.StringValue("nginx:latest"),
+		Namespace:          types.StringValue("default"),
+		Enabled:            types.BoolValue(true),
+		RefreshAfterUpdate: types.BoolValue(true),
+		CreatedAt:          types.StringValue("2024-02-04T00:00:00Z"),
+		CurrentReplicas:    types.Int64Value(2),
+	}
+
+	readResp := resource.ReadResponse{State: testState(t, r, state)}
+	private := setTestPrivate(t, &readResp)
+	r.Read(context.Background(), resource.ReadRequest{State: testState(t, r, state)}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", readResp.Diagnostics)
+	}
+	if generation, _ := private.GetKey(context.Background(), generationPrivateKey); string(generation) != "7" {
+		t.Fatalf("Expected generation 7 in private state, got %q", generation)
+	}
+
+	plan := state
+	plan.Replicas = types.Int64Value(3)
+	req := resource.UpdateRequest{Plan: testPlan(t, r, plan), State: testState(t, r, state)}
+	reflect.ValueOf(&req).Elem().FieldByName("Private").Set(reflect.ValueOf(private))
+	resp := resource.UpdateResponse{State: testState(t, r, state)}
+	r.Update(context.Background(), req, &resp)
+
+	if sent != float64(7) {
+		t.Errorf("Expected generation 7 in update body, got %v", sent)
+	}
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Resource changed since last read" {
+		t.Errorf("Expected a changed since last read error, got %v", resp.Diagnostics)
+	}
+}
+
+func TestProjectImportBlocks(t *testing.T) {
+	children := &ProjectChildren{
+		Modules: []ProjectChild{{ID: "mod-1", Name: "api"}, {ID: "mod-2", Name: "api"}, {ID: "mod-3", Name: "1st.worker"}},
+		Configs: []ProjectChild{{ID: "config-1"}},
+	}
+
+	want := `import {
+  to = nixernetes_module.api
+  id = "mod-1"
+}
+
+import {
+  to = nixernetes_module.api_2
+  id = "mod-2"
+}
+
+import {
+  to = nixernetes_module._1st_worker
+  id = "mod-3"
+}
+
+import {
+  to = nixernetes_config.config-1
+  id = "config-1"
+}
+`
+	if got := projectImportBlocks(children); got != want {
+		t.Errorf("Unexpected import blocks:\n%s", got)
+	}
+}
+
+func TestNullEmptyMapPlanModifier(t *testing.T) {
+	empty := types.MapValueMust(types.StringType, map[string]attr.Value{})
+	populated := types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("payments")})
+	null := types.MapNull(types.StringType)
+
+	tests := []struct {
+		name   string
+		config types.Map
+		state  types.Map
+		want   types.Map
+	}{
+		{"unset on create", null, null, null},
+		{"unset with empty state", null, empty, empty},
+		{"unset clears entries", null, populated, null},
+		{"empty is planned as written", empty, null, empty},
+		{"populated is planned as written", populated, empty, populated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned := tt.config
+			if planned.IsNull() {
+				planned = types.MapUnknown(types.StringType)
+			}
+			req := planmodifier.MapRequest{
+				Path:        path.Root("labels"),
+				ConfigValue: tt.config,
+				PlanValue:   planned,
+				StateValue:  tt.state,
+			}
+			resp := planmodifier.MapResponse{PlanValue: req.PlanValue}
+			nullEmptyMap{}.PlanModifyMap(context.Background(), req, &resp)
+
+			if !resp.PlanValue.Equal(tt.want) {
+				t.Errorf("Expected planned value %v, got %v", tt.want, resp.PlanValue)
+			}
+		})
+	}
+}
+
+func TestStringMapFromResponse(t *testing.T) {
+	empty := types.MapValueMust(types.StringType, map[string]attr.Value{})
+	populated := types.MapValueMust(types.StringType, map[string]attr.Value{"team": types.StringValue("payments")})
+	null := types.MapNull(types.StringType)
+
+	tests := []struct {
+		name   string
+		remote interface{}
+		prior  types.Map
+		want   types.Map
+	}{
+		{"server returns empty for unset", map[string]interface{}{}, null, null},
+		{"server omits unset", nil, null, null},
+		{"empty stays empty", map[string]interface{}{}, empty, empty},
+		{"empty stays empty when omitted", nil, empty, empty},
+		{"populated from null", map[string]interface{}{"team": "payments"}, null, populated},
+		{"populated from empty", map[string]interface{}{"team": "payments"}, empty, populated},
+		{"entries removed remotely", map[string]interface{}{}, populated, null},
+		{"unknown prior", map[string]interface{}{}, types.MapUnknown(types.StringType), null},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stringMapFromResponse(tt.remote, tt.prior)
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWithOperationTimeout(t *testing.T) {
+	r := &NixernetesConfigResource{}
+	configured := testTimeouts(t, r, map[string]string{"create": "30s"})
+
+	tests := []struct {
+		name     string
+		client   *NixernetesClient
+		timeouts timeouts.Value
+		want     time.Duration
+	}{
+		{"resource timeout", &NixernetesClient{OperationTimeout: 5 * time.Minute}, configured, 30 * time.Second},
+		{"provider timeout", &NixernetesClient{OperationTimeout: 5 * time.Minute}, testTimeouts(t, r, nil), 5 * time.Minute},
+		{"default", &NixernetesClient{}, testTimeouts(t, r, nil), defaultOperationTimeout},
+		{"other operation set", &NixernetesClient{}, testTimeouts(t, r, map[string]string{"delete": "1m"}), defaultOperationTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			start := time.Now()
+			ctx, cancel := withOperationTimeout(context.Background(), tt.client, tt.timeouts.Create, &diags)
+			defer cancel()
+			if diags.HasError() {
+				t.Fatalf("Unexpected diagnostics: %v", diags)
+			}
+
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatal("Expected a deadline")
+			}
+			if got := deadline.Sub(start); got < tt.want || got > tt.want+time.Second {
+				t.Errorf("Expected a deadline %v from now, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestConfigResourceReadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	r := &NixernetesConfigResource{client: &NixernetesClient{Endpoint: server.URL}}
+	prior := NixernetesConfigModel{
+		ID:            types.StringValue("cfg-1"),
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ }"),
+		Timeouts:      testTimeouts(t, r, map[string]string{"read": "50ms"}),
+	}
+
+	start := time.Now()
+	resp := resource.ReadResponse{State: testState(t, r, prior)}
+	r.Read(context.Background(), resource.ReadRequest{State: testState(t, r, prior)}, &resp)
+	if !resp.Diagnostics.HasError() || !strings.Contains(resp.Diagnostics[0].Detail(), "deadline exceeded") {
+		t.Errorf("Expected a deadline exceeded error, got %v", resp.Diagnostics)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the read to stop after its timeout, took %v", elapsed)
+	}
+}
+
+func TestSecretResourceDataSensitive(t *testing.T) {
+	data, ok := testResourceSchema(t, &NixernetesSecretResource{}).Attributes["data"].(schema.MapAttribute)
+	if !ok || !data.Sensitive {
+		t.Error("Expected data to be a sensitive map attribute")
+	}
+}
+
+func TestSecretResourceCreateRedactsValues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"message": fmt.Sprintf("invalid secret data: %v", body["data"])})
+	}))
+	defer server.Close()
+
+	r := &NixernetesSecretResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesSecretModel{
+		ID:        types.StringUnknown(),
+		Name:      types.StringValue("db-credentials"),
+		Namespace: types.StringValue("default"),
+		Data:      types.MapValueMust(types.StringType, map[string]attr.Value{"dsn": types.StringValue("postgres://app:hunter2@db/app")}),
+	}
+
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: testPlan(t, r, plan)}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("Expected the create to fail")
+	}
+	detail := resp.Diagnostics.Errors()[0].Detail()
+	if strings.Contains(detail, "hunter2") || !strings.Contains(detail, "dsn:"+redactedPlaceholder) {
+		t.Errorf("Expected the secret value to be redacted, got %q", detail)
+	}
+}
+
+func TestSecretResourceUpdateMethod(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	r := &NixernetesSecretResource{client: &NixernetesClient{Endpoint: server.URL, UpdateMethod: "POST"}}
+	state := NixernetesSecretModel{
+		ID:        types.StringValue("secret-1"),
+		Name:      types.StringValue("db-credentials"),
+		Namespace: types.StringValue("default"),
+		Data:      types.MapValueMust(types.StringType, map[string]attr.Value{"password": types.StringValue("hunter2")}),
+	}
+	plan := state
+	plan.Data = types.MapValueMust(types.StringType, map[string]attr.Value{"password": types.StringValue("hunter3")})
+
+	resp := resource.UpdateResponse{State: testState(t, r, state)}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: testPlan(t, r, plan), State: testState(t, r, state)}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	if method != "POST" {
+		t.Errorf("Expected the update to use update_method POST, got %s", method)
+	}
+}
+
+func TestSecretResourceReadRedactedValues(t *testing.T) {
+	var response map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	r := &NixernetesSecretResource{client: &NixernetesClient{Endpoint: server.URL}}
+	state := NixernetesSecretModel{
+		ID:        types.StringValue("secret-1"),
+		Name:      types.StringValue("db-credentials"),
+		Namespace: types.StringValue("default"),
+		Data: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"username": types.StringValue("app"),
+			"password": types.StringValue("hunter2"),
+		}),
+	}
+
+	read := func(t *testing.T) NixernetesSecretModel {
+		t.Helper()
+		resp := resource.ReadResponse{State: testState(t, r, state)}
+		r.Read(context.Background(), resource.ReadRequest{State: resp.State}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+		}
+		var got NixernetesSecretModel
+		resp.State.Get(context.Background(), &got)
+		return got
+	}
+
+	t.Run("redacted", func(t *testing.T) {
+		response = map[string]interface{}{
+			"id": "secret-1", "name": "db-credentials", "namespace": "default",
+			"data": map[string]interface{}{"username": "********", "password": "[REDACTED]"},
+		}
+		if got := read(t); !got.Data.Equal(state.Data) {
+			t.Errorf("Expected redacted values to keep the values in state, got %v", got.Data)
+		}
+	})
+
+	t.Run("without data", func(t *testing.T) {
+		response = map[string]interface{}{"id": "secret-1", "name": "db-credentials", "namespace": "default"}
+		if got := read(t); !got.Data.Equal(state.Data) {
+			t.Errorf("Expected data to be unchanged, got %v", got.Data)
+		}
+	})
+
+	t.Run("keys changed outside Terraform", func(t *testing.T) {
+		response = map[string]interface{}{
+			"id": "secret-1", "name": "db-credentials", "namespace": "default",
+			"data": map[string]interface{}{"password": "", "api_key": "****"},
+		}
+		want := types.MapValueMust(types.StringType, map[string]attr.Value{
+			"password": types.StringValue("hunter2"),
+			"api_key":  types.StringValue("****"),
+		})
+		if got := read(t); !got.Data.Equal(want) {
+			t.Errorf("Expected %v, got %v", want, got.Data)
+		}
+	})
+}
+
+func TestModuleGroupResourceCreatePartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/modules/batch" {
+			t.Errorf("Expected a single batch request, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": []map[string]interface{}{
+			{"status": 201, "response": map[string]interface{}{"id": "module-1", "name": "api", "image": "acme/api:1.0", "namespace": "default", "replicas": 1}},
+			{"status": 409, "error": map[string]interface{}{"message": "module worker already exists"}},
+		}})
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleGroupResource{client: &NixernetesClient{Endpoint: server.URL}}
+	plan := NixernetesModuleGroupModel{
+		ID: types.StringUnknown(),
+		Modules: []NixernetesModuleGroupEntryModel{
+			{ID: types.StringUnknown(), Name: types.StringValue("api"), Image: types.StringValue("acme/api:1.0"), Namespace: types.StringUnknown(), Replicas: types.Int64Unknown()},
+			{ID: types.StringUnknown(), Name: types.StringValue("worker"), Image: types.StringValue("acme/worker:1.0"), Namespace: types.StringUnknown(), Replicas: types.Int64Value(2)},
+		},
+	}
+
+	resp := resource.CreateResponse{State: testState(t, r, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: testPlan(t, r, plan)}, &resp)
+
+	errs := resp.Diagnostics.Errors()
+	if len(errs) != 1 || !strings.Contains(errs[0].Detail(), "module worker already exists") {
+		t.Fatalf("Expected one error for worker, got %v", resp.Diagnostics)
+	}
+	if withPath, ok := errs[0].(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(path.Root("modules").AtListIndex(1)) {
+		t.Errorf("Expected the error attached to modules[1], got %v", errs[0])
+	}
+
+	var got NixernetesModuleGroupModel
+	resp.State.Get(context.Background(), &got)
+	if got.ID.IsNull() || len(got.Modules) != 1 {
+		t.Fatalf("Expected the group saved with the created module only, got %+v", got)
+	}
+	if api := got.Modules[0]; api.ID.ValueString() != "module-1" || api.Namespace.ValueString() != "default" || api.Replicas.ValueInt64() != 1 {
+		t.Errorf("Expected api created in default with 1 replica, got %+v", api)
+	}
+}
+
+func TestModuleGroupResourceUpdate(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /modules/module-1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "module-1", "image": "acme/api:2.0", "replicas": 1})
+		case "POST /modules/batch":
+			json.NewEncoder(w).Encode(map[string]interface{}{"results": []map[string]interface{}{
+				{"status": 201, "response": map[string]interface{}{"id": "module-3", "namespace": "default", "replicas": 1}},
+			}})
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	r := &NixernetesModuleGroupResource{client: &NixernetesClient{Endpoint: server.URL}}
+	state := NixernetesModuleGroupModel{
+		ID: types.StringValue("group-1"),
+		Modules: []NixernetesModuleGroupEntryModel{
+			{ID: types.StringValue("module-1"), Name: types.StringValue("api"), Image: types.StringValue("acme/api:1.0"), Namespace: types.StringValue("default"), Replicas: types.Int64Value(1)},
+			{ID: types.StringValue("module-2"), Name: types.StringValue("worker"), Image: types.StringValue("acme/worker:1.0"), Namespace: types.StringValue("default"), Replicas: types.Int64Value(1)},
+		},
+	}
+	plan := NixernetesModuleGroupModel{
+		ID: types.StringValue("group-1"),
+		Modules: []NixernetesModuleGroupEntryModel{
+			{ID: types.StringUnknown(), Name: types.StringValue("cron"), Image: types.StringValue("acme/cron:1.0"), Namespace: types.StringUnknown(), Replicas: types.Int64Unknown()},
+			{ID: types.StringUnknown(), Name: types.StringValue("api"), Image: types.StringValue("acme/api:2.0"), Namespace: types.StringUnknown(), Replicas: types.Int64Unknown()},
+		},
+	}
+
+	resp := resource.UpdateResponse{State: testState(t, r, state)}
+	r.Update(context.Background(), resource.UpdateRequest{Plan: testPlan(t, r, plan), State: testState(t, r, state)}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	want := []string{"DELETE /modules/module-2", "PUT /modules/module-1", "POST /modules/batch"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+
+	var got NixernetesModuleGroupModel
+	resp.State.Get(context.Background(), &got)
+	if len(got.Modules) != 2 {
+		t.Fatalf("Expected cron and api, got %+v", got.Modules)
+	}
+	if cron := got.Modules[0]; cron.ID.ValueString() != "module-3" || cron.Name.ValueString() != "cron" {
+		t.Errorf("Expected cron created as module-3, got %+v", cron)
+	}
+	if api := got.Modules[1]; api.ID.ValueString() != "module-1" || api.Image.ValueString() != "acme/api:2.0" || api.Namespace.ValueString() != "default" {
+		t.Errorf("Expected api updated in place, got %+v", api)
+	}
+}
+
+func TestModuleGroupResourceValidateConfig(t *testing.T) {
+	r := &NixernetesModuleGroupResource{}
+	module := func(name, image string) NixernetesModuleGroupEntryModel {
+		return NixernetesModuleGroupEntryModel{Name: types.StringValue(name), Image: types.StringValue(image)}
+	}
+	for _, tt := range []struct {
+		name     string
+		modules  []NixernetesModuleGroupEntryModel
+		wantPath path.Path
+	}{
+		{"valid", []NixernetesModuleGroupEntryModel{module("api", "acme/api:1.0"), module("worker", "acme/worker:1.0")}, path.Empty()},
+		{"empty", []NixernetesModuleGroupEntryModel{}, path.Root("modules")},
+		{"duplicate name", []NixernetesModuleGroupEntryModel{module("api", "acme/api:1.0"), module("api", "acme/worker:1.0")}, path.Root("modules").AtListIndex(1).AtName("name")},
+		{"invalid image", []NixernetesModuleGroupEntryModel{module("api", "acme/api:1.0"), module("worker", "not an image")}, path.Root("modules").AtListIndex(1).AtName("image")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := testPlan(t, r, NixernetesModuleGroupModel{Modules: tt.modules})
+			req := resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw}}
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(context.Background(), req, &resp)
+
+			if tt.wantPath.Equal(path.Empty()) {
+				if resp.Diagnostics.HasError() {
+					t.Errorf("Unexpected diagnostics: %v", resp.Diagnostics)
+				}
+				return
+			}
+			errs := resp.Diagnostics.Errors()
+			if len(errs) != 1 {
+				t.Fatalf("Expected one error, got %v", resp.Diagnostics)
+			}
+			if withPath, ok := errs[0].(diag.DiagnosticWithPath); !ok || !withPath.Path().Equal(tt.wantPath) {
+				t.Errorf("Expected the error at %s, got %v", tt.wantPath, errs[0])
+			}
+		})
+	}
+}
@@ -1,12 +1,22 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 // ValidationError represents a validation error with field and message
@@ -45,8 +55,70 @@ func (v *Validator) ToDiagnostics() diag.Diagnostics {
 	return diags
 }
 
-// ValidateConfigModel validates a NixernetesConfigModel
-func ValidateConfigModel(ctx context.Context, config *NixernetesConfigModel) *Validator {
+// ToDiagnosticsWithPath converts validation errors to Terraform diagnostics
+// attached to the attribute each field maps to in paths, so Terraform can
+// point at the offending line. A field without a path of its own, such as
+// "network_policies.ingress[0].ports", is attached to its nearest mapped
+// parent; one with none at all becomes a plain error.
+func (v *Validator) ToDiagnosticsWithPath(paths map[string]path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, err := range v.Errors {
+		summary := fmt.Sprintf("Invalid %s", err.Field)
+		if p, ok := fieldPath(paths, err.Field); ok {
+			diags.AddAttributeError(p, summary, err.Message)
+		} else {
+			diags.AddError(summary, err.Message)
+		}
+	}
+	return diags
+}
+
+// fieldPath looks field up in paths, falling back to its parents.
+func fieldPath(paths map[string]path.Path, field string) (path.Path, bool) {
+	for field != "" {
+		if p, ok := paths[field]; ok {
+			return p, true
+		}
+		field = field[:max(strings.LastIndexAny(field, ".["), 0)]
+	}
+	return path.Empty(), false
+}
+
+// rootPaths maps each of the given top-level attribute names to its path.
+func rootPaths(names ...string) map[string]path.Path {
+	paths := make(map[string]path.Path, len(names))
+	for _, name := range names {
+		paths[name] = path.Root(name)
+	}
+	return paths
+}
+
+// defaultMaxConfigSize is the largest configuration, in bytes, accepted when
+// the API server does not advertise its own limit.
+const defaultMaxConfigSize = 1024 * 1024
+
+// maxConfigSize returns the configuration size limit advertised by the API
+// server, or defaultMaxConfigSize when there is none or no client yet.
+func maxConfigSize(client *NixernetesClient) int64 {
+	if client == nil || client.MaxConfigSize <= 0 {
+		return defaultMaxConfigSize
+	}
+	return client.MaxConfigSize
+}
+
+// checkConfigSize rejects configuration content larger than limit bytes.
+func checkConfigSize(content string, limit int64) error {
+	if int64(len(content)) > limit {
+		return fmt.Errorf("configuration is %d bytes, which exceeds the maximum configuration size of %d bytes", len(content), limit)
+	}
+	return nil
+}
+
+// ValidateConfigModel validates a NixernetesConfigModel against maxSize,
+// the largest configuration in bytes the server accepts; see maxConfigSize.
+// When serverSchema is not nil, a JSON configuration is also checked
+// against it.
+func ValidateConfigModel(ctx context.Context, config *NixernetesConfigModel, serverSchema *jsonschema.Schema, maxSize int64) *Validator {
 	v := &Validator{}
 
 	tflog.Debug(ctx, "Validating config model", map[string]any{
@@ -67,22 +139,112 @@ func ValidateConfigModel(ctx context.Context, config *NixernetesConfigModel) *Va
 		v.AddError("name", "Name must contain only alphanumeric characters, hyphens, and underscores")
 	}
 
-	// Validate configuration
-	if config.Configuration.IsNull() || config.Configuration.ValueString() == "" {
-		v.AddError("configuration", "Configuration content is required and cannot be empty")
+	// Validate configuration, decoding configuration_base64 when it is used
+	field := "configuration"
+	if !config.ConfigurationBase64.IsNull() {
+		field = "configuration_base64"
+	}
+	content, err := config.content()
+	if err != nil {
+		v.AddError(field, err.Error())
+	} else if content == "" {
+		v.AddError(field, "Configuration content is required and cannot be empty")
+	} else if err := checkConfigSize(content, maxSize); err != nil {
+		v.AddError(field, err.Error())
+	} else if err := checkNixSyntax(content); err != nil {
+		v.AddError(field, err.Error())
+	} else if serverSchema != nil {
+		validateConfigSchema(ctx, v, serverSchema, content)
 	}
 
 	// Validate environment if provided
-	if !config.Environment.IsNull() {
+	if !config.Environment.IsNull() && !config.Environment.IsUnknown() {
 		env := config.Environment.ValueString()
 		if !isValidEnvironment(env) {
 			v.AddError("environment", "Environment must be 'development', 'staging', or 'production'")
 		}
 	}
 
+	// Validate priority if provided
+	if !config.Priority.IsNull() && !config.Priority.IsUnknown() {
+		if p := config.Priority.ValueInt64(); p < 0 || p > maxConfigPriority {
+			v.AddError("priority", fmt.Sprintf("Priority must be between 0 and %d", maxConfigPriority))
+		}
+	}
+
 	return v
 }
 
+// configFieldPaths maps the fields ValidateConfigModel reports to their
+// attributes.
+var configFieldPaths = rootPaths("name", "configuration", "configuration_base64", "environment", "priority")
+
+// maxConfigPriority is the highest priority a configuration can have.
+const maxConfigPriority = 1000
+
+// compileConfigSchema compiles the configuration schema published by the API
+// server.
+func compileConfigSchema(raw map[string]interface{}) (*jsonschema.Schema, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration schema: %w", err)
+	}
+
+	const schemaURL = "nixernetes-config-schema.json"
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(schemaURL, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid configuration schema: %w", err)
+	}
+	schema, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration schema: %w", err)
+	}
+	return schema, nil
+}
+
+// validateConfigSchema checks a configuration against the API server's schema,
+// adding an error with the JSON pointer of each offending value. Only JSON
+// configurations can be checked; Nix configurations are left to the server.
+func validateConfigSchema(ctx context.Context, v *Validator, schema *jsonschema.Schema, configuration string) {
+	decoder := json.NewDecoder(strings.NewReader(configuration))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		tflog.Debug(ctx, "Configuration is not JSON, skipping server schema validation")
+		return
+	}
+
+	err := schema.Validate(doc)
+	if err == nil {
+		return
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		v.AddError("configuration", err.Error())
+		return
+	}
+	for _, cause := range schemaViolations(verr) {
+		location := cause.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		v.AddError("configuration", fmt.Sprintf("%s: %s", location, cause.Message))
+	}
+}
+
+// schemaViolations flattens a schema validation error into its leaf causes,
+// which name the exact value and rule that failed.
+func schemaViolations(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, schemaViolations(cause)...)
+	}
+	return leaves
+}
+
 // ValidateModuleModel validates a NixernetesModuleModel
 func ValidateModuleModel(ctx context.Context, module *NixernetesModuleModel) *Validator {
 	v := &Validator{}
@@ -112,31 +274,194 @@ func ValidateModuleModel(ctx context.Context, module *NixernetesModuleModel) *Va
 		image := module.Image.ValueString()
 		if !isValidImage(image) {
 			v.AddError("image", "Image must be in format 'registry/repository:tag' or 'repository:tag'")
+		} else if normalizeEnvironment(module.Environment.ValueString()) == "production" {
+			// Production nodes cannot pull from a developer's local registry
+			if ref, _ := parseImageReference(image); isLocalRegistry(ref.Registry) {
+				v.AddError("image", fmt.Sprintf("Image %q uses the local registry %q, which is not reachable in production; push the image to a shared registry", image, ref.Registry))
+			}
+		}
+	}
+
+	// Validate project ID if provided
+	if !module.ProjectID.IsNull() && !module.ProjectID.IsUnknown() {
+		if !isValidID(module.ProjectID.ValueString()) {
+			v.AddError("project_id", "Project ID must be 1-64 characters of letters, digits, hyphens and underscores, starting with a letter or digit")
+		}
+	}
+
+	// Validate environment if provided
+	if !module.Environment.IsNull() {
+		if !isValidEnvironment(module.Environment.ValueString()) {
+			v.AddError("environment", "Environment must be 'development', 'staging', or 'production'")
 		}
 	}
 
 	// Validate replicas if provided
-	if !module.Replicas.IsNull() {
+	if !module.Replicas.IsNull() && !module.Replicas.IsUnknown() {
 		replicas := module.Replicas.ValueInt64()
 		if replicas < 0 {
 			v.AddError("replicas", "Replicas cannot be negative")
 		}
-		if replicas > 100 {
-			v.AddError("replicas", "Replicas cannot exceed 100")
+		if replicas > maxModuleReplicas {
+			v.AddError("replicas", fmt.Sprintf("Replicas cannot exceed %d", maxModuleReplicas))
+		}
+	}
+
+	// Validate readiness settings if provided
+	if !module.ReadyTimeout.IsNull() {
+		if d, err := time.ParseDuration(module.ReadyTimeout.ValueString()); err != nil || d <= 0 {
+			v.AddError("ready_timeout", "Ready timeout must be a positive duration such as '5m'")
+		}
+	}
+	if !module.OnReadyTimeout.IsNull() && !module.OnReadyTimeout.IsUnknown() {
+		switch module.OnReadyTimeout.ValueString() {
+		case onReadyTimeoutFail, onReadyTimeoutTaint, onReadyTimeoutContinue:
+		default:
+			v.AddError("on_ready_timeout", "On ready timeout must be 'fail', 'taint', or 'continue'")
+		}
+	}
+
+	// Validate autoscaling if provided
+	if a := module.Autoscaling; a != nil {
+		minReplicas, maxReplicas := a.MinReplicas.ValueInt64(), a.MaxReplicas.ValueInt64()
+		if minReplicas < 1 {
+			v.AddError("autoscaling.min_replicas", "Minimum replicas must be at least 1")
+		}
+		if maxReplicas > 100 {
+			v.AddError("autoscaling.max_replicas", "Maximum replicas cannot exceed 100")
+		}
+		if minReplicas > maxReplicas {
+			v.AddError("autoscaling", fmt.Sprintf("Minimum replicas (%d) cannot exceed maximum replicas (%d)", minReplicas, maxReplicas))
+		}
+		if !a.TargetCPUUtilization.IsNull() {
+			target := a.TargetCPUUtilization.ValueInt64()
+			if target < 1 || target > 100 {
+				v.AddError("autoscaling.target_cpu_utilization", "Target CPU utilization must be between 1 and 100 percent")
+			}
 		}
 	}
 
 	// Validate namespace if provided
-	if !module.Namespace.IsNull() {
+	if !module.Namespace.IsNull() && !module.Namespace.IsUnknown() {
 		ns := module.Namespace.ValueString()
 		if !isValidNamespace(ns) {
 			v.AddError("namespace", "Namespace must be a valid Kubernetes namespace name")
 		}
 	}
 
+	// Validate service account if provided
+	if !module.ServiceAccount.IsNull() && !module.ServiceAccount.IsUnknown() {
+		if !isValidDNSSubdomain(module.ServiceAccount.ValueString()) {
+			v.AddError("service_account", "Service account must be a DNS-1123 subdomain: lowercase alphanumerics, '-' and '.', starting and ending with an alphanumeric, at most 253 characters")
+		}
+	}
+
+	// Validate termination grace period if provided
+	if !module.TerminationGracePeriodSeconds.IsNull() && !module.TerminationGracePeriodSeconds.IsUnknown() {
+		seconds := module.TerminationGracePeriodSeconds.ValueInt64()
+		if seconds < 0 || seconds > maxTerminationGracePeriodSeconds {
+			v.AddError("termination_grace_period_seconds", fmt.Sprintf("Termination grace period must be between 0 and %d seconds", maxTerminationGracePeriodSeconds))
+		}
+	}
+
+	// Validate platform if provided
+	if !module.Platform.IsNull() && !module.Platform.IsUnknown() {
+		if !isValidPlatform(module.Platform.ValueString()) {
+			v.AddError("platform", fmt.Sprintf("Platform must be one of %s", strings.Join(modulePlatforms, ", ")))
+		}
+	}
+
+	// Validate volumes and the mounts that reference them
+	volumeNames := make(map[string]bool)
+	for _, volume := range module.Volumes {
+		name := volume.Name.ValueString()
+		if !isValidDNSLabel(name) {
+			v.AddError("volumes", fmt.Sprintf("Volume name %q must be a valid DNS label", name))
+		}
+		if volumeNames[name] {
+			v.AddError("volumes", fmt.Sprintf("Volume name %q is defined more than once", name))
+		}
+		volumeNames[name] = true
+
+		if !isValidVolumeType(volume.Type.ValueString()) {
+			v.AddError("volumes", "Volume type must be 'emptyDir', 'pvc', 'configMap', or 'secret'")
+		}
+		if !volume.Size.IsNull() && !isValidQuantity(volume.Size.ValueString()) {
+			v.AddError("volumes", fmt.Sprintf("Volume size %q must be a Kubernetes quantity such as '10Gi'", volume.Size.ValueString()))
+		}
+	}
+
+	for _, mount := range module.VolumeMounts {
+		name := mount.Name.ValueString()
+		if !volumeNames[name] {
+			v.AddError("volume_mounts", fmt.Sprintf("Volume mount %q does not reference a defined volume", name))
+		}
+		if !strings.HasPrefix(mount.MountPath.ValueString(), "/") {
+			v.AddError("volume_mounts", fmt.Sprintf("Mount path %q must be an absolute path", mount.MountPath.ValueString()))
+		}
+	}
+
+	// Validate network policies if provided
+	if p := module.NetworkPolicies; p != nil {
+		validateNetworkPolicyRules(v, "network_policies.ingress", p.Ingress)
+		validateNetworkPolicyRules(v, "network_policies.egress", p.Egress)
+	}
+
 	return v
 }
 
+// moduleFieldPaths maps the fields ValidateModuleModel reports to their
+// attributes.
+var moduleFieldPaths = func() map[string]path.Path {
+	paths := rootPaths(
+		"name", "image", "project_id", "environment", "replicas", "ready_timeout", "on_ready_timeout",
+		"autoscaling", "namespace", "service_account", "termination_grace_period_seconds", "platform",
+		"volumes", "volume_mounts", "network_policies",
+	)
+	for _, name := range []string{"min_replicas", "max_replicas", "target_cpu_utilization"} {
+		paths["autoscaling."+name] = path.Root("autoscaling").AtName(name)
+	}
+	for _, name := range []string{"ingress", "egress"} {
+		paths["network_policies."+name] = path.Root("network_policies").AtName(name)
+	}
+	return paths
+}()
+
+// defaultNetworkPolicyProtocol is the protocol of network policy ports that
+// do not name one.
+const defaultNetworkPolicyProtocol = "TCP"
+
+// validateNetworkPolicyRules checks the ports, protocols and CIDRs of a
+// module's network policy rules.
+func validateNetworkPolicyRules(v *Validator, field string, rules []NixernetesNetworkPolicyRuleModel) {
+	for i, rule := range rules {
+		ruleField := fmt.Sprintf("%s[%d]", field, i)
+		for _, port := range rule.Ports {
+			if port.IsUnknown() {
+				continue
+			}
+			if p := port.ValueInt64(); p < 1 || p > 65535 {
+				v.AddError(ruleField+".ports", fmt.Sprintf("Port %d must be between 1 and 65535", p))
+			}
+		}
+		if !rule.Protocol.IsNull() && !rule.Protocol.IsUnknown() {
+			switch rule.Protocol.ValueString() {
+			case "TCP", "UDP", "SCTP":
+			default:
+				v.AddError(ruleField+".protocol", "Protocol must be 'TCP', 'UDP', or 'SCTP'")
+			}
+		}
+		for _, cidr := range rule.CIDRs {
+			if cidr.IsUnknown() {
+				continue
+			}
+			if _, _, err := net.ParseCIDR(cidr.ValueString()); err != nil {
+				v.AddError(ruleField+".cidrs", fmt.Sprintf("%q is not a valid CIDR such as '10.0.0.0/8'", cidr.ValueString()))
+			}
+		}
+	}
+}
+
 // ValidateProjectModel validates a NixernetesProjectModel
 func ValidateProjectModel(ctx context.Context, project *NixernetesProjectModel) *Validator {
 	v := &Validator{}
@@ -167,9 +492,186 @@ func ValidateProjectModel(ctx context.Context, project *NixernetesProjectModel)
 		}
 	}
 
+	// Validate default namespace if provided
+	if !project.DefaultNamespace.IsNull() {
+		if !isValidNamespace(project.DefaultNamespace.ValueString()) {
+			v.AddError("default_namespace", "Default namespace must be a valid Kubernetes namespace name")
+		}
+	}
+
+	return v
+}
+
+// projectFieldPaths maps the fields ValidateProjectModel reports to their
+// attributes.
+var projectFieldPaths = rootPaths("name", "description", "default_namespace")
+
+// ValidateResourceQuotaModel validates a NixernetesResourceQuotaModel
+func ValidateResourceQuotaModel(ctx context.Context, quota *NixernetesResourceQuotaModel) *Validator {
+	v := &Validator{}
+
+	tflog.Debug(ctx, "Validating resource quota model", map[string]any{
+		"namespace": quota.Namespace.ValueString(),
+	})
+
+	if !isValidNamespace(quota.Namespace.ValueString()) {
+		v.AddError("namespace", "Namespace must be a valid Kubernetes namespace name")
+	}
+
+	if !quota.CPU.IsNull() && !isValidQuantity(quota.CPU.ValueString()) {
+		v.AddError("cpu", fmt.Sprintf("CPU limit %q is not a valid Kubernetes quantity", quota.CPU.ValueString()))
+	}
+
+	if !quota.Memory.IsNull() && !isValidQuantity(quota.Memory.ValueString()) {
+		v.AddError("memory", fmt.Sprintf("Memory limit %q is not a valid Kubernetes quantity", quota.Memory.ValueString()))
+	}
+
+	if !quota.Pods.IsNull() && quota.Pods.ValueInt64() < 0 {
+		v.AddError("pods", "Pod limit cannot be negative")
+	}
+
+	if quota.CPU.IsNull() && quota.Memory.IsNull() && quota.Pods.IsNull() {
+		v.AddError("quota", "At least one of cpu, memory or pods must be set")
+	}
+
+	return v
+}
+
+// quotaFieldPaths maps the fields ValidateResourceQuotaModel reports to their
+// attributes; "quota" concerns them all and has no path.
+var quotaFieldPaths = rootPaths("namespace", "cpu", "memory", "pods")
+
+// ValidateModuleRolloutModel validates a NixernetesModuleRolloutModel
+func ValidateModuleRolloutModel(ctx context.Context, rollout *NixernetesModuleRolloutModel) *Validator {
+	v := &Validator{}
+
+	tflog.Debug(ctx, "Validating module rollout model", map[string]any{
+		"module_id": rollout.ModuleID.ValueString(),
+		"action":    rollout.Action.ValueString(),
+	})
+
+	if !isValidID(rollout.ModuleID.ValueString()) {
+		v.AddError("module_id", "Module ID must be 1-64 characters of letters, digits, hyphens and underscores, starting with a letter or digit")
+	}
+
+	switch rollout.Action.ValueString() {
+	case rolloutActionPause, rolloutActionResume, rolloutActionAbort:
+	default:
+		v.AddError("action", "Action must be 'pause', 'resume', or 'abort'")
+	}
+
+	return v
+}
+
+// rolloutFieldPaths maps the fields ValidateModuleRolloutModel reports to
+// their attributes.
+var rolloutFieldPaths = rootPaths("module_id", "action")
+
+// ValidateSecretModel validates a NixernetesSecretModel. Unknown values are
+// skipped, so it can check a configuration before they are known. Secret
+// values themselves are never checked or logged.
+func ValidateSecretModel(ctx context.Context, secret *NixernetesSecretModel) *Validator {
+	v := &Validator{}
+
+	tflog.Debug(ctx, "Validating secret model", map[string]any{
+		"name":      secret.Name.ValueString(),
+		"namespace": secret.Namespace.ValueString(),
+	})
+
+	if !secret.Name.IsNull() && !secret.Name.IsUnknown() && !isValidDNSSubdomain(secret.Name.ValueString()) {
+		v.AddError("name", "Name must be a DNS-1123 subdomain: lowercase alphanumerics, '-' and '.', starting and ending with an alphanumeric, at most 253 characters")
+	}
+
+	if !secret.Namespace.IsNull() && !secret.Namespace.IsUnknown() && !isValidNamespace(secret.Namespace.ValueString()) {
+		v.AddError("namespace", "Namespace must be a valid Kubernetes namespace name")
+	}
+
+	if !secret.Data.IsUnknown() {
+		keys := make([]string, 0, len(secret.Data.Elements()))
+		for key := range secret.Data.Elements() {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !isValidSecretKey(key) {
+				v.AddError("data", fmt.Sprintf("Key %q must be at most 253 characters of letters, digits, '-', '_' and '.'", key))
+			}
+		}
+	}
+
+	return v
+}
+
+// secretFieldPaths maps the fields ValidateSecretModel reports to their
+// attributes.
+var secretFieldPaths = rootPaths("name", "namespace", "data")
+
+// ValidateModuleGroupModel validates a NixernetesModuleGroupModel. Unknown
+// values are skipped, so it can check a configuration before they are known.
+func ValidateModuleGroupModel(ctx context.Context, group *NixernetesModuleGroupModel) *Validator {
+	v := &Validator{}
+
+	tflog.Debug(ctx, "Validating module group model", map[string]any{
+		"modules": len(group.Modules),
+	})
+
+	if len(group.Modules) == 0 {
+		v.AddError("modules", "A module group must contain at least one module")
+	}
+
+	seen := make(map[string]bool, len(group.Modules))
+	for i, module := range group.Modules {
+		field := fmt.Sprintf("modules[%d].", i)
+
+		if !module.Name.IsUnknown() {
+			name := module.Name.ValueString()
+			switch {
+			case name == "" || len(name) > 255 || !isValidName(name):
+				v.AddError(field+"name", "Name must be 1-255 alphanumeric characters, hyphens, and underscores")
+			case seen[name]:
+				v.AddError(field+"name", fmt.Sprintf("Name %q is used by more than one module of the group", name))
+			}
+			seen[name] = true
+		}
+
+		if !module.Image.IsUnknown() && !isValidImage(module.Image.ValueString()) {
+			v.AddError(field+"image", "Image must be in format 'registry/repository:tag' or 'repository:tag'")
+		}
+
+		if !module.Namespace.IsNull() && !module.Namespace.IsUnknown() && !isValidNamespace(module.Namespace.ValueString()) {
+			v.AddError(field+"namespace", "Namespace must be a valid Kubernetes namespace name")
+		}
+
+		if !module.Replicas.IsNull() && !module.Replicas.IsUnknown() {
+			if replicas := module.Replicas.ValueInt64(); replicas < 0 || replicas > maxModuleReplicas {
+				v.AddError(field+"replicas", fmt.Sprintf("Replicas must be between 0 and %d", maxModuleReplicas))
+			}
+		}
+	}
+
 	return v
 }
 
+// moduleGroupFieldPaths maps the fields ValidateModuleGroupModel reports for
+// a group of n modules to their attributes.
+func moduleGroupFieldPaths(n int) map[string]path.Path {
+	paths := rootPaths("modules")
+	for i := 0; i < n; i++ {
+		for _, name := range []string{"name", "image", "namespace", "replicas"} {
+			paths[fmt.Sprintf("modules[%d].%s", i, name)] = path.Root("modules").AtListIndex(i).AtName(name)
+		}
+	}
+	return paths
+}
+
+// secretKeyPattern matches a Kubernetes secret data key.
+var secretKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// isValidSecretKey validates a key of a secret's data map.
+func isValidSecretKey(key string) bool {
+	return len(key) <= 253 && secretKeyPattern.MatchString(key)
+}
+
 // isValidName validates a resource name
 func isValidName(name string) bool {
 	if len(name) == 0 {
@@ -191,6 +693,115 @@ func isValidName(name string) bool {
 	return true
 }
 
+// isValidID validates an API object ID such as "proj-123"
+func isValidID(id string) bool {
+	if len(id) == 0 || len(id) > 64 {
+		return false
+	}
+
+	// ID must start with a letter or digit
+	if !isAlphaNumeric(rune(id[0])) {
+		return false
+	}
+
+	for _, r := range id {
+		if !isAlphaNumeric(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkNixSyntax performs a lightweight syntax check of Nix content: braces,
+// brackets and parentheses must balance, strings and block comments must be
+// terminated, and there must be an attribute set { ... } outside comments and
+// strings. It does not evaluate the expression.
+func checkNixSyntax(content string) error {
+	if err := scanNixSyntax(content); err != nil {
+		return fmt.Errorf("configuration does not appear to be a valid Nix expression: %w", err)
+	}
+	return nil
+}
+
+func scanNixSyntax(content string) error {
+	closers := map[rune]rune{'}': '{', ']': '[', ')': '('}
+	var stack []rune
+	line := 1
+	sawCode, sawAttrSet := false, false
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\n':
+			line++
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			i--
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			for i += 2; i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(runes) {
+				return fmt.Errorf("unterminated comment starting on line %d", start)
+			}
+			i++
+		case r == '"':
+			sawCode = true
+			start := line
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' {
+					i++
+				} else if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(runes) {
+				return fmt.Errorf("unterminated string starting on line %d", start)
+			}
+		case r == '\'' && i+1 < len(runes) && runes[i+1] == '\'':
+			sawCode = true
+			start := line
+			for i += 2; i < len(runes) && !(runes[i] == '\'' && i+1 < len(runes) && runes[i+1] == '\''); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(runes) {
+				return fmt.Errorf("unterminated indented string starting on line %d", start)
+			}
+			i++
+		case r == '{' || r == '[' || r == '(':
+			sawCode = true
+			sawAttrSet = sawAttrSet || r == '{'
+			stack = append(stack, r)
+		case closers[r] != 0:
+			if len(stack) == 0 || stack[len(stack)-1] != closers[r] {
+				return fmt.Errorf("unexpected '%c' on line %d", r, line)
+			}
+			stack = stack[:len(stack)-1]
+		case !unicode.IsSpace(r):
+			sawCode = true
+		}
+	}
+
+	switch {
+	case len(stack) > 0:
+		return fmt.Errorf("unclosed '%c'", stack[len(stack)-1])
+	case !sawCode:
+		return fmt.Errorf("it contains only whitespace and comments")
+	case !sawAttrSet:
+		return fmt.Errorf("no attribute set { ... } found")
+	}
+	return nil
+}
+
 // isValidEnvironment validates an environment name
 func isValidEnvironment(env string) bool {
 	validEnvs := map[string]bool{
@@ -198,47 +809,247 @@ func isValidEnvironment(env string) bool {
 		"staging":     true,
 		"production":  true,
 	}
-	return validEnvs[strings.ToLower(env)]
+	return validEnvs[normalizeEnvironment(env)]
+}
+
+// normalizeEnvironment returns the canonical, lowercase form of an
+// environment name, which is what the provider sends to the API and records
+// in state.
+func normalizeEnvironment(env string) string {
+	return strings.ToLower(env)
+}
+
+// maxTerminationGracePeriodSeconds bounds termination_grace_period_seconds;
+// a module that needs more than an hour to drain is almost certainly stuck.
+const maxTerminationGracePeriodSeconds = 3600
+
+// modulePlatforms are the platforms modules can be pinned to.
+var modulePlatforms = []string{"linux/amd64", "linux/arm64"}
+
+// isValidPlatform validates a module platform
+func isValidPlatform(platform string) bool {
+	for _, p := range modulePlatforms {
+		if platform == p {
+			return true
+		}
+	}
+	return false
+}
+
+// maxModuleReplicas is the largest replica count a module may request.
+const maxModuleReplicas = 100
+
+// shellMetacharacters are characters a shell treats specially. None of them
+// can appear in an image reference, so an image containing one is most
+// likely an injection attempt or a copy-paste mistake.
+const shellMetacharacters = "$`;|&<>()\\\"'*?!{}[] \t\n"
+
+// shellMetacharacter returns the first shell metacharacter in s, if any.
+func shellMetacharacter(s string) (rune, bool) {
+	if i := strings.IndexAny(s, shellMetacharacters); i >= 0 {
+		return []rune(s[i:])[0], true
+	}
+	return 0, false
 }
 
 // isValidImage validates a container image reference
 func isValidImage(image string) bool {
-	// Basic validation for image format
-	if len(image) == 0 {
-		return false
+	_, err := parseImageReference(image)
+	return err == nil
+}
+
+const (
+	defaultImageRegistry = "docker.io"
+	defaultImageTag      = "latest"
+)
+
+var (
+	imageRegistryPattern  = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*(:[0-9]+)?$`)
+	imageComponentPattern = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*$`)
+	imageTagPattern       = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+	imageDigestPattern    = regexp.MustCompile(`^[a-z0-9]+([+._-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+)
+
+// imageReference is a parsed container image reference. Registry and Tag are
+// empty when the reference omits them.
+type imageReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// String formats the reference back into registry/repository:tag@digest form.
+func (r imageReference) String() string {
+	s := r.Repository
+	if r.Registry != "" {
+		s = r.Registry + "/" + s
+	}
+	if r.Tag != "" {
+		s += ":" + r.Tag
 	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
 
-	// Check for invalid characters
-	invalidChars := []string{"<", ">", "`", "$", "&", "|", ";"}
-	for _, char := range invalidChars {
-		if strings.Contains(image, char) {
-			return false
+// parseImageReference splits an image reference into its registry,
+// repository, tag and digest, rejecting anything a container runtime would.
+func parseImageReference(image string) (imageReference, error) {
+	var ref imageReference
+
+	if image == "" {
+		return ref, fmt.Errorf("image reference is empty")
+	}
+
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+		if !imageDigestPattern.MatchString(ref.Digest) {
+			return ref, fmt.Errorf("invalid digest %q", ref.Digest)
+		}
+	}
+
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+		if !imageTagPattern.MatchString(ref.Tag) {
+			return ref, fmt.Errorf("invalid tag %q", ref.Tag)
 		}
 	}
 
-	// Must contain at least a name
-	parts := strings.Split(image, ":")
-	if len(parts) > 2 {
-		return false // Too many colons
+	// The first component is a registry host only if it looks like one;
+	// otherwise it is part of the repository (e.g. "library/nginx").
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			if !imageRegistryPattern.MatchString(host) {
+				return ref, fmt.Errorf("invalid registry %q", host)
+			}
+			ref.Registry = host
+			name = name[i+1:]
+		}
 	}
 
-	return true
+	for _, component := range strings.Split(name, "/") {
+		if !imageComponentPattern.MatchString(component) {
+			return ref, fmt.Errorf("invalid repository %q", name)
+		}
+	}
+	ref.Repository = name
+
+	return ref, nil
+}
+
+// isLocalRegistry reports whether a registry host, with or without a port,
+// only resolves on the machine or network it was pushed from.
+func isLocalRegistry(registry string) bool {
+	host := registry
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.ToLower(host)
+	return host == "localhost" || host == "127.0.0.1" || strings.HasSuffix(host, ".local")
 }
 
-// isValidNamespace validates a Kubernetes namespace name
+// isLocalEndpoint reports whether an API endpoint URL points at the local
+// machine, as the placeholder endpoint in the examples does.
+func isLocalEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// canonicalizeImage returns the fully qualified form of an image reference,
+// filling in the default registry, the library namespace for official
+// images, and the latest tag when neither a tag nor a digest is given.
+func canonicalizeImage(image string) (string, error) {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Registry == "" || ref.Registry == "index.docker.io" {
+		ref.Registry = defaultImageRegistry
+	}
+	if ref.Registry == defaultImageRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultImageTag
+	}
+
+	return ref.String(), nil
+}
+
+// isValidNamespace validates a Kubernetes namespace name, which must be an
+// RFC 1123 DNS label
 func isValidNamespace(ns string) bool {
-	if len(ns) == 0 || len(ns) > 63 {
+	return isValidDNSLabel(ns)
+}
+
+// toNamespace derives a Kubernetes namespace name from arbitrary input by
+// lowercasing it, replacing invalid characters with hyphens, trimming leading
+// and trailing hyphens and truncating to 63 characters.
+func toNamespace(input string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToLower(input) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+
+	ns := strings.Trim(b.String(), "-")
+	if len(ns) > 63 {
+		ns = strings.TrimRight(ns[:63], "-")
+	}
+
+	if !isValidNamespace(ns) {
+		return "", fmt.Errorf("%q does not contain any characters usable in a namespace", input)
+	}
+	return ns, nil
+}
+
+// isValidDNSLabel validates an RFC 1123 DNS label
+func isValidDNSLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 {
 		return false
 	}
 
-	// Must start and end with alphanumeric
-	if !isAlphaNumeric(rune(ns[0])) || !isAlphaNumeric(rune(ns[len(ns)-1])) {
+	for i, r := range label {
+		lowerAlphaNumeric := (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+		if lowerAlphaNumeric {
+			continue
+		}
+		// Hyphens are allowed, but not at either end
+		if r != '-' || i == 0 || i == len(label)-1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidDNSSubdomain validates an RFC 1123 DNS subdomain, a dot-separated
+// series of DNS labels of at most 253 characters
+func isValidDNSSubdomain(name string) bool {
+	if len(name) == 0 || len(name) > 253 {
 		return false
 	}
 
-	// Can only contain lowercase alphanumerics and hyphens
-	for _, r := range ns {
-		if r < 'a' || r > 'z' && r < '0' || r > '9' && r != '-' {
+	for _, label := range strings.Split(name, ".") {
+		if !isValidDNSLabel(label) {
 			return false
 		}
 	}
@@ -246,6 +1057,34 @@ func isValidNamespace(ns string) bool {
 	return true
 }
 
+// isValidHost validates a bare hostname or IP address, as used in
+// tls_insecure_hosts; schemes, ports and paths are rejected.
+func isValidHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	return isValidDNSSubdomain(strings.ToLower(host))
+}
+
+// isValidVolumeType validates a module volume type
+func isValidVolumeType(volumeType string) bool {
+	validTypes := map[string]bool{
+		"emptyDir":  true,
+		"pvc":       true,
+		"configMap": true,
+		"secret":    true,
+	}
+	return validTypes[volumeType]
+}
+
+// quantityPattern matches Kubernetes resource quantities such as 500m, 10Gi or 1.5
+var quantityPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(m|k|M|G|T|P|E|Ki|Mi|Gi|Ti|Pi|Ei)?$`)
+
+// isValidQuantity validates a Kubernetes resource quantity
+func isValidQuantity(quantity string) bool {
+	return quantityPattern.MatchString(quantity)
+}
+
 // isAlphaNumeric checks if a rune is alphanumeric
 func isAlphaNumeric(r rune) bool {
 	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
@@ -268,17 +1107,26 @@ func ValidateHTTPError(err error) (message string, retryable bool) {
 		return fmt.Sprintf("Invalid request: %s", httpErr.Message), false
 	case 401: // Unauthorized
 		return fmt.Sprintf("Authentication failed: %s", httpErr.Message), false
+	case 402: // Payment Required, used by the API for quota violations
+		return fmt.Sprintf("Quota exceeded: %s", httpErr.Message), false
 	case 403: // Forbidden
 		return fmt.Sprintf("Access denied: %s", httpErr.Message), false
 	case 404: // Not Found
 		return fmt.Sprintf("Resource not found: %s", httpErr.Message), false
 	case 409: // Conflict
 		return fmt.Sprintf("Resource conflict: %s", httpErr.Message), false
+	case 422: // Unprocessable Entity, the API rejected field values
+		return fmt.Sprintf("Validation failed: %s", httpErr.Message), false
 	case 429: // Too Many Requests
 		return fmt.Sprintf("Rate limited: %s", httpErr.Message), true
 	case 500: // Internal Server Error
 		return fmt.Sprintf("Server error: %s", httpErr.Message), true
-	case 502, 503, 504: // Bad Gateway, Service Unavailable, Gateway Timeout
+	case 503: // Service Unavailable, including planned maintenance
+		if httpErr.Maintenance {
+			return maintenanceMessage(httpErr.MaintenanceEnd), true
+		}
+		return fmt.Sprintf("Service unavailable: %s", httpErr.Message), true
+	case 502, 504: // Bad Gateway, Gateway Timeout
 		return fmt.Sprintf("Service unavailable: %s", httpErr.Message), true
 	default:
 		if httpErr.StatusCode >= 500 {
@@ -287,3 +1135,44 @@ func ValidateHTTPError(err error) (message string, retryable bool) {
 		return fmt.Sprintf("Request failed (HTTP %d): %s", httpErr.StatusCode, httpErr.Message), false
 	}
 }
+
+// fieldErrorDiagnostics returns the field errors of an API rejection as
+// diagnostics attached to the attributes they name in paths, in the same way
+// as local validation errors. It returns nothing when err has no field errors.
+func fieldErrorDiagnostics(err error, paths map[string]path.Path) diag.Diagnostics {
+	httpErr, ok := err.(*HTTPError)
+	if !ok || len(httpErr.FieldErrors) == 0 {
+		return nil
+	}
+	v := &Validator{}
+	for _, e := range httpErr.FieldErrors {
+		field := e.Field
+		if field == "" {
+			field = "request"
+		}
+		v.AddError(field, "The API rejected the value: "+e.Message+httpErr.requestIDSuffix())
+	}
+	return v.ToDiagnosticsWithPath(paths)
+}
+
+// platformMismatchCode is the error code of an API rejection of a
+// digest-pinned image that is not built for the requested platform.
+const platformMismatchCode = "platform_mismatch"
+
+// isPlatformMismatch reports whether err is an API rejection caused by an
+// image digest that does not match the module's platform.
+func isPlatformMismatch(err error) bool {
+	httpErr, ok := err.(*HTTPError)
+	return ok && httpErr.Code == platformMismatchCode
+}
+
+// isQuotaExceeded reports whether err is an API rejection caused by a
+// namespace resource quota.
+func isQuotaExceeded(err error) bool {
+	httpErr, ok := err.(*HTTPError)
+	if !ok {
+		return false
+	}
+	return httpErr.StatusCode == 402 ||
+		(httpErr.StatusCode == 403 && strings.Contains(strings.ToLower(httpErr.Message), "exceeded quota"))
+}
@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -78,6 +82,50 @@ func TestValidateConfigModel(t *testing.T) {
 			wantError: true,
 			errorMsg:  "Environment must be",
 		},
+		{
+			name: "valid priority",
+			model: &NixernetesConfigModel{
+				Name:          types.StringValue("my-config"),
+				Configuration: types.StringValue("{ test }"),
+				Priority:      types.Int64Value(100),
+			},
+			wantError: false,
+		},
+		{
+			name: "priority out of range",
+			model: &NixernetesConfigModel{
+				Name:          types.StringValue("my-config"),
+				Configuration: types.StringValue("{ test }"),
+				Priority:      types.Int64Value(-1),
+			},
+			wantError: true,
+			errorMsg:  "Priority must be between 0 and 1000",
+		},
+		{
+			name: "valid base64 configuration",
+			model: &NixernetesConfigModel{
+				Name:                types.StringValue("my-config"),
+				ConfigurationBase64: types.StringValue("eyBzZXJ2aWNlcy5uZ2lueC5lbmFibGUgPSB0cnVlOyB9"),
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid base64 configuration",
+			model: &NixernetesConfigModel{
+				Name:                types.StringValue("my-config"),
+				ConfigurationBase64: types.StringValue("not base64!"),
+			},
+			wantError: true,
+			errorMsg:  "not valid base64",
+		},
+		{
+			name: "base64 configuration with syntax error",
+			model: &NixernetesConfigModel{
+				Name:                types.StringValue("my-config"),
+				ConfigurationBase64: types.StringValue("eyBzZXJ2aWNlcy5uZ2lueC5lbmFibGUgPSB0cnVlOw=="),
+			},
+			wantError: true,
+		},
 		{
 			name: "valid environment staging",
 			model: &NixernetesConfigModel{
@@ -91,7 +139,7 @@ func TestValidateConfigModel(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			v := ValidateConfigModel(context.Background(), tt.model)
+			v := ValidateConfigModel(context.Background(), tt.model, nil, defaultMaxConfigSize)
 			if tt.wantError && !v.HasErrors() {
 				t.Error("Expected validation error but got none")
 			}
@@ -102,6 +150,124 @@ func TestValidateConfigModel(t *testing.T) {
 	}
 }
 
+func TestToDiagnosticsWithPath(t *testing.T) {
+	v := ValidateModuleModel(context.Background(), &NixernetesModuleModel{
+		Name:     types.StringValue("api"),
+		Image:    types.StringValue("nginx:latest"),
+		Replicas: types.Int64Value(200),
+	})
+	v.AddError("autoscaling.min_replicas", "Minimum replicas must be at least 1")
+	v.AddError("network_policies.ingress[0].ports", "Port must be between 1 and 65535")
+	v.AddError("quota", "At least one of cpu, memory or pods must be set")
+
+	diags := v.ToDiagnosticsWithPath(moduleFieldPaths)
+	want := []path.Path{
+		path.Root("replicas"),
+		path.Root("autoscaling").AtName("min_replicas"),
+		path.Root("network_policies").AtName("ingress"),
+		path.Empty(),
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("Expected %d diagnostics, got %v", len(want), diags)
+	}
+	for i, d := range diags {
+		got := path.Empty()
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			got = withPath.Path()
+		}
+		if !got.Equal(want[i]) {
+			t.Errorf("Diagnostic %q: expected path %s, got %s", d.Summary(), want[i], got)
+		}
+	}
+}
+
+func TestValidateConfigModelSize(t *testing.T) {
+	model := &NixernetesConfigModel{
+		Name:          types.StringValue("web"),
+		Configuration: types.StringValue("{ services.nginx.enable = true; }"),
+		Environment:   types.StringNull(),
+	}
+
+	if v := ValidateConfigModel(context.Background(), model, nil, defaultMaxConfigSize); v.HasErrors() {
+		t.Errorf("Unexpected errors: %v", v.Errors)
+	}
+
+	v := ValidateConfigModel(context.Background(), model, nil, 16)
+	if len(v.Errors) != 1 || v.Errors[0].Field != "configuration" {
+		t.Fatalf("Expected one configuration error, got %v", v.Errors)
+	}
+	if !strings.Contains(v.Errors[0].Message, "maximum configuration size of 16 bytes") {
+		t.Errorf("Expected error to state the limit, got %q", v.Errors[0].Message)
+	}
+}
+
+func TestMaxConfigSize(t *testing.T) {
+	if got := maxConfigSize(nil); got != defaultMaxConfigSize {
+		t.Errorf("Expected default limit without a client, got %d", got)
+	}
+	if got := maxConfigSize(&NixernetesClient{}); got != defaultMaxConfigSize {
+		t.Errorf("Expected default limit when none is advertised, got %d", got)
+	}
+	if got := maxConfigSize(&NixernetesClient{MaxConfigSize: 4096}); got != 4096 {
+		t.Errorf("Expected advertised limit 4096, got %d", got)
+	}
+}
+
+func TestValidateConfigModelServerSchema(t *testing.T) {
+	schema, err := compileConfigSchema(map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"services"},
+		"properties": map[string]interface{}{
+			"services": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"port": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 65535},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		configuration string
+		wantErrors    []string
+	}{
+		{"valid", `{"services": {"port": 8080}}`, nil},
+		{"wrong type", `{"services": {"port": "http"}}`, []string{"/services/port: "}},
+		{"out of range", `{"services": {"port": 70000}}`, []string{"/services/port: "}},
+		{"missing property", `{"other": {}}`, []string{"/: "}},
+		{"nix is not checked", `{ services.nginx.enable = true; }`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := ValidateConfigModel(context.Background(), &NixernetesConfigModel{
+				Name:          types.StringValue("web"),
+				Configuration: types.StringValue(tt.configuration),
+				Environment:   types.StringNull(),
+			}, schema, defaultMaxConfigSize)
+
+			if len(v.Errors) != len(tt.wantErrors) {
+				t.Fatalf("Expected %d errors, got %v", len(tt.wantErrors), v.Errors)
+			}
+			for i, want := range tt.wantErrors {
+				if v.Errors[i].Field != "configuration" || !strings.HasPrefix(v.Errors[i].Message, want) {
+					t.Errorf("Expected configuration error starting with %q, got %+v", want, v.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompileConfigSchemaInvalid(t *testing.T) {
+	if _, err := compileConfigSchema(map[string]interface{}{"type": 42}); err == nil {
+		t.Error("Expected an invalid schema to be rejected")
+	}
+}
+
 func TestValidateModuleModel(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -137,6 +303,64 @@ func TestValidateModuleModel(t *testing.T) {
 			wantError: true,
 			errorMsg:  "Container image is required",
 		},
+		{
+			name: "local registry image in production",
+			model: &NixernetesModuleModel{
+				Name:        types.StringValue("api"),
+				Image:       types.StringValue("localhost:5000/api:dev"),
+				Environment: types.StringValue("production"),
+			},
+			wantError: true,
+			errorMsg:  "not reachable in production",
+		},
+		{
+			name: "local registry image in mixed-case production",
+			model: &NixernetesModuleModel{
+				Name:        types.StringValue("api"),
+				Image:       types.StringValue("localhost:5000/api:dev"),
+				Environment: types.StringValue("Production"),
+			},
+			wantError: true,
+			errorMsg:  "not reachable in production",
+		},
+		{
+			name: "local registry image in development",
+			model: &NixernetesModuleModel{
+				Name:        types.StringValue("api"),
+				Image:       types.StringValue("localhost:5000/api:dev"),
+				Environment: types.StringValue("development"),
+			},
+			wantError: false,
+		},
+		{
+			name: "shared registry image in production",
+			model: &NixernetesModuleModel{
+				Name:        types.StringValue("api"),
+				Image:       types.StringValue("registry.example.com/api:1.0"),
+				Environment: types.StringValue("production"),
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid project ID",
+			model: &NixernetesModuleModel{
+				Name:      types.StringValue("api"),
+				Image:     types.StringValue("nginx:latest"),
+				ProjectID: types.StringValue("../projects"),
+			},
+			wantError: true,
+			errorMsg:  "Project ID must be",
+		},
+		{
+			name: "invalid module environment",
+			model: &NixernetesModuleModel{
+				Name:        types.StringValue("api"),
+				Image:       types.StringValue("nginx:latest"),
+				Environment: types.StringValue("testing"),
+			},
+			wantError: true,
+			errorMsg:  "Environment must be",
+		},
 		{
 			name: "invalid image with shell characters",
 			model: &NixernetesModuleModel{
@@ -166,6 +390,73 @@ func TestValidateModuleModel(t *testing.T) {
 			wantError: true,
 			errorMsg:  "Replicas cannot exceed 100",
 		},
+		{
+			name: "valid service account",
+			model: &NixernetesModuleModel{
+				Name:           types.StringValue("api"),
+				Image:          types.StringValue("nginx:latest"),
+				ServiceAccount: types.StringValue("api-reader.payments"),
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid service account",
+			model: &NixernetesModuleModel{
+				Name:           types.StringValue("api"),
+				Image:          types.StringValue("nginx:latest"),
+				ServiceAccount: types.StringValue("API_Reader"),
+			},
+			wantError: true,
+			errorMsg:  "Service account must be a DNS-1123 subdomain",
+		},
+		{
+			name: "valid termination grace period",
+			model: &NixernetesModuleModel{
+				Name:                          types.StringValue("db"),
+				Image:                         types.StringValue("postgres:16"),
+				TerminationGracePeriodSeconds: types.Int64Value(300),
+			},
+			wantError: false,
+		},
+		{
+			name: "negative termination grace period",
+			model: &NixernetesModuleModel{
+				Name:                          types.StringValue("db"),
+				Image:                         types.StringValue("postgres:16"),
+				TerminationGracePeriodSeconds: types.Int64Value(-1),
+			},
+			wantError: true,
+			errorMsg:  "Termination grace period must be between 0 and 3600 seconds",
+		},
+		{
+			name: "excessive termination grace period",
+			model: &NixernetesModuleModel{
+				Name:                          types.StringValue("db"),
+				Image:                         types.StringValue("postgres:16"),
+				TerminationGracePeriodSeconds: types.Int64Value(86400),
+			},
+			wantError: true,
+			errorMsg:  "Termination grace period must be between 0 and 3600 seconds",
+		},
+		{
+			name: "valid platform",
+			model: &NixernetesModuleModel{
+				Name:     types.StringValue("api"),
+				Image:    types.StringValue("nginx:latest"),
+				Platform: types.StringValue("linux/arm64"),
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid platform",
+			model: &NixernetesModuleModel{
+				Name:     types.StringValue("api"),
+				Image:    types.StringValue("nginx:latest"),
+				Platform: types.StringValue("linux/386"),
+			},
+			wantError: true,
+			errorMsg:  "Platform must be one of linux/amd64, linux/arm64",
+		},
 		{
 			name: "invalid namespace",
 			model: &NixernetesModuleModel{
@@ -185,6 +476,222 @@ func TestValidateModuleModel(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "valid volumes and mounts",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("db"),
+				Image: types.StringValue("postgres:16"),
+				Volumes: []NixernetesVolumeModel{
+					{Name: types.StringValue("data"), Type: types.StringValue("pvc"), Size: types.StringValue("10Gi")},
+					{Name: types.StringValue("scratch"), Type: types.StringValue("emptyDir"), Size: types.StringNull()},
+				},
+				VolumeMounts: []NixernetesVolumeMountModel{
+					{Name: types.StringValue("data"), MountPath: types.StringValue("/var/lib/postgresql"), ReadOnly: types.BoolNull()},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid volume name",
+			model: &NixernetesModuleModel{
+				Name:    types.StringValue("db"),
+				Image:   types.StringValue("postgres:16"),
+				Volumes: []NixernetesVolumeModel{{Name: types.StringValue("Data_Dir"), Type: types.StringValue("pvc"), Size: types.StringNull()}},
+			},
+			wantError: true,
+			errorMsg:  "must be a valid DNS label",
+		},
+		{
+			name: "invalid volume type",
+			model: &NixernetesModuleModel{
+				Name:    types.StringValue("db"),
+				Image:   types.StringValue("postgres:16"),
+				Volumes: []NixernetesVolumeModel{{Name: types.StringValue("data"), Type: types.StringValue("hostPath"), Size: types.StringNull()}},
+			},
+			wantError: true,
+			errorMsg:  "Volume type must be",
+		},
+		{
+			name: "invalid volume size",
+			model: &NixernetesModuleModel{
+				Name:    types.StringValue("db"),
+				Image:   types.StringValue("postgres:16"),
+				Volumes: []NixernetesVolumeModel{{Name: types.StringValue("data"), Type: types.StringValue("pvc"), Size: types.StringValue("ten gigs")}},
+			},
+			wantError: true,
+			errorMsg:  "must be a Kubernetes quantity",
+		},
+		{
+			name: "relative mount path",
+			model: &NixernetesModuleModel{
+				Name:    types.StringValue("db"),
+				Image:   types.StringValue("postgres:16"),
+				Volumes: []NixernetesVolumeModel{{Name: types.StringValue("data"), Type: types.StringValue("pvc"), Size: types.StringNull()}},
+				VolumeMounts: []NixernetesVolumeMountModel{
+					{Name: types.StringValue("data"), MountPath: types.StringValue("var/lib/data"), ReadOnly: types.BoolNull()},
+				},
+			},
+			wantError: true,
+			errorMsg:  "must be an absolute path",
+		},
+		{
+			name: "mount of undefined volume",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("db"),
+				Image: types.StringValue("postgres:16"),
+				VolumeMounts: []NixernetesVolumeMountModel{
+					{Name: types.StringValue("data"), MountPath: types.StringValue("/data"), ReadOnly: types.BoolNull()},
+				},
+			},
+			wantError: true,
+			errorMsg:  "does not reference a defined volume",
+		},
+		{
+			name: "valid autoscaling",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				Autoscaling: &NixernetesAutoscalingModel{
+					MinReplicas:          types.Int64Value(2),
+					MaxReplicas:          types.Int64Value(10),
+					TargetCPUUtilization: types.Int64Value(70),
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "autoscaling min above max",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				Autoscaling: &NixernetesAutoscalingModel{
+					MinReplicas:          types.Int64Value(5),
+					MaxReplicas:          types.Int64Value(3),
+					TargetCPUUtilization: types.Int64Null(),
+				},
+			},
+			wantError: true,
+			errorMsg:  "cannot exceed maximum replicas",
+		},
+		{
+			name: "autoscaling zero min",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				Autoscaling: &NixernetesAutoscalingModel{
+					MinReplicas:          types.Int64Value(0),
+					MaxReplicas:          types.Int64Value(3),
+					TargetCPUUtilization: types.Int64Null(),
+				},
+			},
+			wantError: true,
+			errorMsg:  "at least 1",
+		},
+		{
+			name: "autoscaling target out of range",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				Autoscaling: &NixernetesAutoscalingModel{
+					MinReplicas:          types.Int64Value(1),
+					MaxReplicas:          types.Int64Value(3),
+					TargetCPUUtilization: types.Int64Value(150),
+				},
+			},
+			wantError: true,
+			errorMsg:  "between 1 and 100",
+		},
+		{
+			name: "valid ready settings",
+			model: &NixernetesModuleModel{
+				Name:           types.StringValue("api"),
+				Image:          types.StringValue("nginx:latest"),
+				ReadyTimeout:   types.StringValue("5m"),
+				OnReadyTimeout: types.StringValue("taint"),
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid ready timeout",
+			model: &NixernetesModuleModel{
+				Name:         types.StringValue("api"),
+				Image:        types.StringValue("nginx:latest"),
+				ReadyTimeout: types.StringValue("five minutes"),
+			},
+			wantError: true,
+			errorMsg:  "positive duration",
+		},
+		{
+			name: "invalid on ready timeout",
+			model: &NixernetesModuleModel{
+				Name:           types.StringValue("api"),
+				Image:          types.StringValue("nginx:latest"),
+				OnReadyTimeout: types.StringValue("ignore"),
+			},
+			wantError: true,
+			errorMsg:  "'fail', 'taint', or 'continue'",
+		},
+		{
+			name: "valid network policies",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				NetworkPolicies: &NixernetesNetworkPoliciesModel{
+					Ingress: []NixernetesNetworkPolicyRuleModel{{
+						Ports:    []types.Int64{types.Int64Value(8080)},
+						Protocol: types.StringValue("TCP"),
+						CIDRs:    []types.String{types.StringValue("10.0.0.0/8")},
+					}},
+					Egress: []NixernetesNetworkPolicyRuleModel{{
+						Ports:    []types.Int64{types.Int64Value(53)},
+						Protocol: types.StringValue("UDP"),
+					}},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid network policy cidr",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				NetworkPolicies: &NixernetesNetworkPoliciesModel{
+					Ingress: []NixernetesNetworkPolicyRuleModel{{
+						CIDRs: []types.String{types.StringValue("10.0.0.0")},
+					}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "valid CIDR",
+		},
+		{
+			name: "invalid network policy port",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				NetworkPolicies: &NixernetesNetworkPoliciesModel{
+					Egress: []NixernetesNetworkPolicyRuleModel{{
+						Ports: []types.Int64{types.Int64Value(70000)},
+					}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "between 1 and 65535",
+		},
+		{
+			name: "invalid network policy protocol",
+			model: &NixernetesModuleModel{
+				Name:  types.StringValue("api"),
+				Image: types.StringValue("nginx:latest"),
+				NetworkPolicies: &NixernetesNetworkPoliciesModel{
+					Ingress: []NixernetesNetworkPolicyRuleModel{{
+						Protocol: types.StringValue("ICMP"),
+					}},
+				},
+			},
+			wantError: true,
+			errorMsg:  "'TCP', 'UDP', or 'SCTP'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -240,6 +747,15 @@ func TestValidateProjectModel(t *testing.T) {
 			wantError: true,
 			errorMsg:  "Description cannot exceed 1000",
 		},
+		{
+			name: "invalid default namespace",
+			model: &NixernetesProjectModel{
+				Name:             types.StringValue("shop"),
+				DefaultNamespace: types.StringValue("Team_Shop"),
+			},
+			wantError: true,
+			errorMsg:  "Default namespace must be",
+		},
 	}
 
 	for _, tt := range tests {
@@ -255,6 +771,193 @@ func TestValidateProjectModel(t *testing.T) {
 	}
 }
 
+func TestValidateResourceQuotaModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		model     *NixernetesResourceQuotaModel
+		wantError bool
+	}{
+		{
+			name: "valid quota",
+			model: &NixernetesResourceQuotaModel{
+				Namespace: types.StringValue("team-payments"),
+				CPU:       types.StringValue("4"),
+				Memory:    types.StringValue("8Gi"),
+				Pods:      types.Int64Value(20),
+			},
+			wantError: false,
+		},
+		{
+			name: "pods only",
+			model: &NixernetesResourceQuotaModel{
+				Namespace: types.StringValue("default"),
+				CPU:       types.StringNull(),
+				Memory:    types.StringNull(),
+				Pods:      types.Int64Value(10),
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid namespace",
+			model: &NixernetesResourceQuotaModel{
+				Namespace: types.StringValue("Team_Payments"),
+				CPU:       types.StringValue("4"),
+				Memory:    types.StringNull(),
+				Pods:      types.Int64Null(),
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid cpu quantity",
+			model: &NixernetesResourceQuotaModel{
+				Namespace: types.StringValue("default"),
+				CPU:       types.StringValue("four"),
+				Memory:    types.StringNull(),
+				Pods:      types.Int64Null(),
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid memory quantity",
+			model: &NixernetesResourceQuotaModel{
+				Namespace: types.StringValue("default"),
+				CPU:       types.StringNull(),
+				Memory:    types.StringValue("8 GB"),
+				Pods:      types.Int64Null(),
+			},
+			wantError: true,
+		},
+		{
+			name: "negative pods",
+			model: &NixernetesResourceQuotaModel{
+				Namespace: types.StringValue("default"),
+				CPU:       types.StringNull(),
+				Memory:    types.StringNull(),
+				Pods:      types.Int64Value(-1),
+			},
+			wantError: true,
+		},
+		{
+			name: "no limits",
+			model: &NixernetesResourceQuotaModel{
+				Namespace: types.StringValue("default"),
+				CPU:       types.StringNull(),
+				Memory:    types.StringNull(),
+				Pods:      types.Int64Null(),
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := ValidateResourceQuotaModel(context.Background(), tt.model)
+			if tt.wantError && !v.HasErrors() {
+				t.Error("Expected validation error but got none")
+			}
+			if !tt.wantError && v.HasErrors() {
+				t.Errorf("Unexpected validation errors: %v", v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateSecretModel(t *testing.T) {
+	data := func(keys ...string) types.Map {
+		elements := map[string]attr.Value{}
+		for _, key := range keys {
+			elements[key] = types.StringValue("value")
+		}
+		return types.MapValueMust(types.StringType, elements)
+	}
+
+	tests := []struct {
+		name      string
+		model     *NixernetesSecretModel
+		wantError bool
+	}{
+		{
+			name: "valid secret",
+			model: &NixernetesSecretModel{
+				Name:      types.StringValue("db-credentials"),
+				Namespace: types.StringValue("default"),
+				Data:      data("username", "DB_PASSWORD", "tls.crt"),
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown values",
+			model: &NixernetesSecretModel{
+				Name:      types.StringUnknown(),
+				Namespace: types.StringUnknown(),
+				Data:      types.MapUnknown(types.StringType),
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid name",
+			model: &NixernetesSecretModel{
+				Name:      types.StringValue("DB_Credentials"),
+				Namespace: types.StringValue("default"),
+				Data:      data("password"),
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid namespace",
+			model: &NixernetesSecretModel{
+				Name:      types.StringValue("db-credentials"),
+				Namespace: types.StringValue("-default"),
+				Data:      data("password"),
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid key",
+			model: &NixernetesSecretModel{
+				Name:      types.StringValue("db-credentials"),
+				Namespace: types.StringValue("default"),
+				Data:      data("db password"),
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := ValidateSecretModel(context.Background(), tt.model)
+			if tt.wantError && !v.HasErrors() {
+				t.Error("Expected validation error but got none")
+			}
+			if !tt.wantError && v.HasErrors() {
+				t.Errorf("Unexpected validation errors: %v", v.Errors)
+			}
+		})
+	}
+}
+
+func TestIsQuotaExceeded(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"402", &HTTPError{StatusCode: 402, Message: "quota exceeded"}, true},
+		{"403 quota", &HTTPError{StatusCode: 403, Message: "pods \"api\" is forbidden: exceeded quota: compute"}, true},
+		{"403 other", &HTTPError{StatusCode: 403, Message: "Access denied"}, false},
+		{"500", &HTTPError{StatusCode: 500, Message: "exceeded quota"}, false},
+		{"non-HTTP error", context.DeadlineExceeded, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isQuotaExceeded(tt.err); got != tt.want {
+				t.Errorf("isQuotaExceeded(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsValidName(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -283,6 +986,72 @@ func TestIsValidName(t *testing.T) {
 	}
 }
 
+func TestIsValidID(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantValid bool
+	}{
+		{"prefixed", "proj-123", true},
+		{"uuid", "3f2504e0-4f89-11d3-9a0c-0305e82c3301", true},
+		{"underscore", "proj_1", true},
+		{"empty string", "", false},
+		{"starts with hyphen", "-proj", false},
+		{"contains slash", "proj/1", false},
+		{"contains space", "proj 1", false},
+		{"too long", strings.Repeat("a", 65), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isValidID(tt.input)
+			if got != tt.wantValid {
+				t.Errorf("isValidID(%q) = %v, want %v", tt.input, got, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestCheckNixSyntax(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantValid bool
+	}{
+		{"attribute set", "{ services.nginx.enable = true; }", true},
+		{"list and parens", "{ xs = [ (f 1) 2 ]; }", true},
+		{"line comment", "{ a = 1; # }\n}", true},
+		{"block comment", "{ /* ] */ a = 1; }", true},
+		{"string with escape", `{ a = "\"}"; }`, true},
+		{"indented string", "{ a = ''\n  }\n''; }", true},
+		{"unclosed brace", "{ a = 1;", false},
+		{"mismatched bracket", "{ a = [ 1 }; ]", false},
+		{"stray closer", "a = 1; }", false},
+		{"unterminated string", `{ a = "x; }`, false},
+		{"unterminated comment", "{ /* a = 1; }", false},
+		{"unterminated indented string", "{ a = '' x; }", false},
+		{"module function", "{ pkgs, ... }:\n{\n  environment.systemPackages = [ pkgs.git ];\n}", true},
+		{"empty", "", false},
+		{"whitespace only", "  \n\t", false},
+		{"comments only", "# services.nginx.enable = true;\n/* { } */", false},
+		{"no attribute set", "services.nginx.enable = true;", false},
+		{"braces only in a string", `"{ a = 1; }"`, false},
+		{"unbalanced parens", "{ a = (f 1; }", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkNixSyntax(tt.input)
+			if (err == nil) != tt.wantValid {
+				t.Errorf("checkNixSyntax(%q) = %v, want valid %v", tt.input, err, tt.wantValid)
+			}
+			if err != nil && !strings.Contains(err.Error(), "does not appear to be a valid Nix expression") {
+				t.Errorf("checkNixSyntax(%q) = %v, want a valid Nix expression error", tt.input, err)
+			}
+		})
+	}
+}
+
 func TestIsValidEnvironment(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -324,6 +1093,9 @@ func TestIsValidImage(t *testing.T) {
 		{"with shell pipe", "nginx|bash", false},
 		{"with backtick", "nginx`ls`", false},
 		{"too many colons", "registry:5000:80/image:tag", false},
+		{"image with digest", "nginx@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", true},
+		{"uppercase repository", "Nginx:latest", false},
+		{"empty tag", "nginx:", false},
 	}
 
 	for _, tt := range tests {
@@ -336,6 +1108,105 @@ func TestIsValidImage(t *testing.T) {
 	}
 }
 
+func TestIsLocalRegistry(t *testing.T) {
+	tests := []struct {
+		registry string
+		want     bool
+	}{
+		{"localhost", true},
+		{"localhost:5000", true},
+		{"127.0.0.1:5000", true},
+		{"registry.local", true},
+		{"Builder.LOCAL:8080", true},
+		{"", false},
+		{"docker.io", false},
+		{"registry.example.com:5000", false},
+		{"local.example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLocalRegistry(tt.registry); got != tt.want {
+			t.Errorf("isLocalRegistry(%q) = %v, want %v", tt.registry, got, tt.want)
+		}
+	}
+}
+
+func TestIsLocalEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     bool
+	}{
+		{"https://localhost:8080", true},
+		{"http://LOCALHOST", true},
+		{"http://127.0.0.1:8080/api", true},
+		{"http://127.0.1.1", true},
+		{"http://[::1]:8080", true},
+		{"https://nixernetes.example.com", false},
+		{"https://localhost.example.com", false},
+		{"http://10.0.0.5:8080", false},
+		{"", false},
+		{"://bad", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLocalEndpoint(tt.endpoint); got != tt.want {
+			t.Errorf("isLocalEndpoint(%q) = %v, want %v", tt.endpoint, got, tt.want)
+		}
+	}
+}
+
+func TestIsValidHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"nixernetes.internal.example.com", true},
+		{"Build-Host.corp", true},
+		{"10.0.0.5", true},
+		{"::1", true},
+		{"https://nixernetes.example.com", false},
+		{"nixernetes.example.com:8443", false},
+		{"host/path", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidHost(tt.host); got != tt.want {
+			t.Errorf("isValidHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalizeImage(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"nginx", "docker.io/library/nginx:latest"},
+		{"nginx:1.25", "docker.io/library/nginx:1.25"},
+		{"library/nginx", "docker.io/library/nginx:latest"},
+		{"index.docker.io/nginx", "docker.io/library/nginx:latest"},
+		{"bitnami/redis", "docker.io/bitnami/redis:latest"},
+		{"localhost:5000/myimage:tag", "localhost:5000/myimage:tag"},
+		{"myregistry.azurecr.io/api", "myregistry.azurecr.io/api:latest"},
+		{"nginx@" + digest, "docker.io/library/nginx@" + digest},
+		{"nginx:1.25@" + digest, "docker.io/library/nginx:1.25@" + digest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := canonicalizeImage(tt.input)
+			if err != nil {
+				t.Fatalf("canonicalizeImage(%q) returned error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("canonicalizeImage(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsValidNamespace(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -346,8 +1217,14 @@ func TestIsValidNamespace(t *testing.T) {
 		{"kube-system", "kube-system", true},
 		{"my-namespace", "my-namespace", true},
 		{"a", "a", true},
-		{"63 chars", string(make([]byte, 63)), false}, // All nulls - invalid
-		{"64 chars", string(make([]byte, 64)), false},
+		{"63 nulls", string(make([]byte, 63)), false},
+		{"64 nulls", string(make([]byte, 64)), false},
+		{"63 chars", strings.Repeat("a", 63), true},
+		{"64 chars", strings.Repeat("a", 64), false},
+		{"digits only", "123", true},
+		{"mid-string hyphens", "team-a--prod-1", true},
+		{"uppercase start", "Default", false},
+		{"non-ascii", "namespacé", false},
 		{"UPPERCASE", "UPPERCASE", false},
 		{"with_underscore", "with_underscore", false},
 		{"starts with dash", "-namespace", false},
@@ -365,6 +1242,61 @@ func TestIsValidNamespace(t *testing.T) {
 	}
 }
 
+func TestIsValidDNSLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantValid bool
+	}{
+		{"simple", "data", true},
+		{"with hyphen", "app-data", true},
+		{"digits only", "123", true},
+		{"63 chars", strings.Repeat("a", 63), true},
+		{"64 chars", strings.Repeat("a", 64), false},
+		{"uppercase", "Data", false},
+		{"underscore", "app_data", false},
+		{"starts with dash", "-data", false},
+		{"ends with dash", "data-", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isValidDNSLabel(tt.input)
+			if got != tt.wantValid {
+				t.Errorf("isValidDNSLabel(%q) = %v, want %v", tt.input, got, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestIsValidDNSSubdomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantValid bool
+	}{
+		{"single label", "default", true},
+		{"dotted", "api-reader.payments", true},
+		{"253 chars", strings.Repeat(strings.Repeat("a", 62)+".", 4) + "a", true},
+		{"254 chars", strings.Repeat(strings.Repeat("a", 62)+".", 4) + "aa", false},
+		{"label too long", strings.Repeat("a", 64) + ".svc", false},
+		{"uppercase", "API", false},
+		{"empty label", "api..reader", false},
+		{"trailing dot", "api.", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := isValidDNSSubdomain(tt.input)
+			if got != tt.wantValid {
+				t.Errorf("isValidDNSSubdomain(%q) = %v, want %v", tt.input, got, tt.wantValid)
+			}
+		})
+	}
+}
+
 func TestValidateHTTPError(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -387,6 +1319,16 @@ func TestValidateHTTPError(t *testing.T) {
 			err:           &HTTPError{StatusCode: 404, Message: "Not found"},
 			wantRetryable: false,
 		},
+		{
+			name:          "402 quota exceeded",
+			err:           &HTTPError{StatusCode: 402, Message: "Quota exceeded"},
+			wantRetryable: false,
+		},
+		{
+			name:          "422 unprocessable",
+			err:           &HTTPError{StatusCode: 422, Message: "replicas: must not exceed 50"},
+			wantRetryable: false,
+		},
 		{
 			name:          "429 rate limited",
 			err:           &HTTPError{StatusCode: 429, Message: "Rate limited"},
@@ -397,6 +1339,11 @@ func TestValidateHTTPError(t *testing.T) {
 			err:           &HTTPError{StatusCode: 500, Message: "Internal error"},
 			wantRetryable: true,
 		},
+		{
+			name:          "503 maintenance",
+			err:           &HTTPError{StatusCode: 503, Message: "Down for maintenance", Maintenance: true},
+			wantRetryable: true,
+		},
 		{
 			name:          "503 unavailable",
 			err:           &HTTPError{StatusCode: 503, Message: "Service unavailable"},